@@ -0,0 +1,329 @@
+// easyssh 是基于 easyssh 包的多主机批量运维命令行工具
+//
+// 用法:
+//
+//	easyssh <命令> [选项] [参数...]
+//
+// 支持的命令:
+//   - exec:     在主机上执行命令
+//   - ping:     测试主机连通性
+//   - upload:   上传本地文件到主机
+//   - download: 从主机下载文件
+//   - facts:    采集主机基础信息
+//   - play:     执行 JSON 格式的任务剧本
+//   - shell:    在单台主机上打开交互式 shell
+//   - sync:     将本地目录增量同步到主机
+//
+// 退出码:
+//   - 0: 所有主机操作成功
+//   - 1: 至少一台主机操作失败
+//   - 2: 参数错误或主机清单解析失败
+//   - 3: 执行过程中出现无法继续的错误（如剧本加载失败、本地目录无法读取）
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gitee.com/MM-Q/go-kit/easyssh"
+)
+
+// 退出码定义
+const (
+	exitOK     = 0 // 所有主机操作成功
+	exitFailed = 1 // 至少一台主机操作失败
+	exitUsage  = 2 // 参数错误或主机清单解析失败
+	exitError  = 3 // 执行过程中出现无法继续的错误
+)
+
+// 默认主机清单文件，可通过环境变量 EASYSSH_HOSTS 覆盖
+const defaultHostsFile = "hosts.txt"
+
+// options 所有子命令共用的命令行选项
+type options struct {
+	hostsFile string              // 主机清单文件路径
+	parallel  int                 // 并发数
+	timeout   time.Duration       // 连接超时时间
+	hosts     string              // 主机筛选模式，多个用逗号分隔
+	output    string              // 输出格式：text 或 json
+	level     string              // ping 检查级别
+	check     bool                // play 检查模式，不实际执行
+	keepGoing bool                // play 步骤失败时继续执行
+	sync      easyssh.SyncOptions // sync 同步选项
+}
+
+// command 子命令定义
+type command struct {
+	name  string                                                                 // 命令名称
+	args  string                                                                 // 位置参数说明
+	desc  string                                                                 // 命令描述
+	nargs int                                                                    // 位置参数个数
+	flags func(fset *flag.FlagSet, opts *options)                                // 注册命令专用选项，可为 nil
+	run   func(e *easyssh.EasySSH, p *printer, opts *options, args []string) int // 命令执行函数
+}
+
+// commands 支持的子命令列表
+var commands = []command{
+	{name: "exec", args: "<命令>", desc: "在主机上执行命令", nargs: 1, run: runExec},
+	{name: "ping", args: "", desc: "测试主机连通性", nargs: 0, flags: pingFlags, run: runPing},
+	{name: "upload", args: "<本地文件> <远程路径>", desc: "上传本地文件到主机", nargs: 2, run: runUpload},
+	{name: "download", args: "<远程文件> <本地目录>", desc: "从主机下载文件", nargs: 2, run: runDownload},
+	{name: "facts", args: "", desc: "采集主机基础信息", nargs: 0, run: runFacts},
+	{name: "play", args: "<剧本文件>", desc: "执行 JSON 格式的任务剧本", nargs: 1, flags: playFlags, run: runPlay},
+	{name: "shell", args: "<主机模式>", desc: "在单台主机上打开交互式 shell", nargs: 1, run: runShell},
+	{name: "sync", args: "<本地目录> <远程目录>", desc: "将本地目录增量同步到主机", nargs: 2, flags: syncFlags, run: runSync},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run 解析参数并执行子命令
+//
+// 参数:
+//   - args: 命令行参数（不含程序名）
+//   - stdout: 标准输出
+//   - stderr: 标准错误输出
+//
+// 返回:
+//   - int: 进程退出码
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "未知命令: %s\n\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+
+	opts, fset := newFlagSet(cmd, stderr)
+	if err := fset.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	// exec 命令允许不加引号直接传入多个单词
+	rest := fset.Args()
+	if cmd.name == "exec" && len(rest) > 1 {
+		rest = []string{strings.Join(rest, " ")}
+	}
+	if len(rest) != cmd.nargs {
+		_, _ = fmt.Fprintf(stderr, "参数错误: %s 需要 %d 个参数，实际为 %d 个\n", cmd.name, cmd.nargs, len(rest))
+		fset.Usage()
+		return exitUsage
+	}
+
+	p, err := newPrinter(opts.output, stdout, stderr)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	e := easyssh.New(opts.hostsFile, opts.timeout, false, false)
+	e.Parallel = opts.parallel
+	e.Patterns = splitPatterns(opts.hosts)
+	if opts.level != "" {
+		if e.PingLevel, err = easyssh.ParsePingLevel(opts.level); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+	}
+
+	// 提前加载主机清单，清单错误与主机执行失败使用不同的退出码
+	if _, err := e.SelectHosts(); err != nil {
+		_, _ = fmt.Fprintf(stderr, "加载主机清单失败: %v\n", err)
+		return exitUsage
+	}
+
+	return cmd.run(e, p, opts, rest)
+}
+
+// newFlagSet 创建子命令的参数解析器并注册通用选项
+//
+// 参数:
+//   - cmd: 子命令定义
+//   - stderr: 帮助信息输出位置
+//
+// 返回:
+//   - *options: 解析结果存放位置
+//   - *flag.FlagSet: 参数解析器
+func newFlagSet(cmd command, stderr io.Writer) (*options, *flag.FlagSet) {
+	opts := &options{}
+	fset := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fset.SetOutput(stderr)
+
+	hostsFile := defaultHostsFile
+	if env := os.Getenv("EASYSSH_HOSTS"); env != "" {
+		hostsFile = env
+	}
+
+	fset.StringVar(&opts.hostsFile, "f", hostsFile, "主机清单文件路径（也可通过 EASYSSH_HOSTS 环境变量设置）")
+	fset.IntVar(&opts.parallel, "p", 10, "并发操作的主机数量")
+	fset.DurationVar(&opts.timeout, "t", 5*time.Second, "连接超时时间")
+	fset.StringVar(&opts.hosts, "H", "", "主机筛选模式，支持通配符，多个用逗号分隔")
+	fset.StringVar(&opts.output, "o", "text", "输出格式：text 或 json")
+	if cmd.flags != nil {
+		cmd.flags(fset, opts)
+	}
+
+	fset.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "用法: easyssh %s [选项] %s\n\n%s\n\n选项:\n", cmd.name, cmd.args, cmd.desc)
+		fset.PrintDefaults()
+	}
+	return opts, fset
+}
+
+// usage 打印总体帮助信息
+func usage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "用法: easyssh <命令> [选项] [参数...]")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "命令:")
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(w, "  %-10s %-26s %s\n", cmd.name, cmd.args, cmd.desc)
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "使用 \"easyssh <命令> -h\" 查看命令选项")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "退出码: 0 全部成功, 1 存在失败主机, 2 参数或主机清单错误, 3 执行出错")
+}
+
+// findCommand 根据名称查找子命令
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// splitPatterns 拆分逗号分隔的主机筛选模式，忽略空项
+func splitPatterns(s string) []string {
+	var patterns []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// runExec 执行 exec 命令
+func runExec(e *easyssh.EasySSH, p *printer, _ *options, args []string) int {
+	results, err := e.ExecRaw(args[0])
+	if err != nil {
+		return p.fatal(err, exitError)
+	}
+	return p.hostResults("EXEC "+args[0], results)
+}
+
+// pingFlags 注册 ping 命令专用选项
+func pingFlags(fset *flag.FlagSet, opts *options) {
+	fset.StringVar(&opts.level, "level", "tcp", "检查级别：tcp、banner、handshake、auth")
+}
+
+// runPing 执行 ping 命令
+func runPing(e *easyssh.EasySSH, p *printer, _ *options, _ []string) int {
+	results, err := e.PingHostsRaw()
+	if err != nil {
+		return p.fatal(err, exitError)
+	}
+	return p.pingResults(results)
+}
+
+// runUpload 执行 upload 命令
+func runUpload(e *easyssh.EasySSH, p *printer, _ *options, args []string) int {
+	results, err := e.UploadRaw(args[0], args[1])
+	if err != nil {
+		return p.fatal(err, exitError)
+	}
+	return p.hostResults(fmt.Sprintf("UPLOAD %s -> %s", args[0], args[1]), results)
+}
+
+// runDownload 执行 download 命令
+func runDownload(e *easyssh.EasySSH, p *printer, _ *options, args []string) int {
+	results, err := e.DownloadRaw(args[0], args[1])
+	if err != nil {
+		return p.fatal(err, exitError)
+	}
+	return p.hostResults(fmt.Sprintf("DOWNLOAD %s -> %s", args[0], args[1]), results)
+}
+
+// runFacts 执行 facts 命令
+func runFacts(e *easyssh.EasySSH, p *printer, _ *options, _ []string) int {
+	facts, err := e.GatherFacts()
+	if err != nil {
+		return p.fatal(err, exitError)
+	}
+	return p.factsResults(facts)
+}
+
+// playFlags 注册 play 命令专用选项
+func playFlags(fset *flag.FlagSet, opts *options) {
+	fset.BoolVar(&opts.check, "check", false, "检查模式：只显示执行计划，不连接主机")
+	fset.BoolVar(&opts.keepGoing, "continue-on-error", false, "步骤失败时继续执行后续步骤")
+}
+
+// runPlay 执行 play 命令
+func runPlay(e *easyssh.EasySSH, p *printer, opts *options, args []string) int {
+	pb, err := easyssh.LoadPlaybook(args[0])
+	if err != nil {
+		return p.fatal(err, exitError)
+	}
+
+	// 文本格式使用库自带的逐步骤输出，便于实时查看进度
+	if !p.json {
+		e.ShowFormat = true
+		e.ShowOutput = true
+	}
+
+	report, err := e.RunPlaybook(pb, easyssh.PlaybookOptions{Check: opts.check, ContinueOnError: opts.keepGoing})
+	if err != nil {
+		return p.fatal(err, exitError)
+	}
+	return p.playbookReport(report)
+}
+
+// runShell 执行 shell 命令
+func runShell(e *easyssh.EasySSH, p *printer, _ *options, args []string) int {
+	if err := e.Shell(args[0]); err != nil {
+		_, _ = fmt.Fprintf(p.errW, "错误: %v\n", err)
+		return exitFailed
+	}
+	return exitOK
+}
+
+// syncFlags 注册 sync 命令专用选项
+func syncFlags(fset *flag.FlagSet, opts *options) {
+	fset.BoolVar(&opts.sync.Delete, "delete", false, "删除远程目录中本地不存在的文件")
+	fset.BoolVar(&opts.sync.Checksum, "checksum", false, "总是比较文件哈希，不信任大小和修改时间")
+	fset.BoolVar(&opts.sync.DryRun, "dry-run", false, "只显示需要的变更，不实际传输或删除")
+}
+
+// runSync 执行 sync 命令
+func runSync(e *easyssh.EasySSH, p *printer, opts *options, args []string) int {
+	results, err := e.SyncRaw(args[0], args[1], opts.sync)
+	if err != nil {
+		return p.fatal(err, exitError)
+	}
+
+	description := fmt.Sprintf("SYNC %s -> %s", args[0], args[1])
+	if opts.sync.DryRun {
+		description += " [DRY RUN]"
+	}
+	return p.syncResults(description, results)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestFile 在临时目录中创建测试文件
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to create %s: %v", name, err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	// 端口 1 没有服务监听，连接立即被拒绝
+	hosts := writeTestFile(t, "hosts.txt", "127.0.0.1 1 root pw\n")
+	playbook := writeTestFile(t, "play.json", `{"name":"deploy","steps":[{"run":"uptime"}]}`)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string // 标准输出中应包含的内容
+		wantStderr string // 标准错误中应包含的内容
+	}{
+		{name: "No args", args: nil, wantCode: exitUsage, wantStderr: "用法: easyssh"},
+		{name: "Help", args: []string{"help"}, wantCode: exitOK, wantStderr: "命令:"},
+		{name: "Command help", args: []string{"ping", "-h"}, wantCode: exitOK, wantStderr: "-level"},
+		{name: "Unknown command", args: []string{"reboot"}, wantCode: exitUsage, wantStderr: "未知命令: reboot"},
+		{name: "Unknown flag", args: []string{"ping", "-x"}, wantCode: exitUsage, wantStderr: "-x"},
+		{name: "Missing args", args: []string{"upload", "-f", hosts, "a"}, wantCode: exitUsage, wantStderr: "upload 需要 2 个参数，实际为 1 个"},
+		{name: "Extra args", args: []string{"ping", "-f", hosts, "x"}, wantCode: exitUsage, wantStderr: "ping 需要 0 个参数"},
+		{name: "Bad format", args: []string{"ping", "-f", hosts, "-o", "yaml"}, wantCode: exitUsage, wantStderr: "不支持的输出格式: yaml"},
+		{name: "Bad level", args: []string{"ping", "-f", hosts, "-level", "deep"}, wantCode: exitUsage},
+		{name: "Missing hosts file", args: []string{"ping", "-f", filepath.Join(t.TempDir(), "none.txt")}, wantCode: exitUsage, wantStderr: "加载主机清单失败"},
+		{name: "Bad host pattern", args: []string{"ping", "-f", hosts, "-H", "[a"}, wantCode: exitUsage, wantStderr: "加载主机清单失败"},
+		{name: "Host failed", args: []string{"exec", "-f", hosts, "-t", "2s", "uptime", "-a"}, wantCode: exitFailed, wantStdout: "==> EXEC uptime -a (1 hosts)"},
+		{name: "Playbook check", args: []string{"play", "-f", hosts, "-check", "-o", "json", playbook}, wantCode: exitOK, wantStdout: `"check": true`},
+		{name: "Missing playbook", args: []string{"play", "-f", hosts, filepath.Join(t.TempDir(), "none.json")}, wantCode: exitError, wantStderr: "读取剧本文件失败"},
+		{name: "Missing sync source", args: []string{"sync", "-f", hosts, "-o", "json", filepath.Join(t.TempDir(), "none"), "/opt"}, wantCode: exitError, wantStdout: `"error"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("run() = %d, want %d\nstdout: %s\nstderr: %s", code, tt.wantCode, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("stdout missing %q:\n%s", tt.wantStdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr missing %q:\n%s", tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	hosts := writeTestFile(t, "hosts.txt", "127.0.0.1 1 root pw\n")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"exec", "-f", hosts, "-o", "json", "uptime"}, &stdout, &stderr); code != exitFailed {
+		t.Fatalf("run() = %d, want %d\nstderr: %s", code, exitFailed, stderr.String())
+	}
+
+	var items []hostJSON
+	if err := json.Unmarshal(stdout.Bytes(), &items); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	if len(items) != 1 || items[0].Host != "127.0.0.1" || items[0].Port != 1 || items[0].Success || items[0].Error == "" {
+		t.Errorf("items = %+v", items)
+	}
+	// 连接失败时无法获取远程退出码
+	if items[0].ExitCode != -1 {
+		t.Errorf("ExitCode = %d, want -1", items[0].ExitCode)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("unexpected stderr in JSON mode: %s", stderr.String())
+	}
+}
+
+func TestSplitPatterns(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"web*", []string{"web*"}},
+		{" web* , ,db:2222,", []string{"web*", "db:2222"}},
+	}
+	for _, tt := range tests {
+		got := splitPatterns(tt.in)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("splitPatterns(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,471 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gitee.com/MM-Q/go-kit/easyssh"
+	"gitee.com/MM-Q/go-kit/utils"
+)
+
+// printer 按指定格式输出操作结果
+type printer struct {
+	json bool      // 是否以 JSON 格式输出
+	w    io.Writer // 结果输出位置
+	errW io.Writer // 错误信息输出位置（仅文本格式使用）
+}
+
+// hostJSON 单台主机操作结果的 JSON 表示
+type hostJSON struct {
+	Host       string  `json:"host"`
+	Port       int     `json:"port"`
+	Success    bool    `json:"success"`
+	Output     string  `json:"output,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	ExitCode   int     `json:"exit_code"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// pingJSON 单台主机连通性测试结果的 JSON 表示
+type pingJSON struct {
+	Host               string      `json:"host"`
+	Port               int         `json:"port"`
+	Connected          bool        `json:"connected"`
+	Level              string      `json:"level"`
+	LatencyMs          float64     `json:"latency_ms"`
+	FailedStage        string      `json:"failed_stage,omitempty"`
+	Stages             []stageJSON `json:"stages"`
+	ServerVersion      string      `json:"server_version,omitempty"`
+	HostKeyType        string      `json:"host_key_type,omitempty"`
+	HostKeyFingerprint string      `json:"host_key_fingerprint,omitempty"`
+	Error              string      `json:"error,omitempty"`
+}
+
+// stageJSON 单个检查阶段结果的 JSON 表示
+type stageJSON struct {
+	Name       string  `json:"name"`
+	DurationMs float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// factsJSON 单台主机基础信息的 JSON 表示
+type factsJSON struct {
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	Hostname  string `json:"hostname,omitempty"`
+	OS        string `json:"os,omitempty"`
+	Kernel    string `json:"kernel,omitempty"`
+	Arch      string `json:"arch,omitempty"`
+	CPUs      int    `json:"cpus,omitempty"`
+	MemTotal  int64  `json:"mem_total,omitempty"`
+	UptimeSec int64  `json:"uptime_sec,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// syncJSON 单台主机目录同步结果的 JSON 表示
+type syncJSON struct {
+	Host       string   `json:"host"`
+	Port       int      `json:"port"`
+	Success    bool     `json:"success"`
+	Added      []string `json:"added"`
+	Changed    []string `json:"changed"`
+	Deleted    []string `json:"deleted"`
+	Unchanged  int      `json:"unchanged"`
+	Error      string   `json:"error,omitempty"`
+	DurationMs float64  `json:"duration_ms"`
+}
+
+// newPrinter 创建结果输出器
+//
+// 参数:
+//   - format: 输出格式，支持 text 和 json
+//   - w: 结果输出位置
+//   - errW: 错误信息输出位置
+//
+// 返回:
+//   - *printer: 结果输出器
+//   - error: 格式不受支持时返回错误
+func newPrinter(format string, w, errW io.Writer) (*printer, error) {
+	switch strings.ToLower(format) {
+	case "text":
+		return &printer{w: w, errW: errW}, nil
+	case "json":
+		return &printer{json: true, w: w, errW: errW}, nil
+	default:
+		return nil, fmt.Errorf("不支持的输出格式: %s（可选 text、json）", format)
+	}
+}
+
+// fatal 输出无法继续执行的错误并返回指定的退出码
+//
+// 参数:
+//   - err: 错误信息
+//   - code: 进程退出码
+//
+// 返回:
+//   - int: 进程退出码（即 code）
+func (p *printer) fatal(err error, code int) int {
+	if p.json {
+		p.encode(map[string]string{"error": err.Error()})
+	} else {
+		_, _ = fmt.Fprintf(p.errW, "错误: %v\n", err)
+	}
+	return code
+}
+
+// hostResults 输出批量操作结果
+//
+// 参数:
+//   - description: 操作描述（仅文本格式使用）
+//   - results: 每台主机的操作结果
+//
+// 返回:
+//   - int: 进程退出码
+func (p *printer) hostResults(description string, results []easyssh.HostResult) int {
+	failed := 0
+	for _, r := range results {
+		if !r.Success {
+			failed++
+		}
+	}
+
+	if p.json {
+		items := make([]hostJSON, 0, len(results))
+		for _, r := range results {
+			items = append(items, hostJSON{
+				Host:       r.Host,
+				Port:       r.Port,
+				Success:    r.Success,
+				Output:     r.Output,
+				Error:      errString(r.Err),
+				ExitCode:   r.ExitCode,
+				DurationMs: millis(r.Duration),
+			})
+		}
+		p.encode(items)
+		return exitCode(failed)
+	}
+
+	p.header(description, len(results))
+	for _, r := range results {
+		label := fmt.Sprintf("%s:%d", r.Host, r.Port)
+		if r.Success {
+			_, _ = fmt.Fprintf(p.w, "%-20s : [ ✓ ok (%.2fms) ]\n", label, millis(r.Duration))
+		} else {
+			_, _ = fmt.Fprintf(p.w, "%-20s : [ ✗ failed ]\n", label)
+			if r.Err != nil {
+				_, _ = fmt.Fprintf(p.w, "    %v\n", r.Err)
+			}
+		}
+		if output := strings.TrimSpace(r.Output); output != "" {
+			p.indent(output)
+		}
+	}
+	p.footer(len(results), failed)
+	return exitCode(failed)
+}
+
+// pingResults 输出连通性测试结果
+//
+// 参数:
+//   - results: 每台主机的连通性测试结果
+//
+// 返回:
+//   - int: 进程退出码
+func (p *printer) pingResults(results []easyssh.PingResult) int {
+	failed := 0
+	for _, r := range results {
+		if !r.Connected {
+			failed++
+		}
+	}
+
+	if p.json {
+		items := make([]pingJSON, 0, len(results))
+		for _, r := range results {
+			stages := make([]stageJSON, 0, len(r.Stages))
+			for _, stage := range r.Stages {
+				stages = append(stages, stageJSON{Name: stage.Name, DurationMs: millis(stage.Duration), Error: errString(stage.Err)})
+			}
+			items = append(items, pingJSON{
+				Host:               r.Host,
+				Port:               r.Port,
+				Connected:          r.Connected,
+				Level:              r.Level.String(),
+				LatencyMs:          millis(r.Latency),
+				FailedStage:        r.FailedStage,
+				Stages:             stages,
+				ServerVersion:      r.ServerVersion,
+				HostKeyType:        r.HostKeyType,
+				HostKeyFingerprint: r.HostKeyFingerprint,
+				Error:              errString(r.Err),
+			})
+		}
+		p.encode(items)
+		return exitCode(failed)
+	}
+
+	level := easyssh.PingTCP
+	if len(results) > 0 {
+		level = results[0].Level
+	}
+	p.header("PING "+level.String(), len(results))
+	for _, r := range results {
+		label := fmt.Sprintf("%s:%d", r.Host, r.Port)
+		if r.Connected {
+			_, _ = fmt.Fprintf(p.w, "%-20s : [ ✓ ok (%.2fms) ]\n", label, millis(r.Latency))
+		} else {
+			_, _ = fmt.Fprintf(p.w, "%-20s : [ ✗ failed at %s ]\n", label, r.FailedStage)
+		}
+
+		// 多阶段检查时输出每个阶段的耗时
+		if len(r.Stages) > 1 {
+			parts := make([]string, 0, len(r.Stages))
+			for _, stage := range r.Stages {
+				parts = append(parts, fmt.Sprintf("%s %.2fms", stage.Name, millis(stage.Duration)))
+			}
+			_, _ = fmt.Fprintf(p.w, "    %s\n", strings.Join(parts, " → "))
+		}
+		if r.ServerVersion != "" {
+			_, _ = fmt.Fprintf(p.w, "    %s\n", r.ServerVersion)
+		}
+		if r.HostKeyFingerprint != "" {
+			_, _ = fmt.Fprintf(p.w, "    %s %s\n", r.HostKeyType, r.HostKeyFingerprint)
+		}
+		if r.Err != nil {
+			_, _ = fmt.Fprintf(p.w, "    %v\n", r.Err)
+		}
+	}
+	p.footer(len(results), failed)
+	return exitCode(failed)
+}
+
+// factsResults 输出主机基础信息
+//
+// 参数:
+//   - facts: 每台主机的基础信息
+//
+// 返回:
+//   - int: 进程退出码
+func (p *printer) factsResults(facts []easyssh.HostFacts) int {
+	failed := 0
+	for _, f := range facts {
+		if f.Err != nil {
+			failed++
+		}
+	}
+
+	if p.json {
+		items := make([]factsJSON, 0, len(facts))
+		for _, f := range facts {
+			items = append(items, factsJSON{
+				Host:      f.Host,
+				Port:      f.Port,
+				Hostname:  f.Hostname,
+				OS:        f.OS,
+				Kernel:    f.Kernel,
+				Arch:      f.Arch,
+				CPUs:      f.CPUs,
+				MemTotal:  f.MemTotal,
+				UptimeSec: int64(f.Uptime / time.Second),
+				Error:     errString(f.Err),
+			})
+		}
+		p.encode(items)
+		return exitCode(failed)
+	}
+
+	p.header("FACTS", len(facts))
+	for _, f := range facts {
+		label := fmt.Sprintf("%s:%d", f.Host, f.Port)
+		if f.Err != nil {
+			_, _ = fmt.Fprintf(p.w, "%-20s : [ ✗ failed ]\n    %v\n", label, f.Err)
+			continue
+		}
+		_, _ = fmt.Fprintf(p.w, "%-20s : [ ✓ ok ]\n", label)
+		_, _ = fmt.Fprintf(p.w, "    主机名: %s\n    系统:   %s\n    内核:   %s (%s)\n", f.Hostname, f.OS, f.Kernel, f.Arch)
+		_, _ = fmt.Fprintf(p.w, "    CPU:    %d 核\n    内存:   %s\n    运行:   %s\n", f.CPUs, utils.FormatBytes(f.MemTotal), f.Uptime)
+	}
+	p.footer(len(facts), failed)
+	return exitCode(failed)
+}
+
+// syncResults 输出目录同步结果
+//
+// 参数:
+//   - description: 操作描述（仅文本格式使用）
+//   - results: 每台主机的同步结果
+//
+// 返回:
+//   - int: 进程退出码
+func (p *printer) syncResults(description string, results []easyssh.SyncResult) int {
+	failed := 0
+	for _, r := range results {
+		if !r.Success {
+			failed++
+		}
+	}
+
+	if p.json {
+		items := make([]syncJSON, 0, len(results))
+		for _, r := range results {
+			items = append(items, syncJSON{
+				Host:       r.Host,
+				Port:       r.Port,
+				Success:    r.Success,
+				Added:      nonNil(r.Added),
+				Changed:    nonNil(r.Changed),
+				Deleted:    nonNil(r.Deleted),
+				Unchanged:  r.Unchanged,
+				Error:      errString(r.Err),
+				DurationMs: millis(r.Duration),
+			})
+		}
+		p.encode(items)
+		return exitCode(failed)
+	}
+
+	p.header(description, len(results))
+	for _, r := range results {
+		label := fmt.Sprintf("%s:%d", r.Host, r.Port)
+		if !r.Success {
+			_, _ = fmt.Fprintf(p.w, "%-20s : [ ✗ failed ]\n", label)
+			if r.Err != nil {
+				_, _ = fmt.Fprintf(p.w, "    %v\n", r.Err)
+			}
+			continue
+		}
+		_, _ = fmt.Fprintf(p.w, "%-20s : [ ✓ ok (%.2fms) ] added=%d changed=%d deleted=%d unchanged=%d\n",
+			label, millis(r.Duration), len(r.Added), len(r.Changed), len(r.Deleted), r.Unchanged)
+		for _, name := range r.Added {
+			_, _ = fmt.Fprintf(p.w, "    + %s\n", name)
+		}
+		for _, name := range r.Changed {
+			_, _ = fmt.Fprintf(p.w, "    ~ %s\n", name)
+		}
+		for _, name := range r.Deleted {
+			_, _ = fmt.Fprintf(p.w, "    - %s\n", name)
+		}
+	}
+	p.footer(len(results), failed)
+	return exitCode(failed)
+}
+
+// playbookJSON 剧本执行报告的 JSON 表示
+type playbookJSON struct {
+	Name   string     `json:"name"`
+	Check  bool       `json:"check"`
+	Failed bool       `json:"failed"`
+	Steps  []stepJSON `json:"steps"`
+}
+
+// stepJSON 单个剧本步骤执行结果的 JSON 表示
+type stepJSON struct {
+	Name    string         `json:"name"`
+	Results []stepHostJSON `json:"results"`
+}
+
+// stepHostJSON 剧本步骤在单台主机上执行结果的 JSON 表示
+type stepHostJSON struct {
+	Host       string  `json:"host"`
+	Port       int     `json:"port"`
+	Status     string  `json:"status"`
+	ExitCode   int     `json:"exit_code"`
+	Output     string  `json:"output,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// playbookReport 输出剧本执行报告（文本格式已由库逐步骤输出）
+//
+// 参数:
+//   - report: 剧本执行报告
+//
+// 返回:
+//   - int: 进程退出码
+func (p *printer) playbookReport(report *easyssh.PlaybookReport) int {
+	code := exitOK
+	if report.Failed() {
+		code = exitFailed
+	}
+	if !p.json {
+		return code
+	}
+
+	out := playbookJSON{Name: report.Name, Check: report.Check, Failed: report.Failed()}
+	for _, step := range report.Steps {
+		stepOut := stepJSON{Name: step.Name}
+		for _, r := range step.Results {
+			stepOut.Results = append(stepOut.Results, stepHostJSON{
+				Host:       r.Host,
+				Port:       r.Port,
+				Status:     string(r.Status),
+				ExitCode:   r.ExitCode,
+				Output:     r.Output,
+				Error:      errString(r.Err),
+				DurationMs: millis(r.Duration),
+			})
+		}
+		out.Steps = append(out.Steps, stepOut)
+	}
+	p.encode(out)
+	return code
+}
+
+// header 输出文本格式的标题
+func (p *printer) header(description string, total int) {
+	_, _ = fmt.Fprintf(p.w, "==> %s (%d hosts)\n", description, total)
+	_, _ = fmt.Fprintln(p.w, "----------------------------------------")
+}
+
+// footer 输出文本格式的统计信息
+func (p *printer) footer(total, failed int) {
+	_, _ = fmt.Fprintln(p.w, "----------------------------------------")
+	_, _ = fmt.Fprintf(p.w, "==> 成功: %d/%d | 失败: %d/%d\n", total-failed, total, failed, total)
+}
+
+// indent 以缩进形式输出多行文本
+func (p *printer) indent(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		_, _ = fmt.Fprintf(p.w, "    %s\n", line)
+	}
+}
+
+// encode 以 JSON 格式输出数据
+func (p *printer) encode(v any) {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// exitCode 根据失败主机数量计算退出码
+func exitCode(failed int) int {
+	if failed > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// errString 将错误转换为字符串，nil 返回空字符串
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// millis 将时长转换为毫秒
+func millis(d time.Duration) float64 {
+	return float64(d.Nanoseconds()) / 1e6
+}
+
+// nonNil 将 nil 切片转换为空切片，使 JSON 输出为 [] 而不是 null
+func nonNil(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gitee.com/MM-Q/go-kit/easyssh"
+	"gitee.com/MM-Q/go-kit/utils"
+)
+
+// newTestPrinter 创建输出到缓冲区的结果输出器
+func newTestPrinter(t *testing.T, format string) (*printer, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+
+	var w, errW bytes.Buffer
+	p, err := newPrinter(format, &w, &errW)
+	if err != nil {
+		t.Fatalf("newPrinter(%q) error = %v", format, err)
+	}
+	return p, &w, &errW
+}
+
+func TestNewPrinter(t *testing.T) {
+	for _, format := range []string{"text", "json", "JSON"} {
+		if _, err := newPrinter(format, nil, nil); err != nil {
+			t.Errorf("newPrinter(%q) error = %v", format, err)
+		}
+	}
+	if _, err := newPrinter("yaml", nil, nil); err == nil {
+		t.Error("newPrinter(yaml): expected error")
+	}
+}
+
+func TestPrinterFatal(t *testing.T) {
+	p, w, errW := newTestPrinter(t, "text")
+	if code := p.fatal(errors.New("boom"), exitError); code != exitError {
+		t.Errorf("fatal() = %d, want %d", code, exitError)
+	}
+	if errW.String() != "错误: boom\n" || w.Len() != 0 {
+		t.Errorf("text fatal: stdout %q, stderr %q", w.String(), errW.String())
+	}
+
+	p, w, errW = newTestPrinter(t, "json")
+	if code := p.fatal(errors.New("boom"), exitUsage); code != exitUsage {
+		t.Errorf("fatal() = %d, want %d", code, exitUsage)
+	}
+	var out map[string]string
+	if err := json.Unmarshal(w.Bytes(), &out); err != nil || out["error"] != "boom" || errW.Len() != 0 {
+		t.Errorf("json fatal: stdout %q (%v), stderr %q", w.String(), err, errW.String())
+	}
+}
+
+func TestPrinterHostResults(t *testing.T) {
+	results := []easyssh.HostResult{
+		{Host: "10.0.0.1", Port: 22, Success: true, Output: "line1\nline2\n", Duration: 1500 * time.Microsecond},
+		{Host: "10.0.0.2", Port: 2222, Err: errors.New("exit status 3"), ExitCode: 3},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		results []easyssh.HostResult
+		want    int
+		check   func(t *testing.T, out string)
+	}{
+		{
+			name: "Text", format: "text", results: results, want: exitFailed,
+			check: func(t *testing.T, out string) {
+				for _, s := range []string{
+					"==> EXEC uptime (2 hosts)",
+					"10.0.0.1:22          : [ ✓ ok (1.50ms) ]\n    line1\n    line2\n",
+					"10.0.0.2:2222        : [ ✗ failed ]\n    exit status 3\n",
+					"==> 成功: 1/2 | 失败: 1/2",
+				} {
+					if !strings.Contains(out, s) {
+						t.Errorf("output missing %q:\n%s", s, out)
+					}
+				}
+			},
+		},
+		{
+			name: "JSON", format: "json", results: results, want: exitFailed,
+			check: func(t *testing.T, out string) {
+				var items []hostJSON
+				if err := json.Unmarshal([]byte(out), &items); err != nil {
+					t.Fatalf("invalid JSON: %v\n%s", err, out)
+				}
+				want := []hostJSON{
+					{Host: "10.0.0.1", Port: 22, Success: true, Output: "line1\nline2\n", DurationMs: 1.5},
+					{Host: "10.0.0.2", Port: 2222, Error: "exit status 3", ExitCode: 3},
+				}
+				if len(items) != len(want) || items[0] != want[0] || items[1] != want[1] {
+					t.Errorf("items = %+v, want %+v", items, want)
+				}
+				if !strings.Contains(out, `"exit_code": 0`) {
+					t.Errorf("exit_code must be present for successful hosts:\n%s", out)
+				}
+			},
+		},
+		{
+			name: "All succeeded", format: "json", results: results[:1], want: exitOK,
+			check: func(t *testing.T, out string) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, w, _ := newTestPrinter(t, tt.format)
+			if code := p.hostResults("EXEC uptime", tt.results); code != tt.want {
+				t.Errorf("hostResults() = %d, want %d", code, tt.want)
+			}
+			tt.check(t, w.String())
+		})
+	}
+}
+
+func TestPrinterPingResults(t *testing.T) {
+	results := []easyssh.PingResult{
+		{
+			Host: "10.0.0.1", Port: 22, Connected: true, Level: easyssh.PingAuth, Latency: 3 * time.Millisecond,
+			Stages: []easyssh.PingStage{
+				{Name: "tcp", Duration: time.Millisecond},
+				{Name: "auth", Duration: 2 * time.Millisecond},
+			},
+			ServerVersion: "SSH-2.0-OpenSSH_9.6",
+		},
+		{Host: "10.0.0.2", Port: 22, Level: easyssh.PingAuth, FailedStage: "tcp", Err: errors.New("connection refused")},
+	}
+
+	p, w, _ := newTestPrinter(t, "text")
+	if code := p.pingResults(results); code != exitFailed {
+		t.Errorf("pingResults() = %d, want %d", code, exitFailed)
+	}
+	for _, s := range []string{"==> PING auth (2 hosts)", "    tcp 1.00ms → auth 2.00ms\n", "SSH-2.0-OpenSSH_9.6", "[ ✗ failed at tcp ]"} {
+		if !strings.Contains(w.String(), s) {
+			t.Errorf("output missing %q:\n%s", s, w.String())
+		}
+	}
+
+	p, w, _ = newTestPrinter(t, "json")
+	p.pingResults(results)
+	var items []pingJSON
+	if err := json.Unmarshal(w.Bytes(), &items); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(items) != 2 || len(items[0].Stages) != 2 || items[0].Level != "auth" || items[1].FailedStage != "tcp" || items[1].Error != "connection refused" {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+func TestPrinterSyncResults(t *testing.T) {
+	results := []easyssh.SyncResult{{Host: "10.0.0.1", Port: 22, Success: true, Added: []string{"a.txt"}, Unchanged: 2}}
+
+	p, w, _ := newTestPrinter(t, "text")
+	if code := p.syncResults("SYNC a -> b", results); code != exitOK {
+		t.Errorf("syncResults() = %d, want %d", code, exitOK)
+	}
+	if !strings.Contains(w.String(), "added=1 changed=0 deleted=0 unchanged=2\n    + a.txt\n") {
+		t.Errorf("unexpected output:\n%s", w.String())
+	}
+
+	// 空列表输出为 [] 而不是 null
+	p, w, _ = newTestPrinter(t, "json")
+	p.syncResults("SYNC a -> b", results)
+	if !strings.Contains(w.String(), `"changed": []`) || !strings.Contains(w.String(), `"deleted": []`) {
+		t.Errorf("unexpected JSON:\n%s", w.String())
+	}
+}
+
+func TestPrinterFactsResults(t *testing.T) {
+	facts := []easyssh.HostFacts{
+		{Host: "10.0.0.1", Port: 22, Hostname: "web1", OS: "Debian", CPUs: 4, MemTotal: 8 << 30, Uptime: 90 * time.Second},
+		{Host: "10.0.0.2", Port: 22, Err: errors.New("auth failed")},
+	}
+
+	p, w, _ := newTestPrinter(t, "text")
+	if code := p.factsResults(facts); code != exitFailed {
+		t.Errorf("factsResults() = %d, want %d", code, exitFailed)
+	}
+	for _, s := range []string{"主机名: web1", "内存:   " + utils.FormatBytes(8<<30), "auth failed"} {
+		if !strings.Contains(w.String(), s) {
+			t.Errorf("output missing %q:\n%s", s, w.String())
+		}
+	}
+
+	p, w, _ = newTestPrinter(t, "json")
+	p.factsResults(facts)
+	var items []factsJSON
+	if err := json.Unmarshal(w.Bytes(), &items); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(items) != 2 || items[0].UptimeSec != 90 || items[0].MemTotal != 8<<30 || items[1].Error != "auth failed" {
+		t.Errorf("items = %+v", items)
+	}
+}
@@ -2,8 +2,10 @@ package easyssh
 
 import (
 	"fmt"
-	"net"
+	"path"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -73,41 +75,166 @@ func (e *EasySSH) ReloadHosts() error {
 	return nil
 }
 
-// execOnHost 在单台主机上执行命令（私有方法）
+// SelectHosts 加载主机清单并按 Patterns 筛选主机
+//
+// 筛选规则：
+//   - Patterns 为空时返回全部主机
+//   - 每个模式使用 path.Match 语法，同时匹配 "主机地址" 和 "主机地址:端口"
+//   - 任意一个模式匹配即选中该主机，结果保持清单中的原有顺序
+//
+// 返回：
+//   - []HostConfig: 筛选后的主机配置列表
+//   - error: 解析主机清单失败或模式语法错误时返回错误
+func (e *EasySSH) SelectHosts() ([]HostConfig, error) {
+	hosts, err := e.LoadHosts()
+	if err != nil {
+		return nil, err
+	}
+	return filterHosts(hosts, e.Patterns)
+}
+
+// filterHosts 按通配符模式筛选主机（私有函数）
 //
 // 参数：
-//   - host: 要执行的主机配置
-//   - cmd: 要执行的命令
+//   - hosts: 待筛选的主机列表
+//   - patterns: 通配符模式列表，为空时不筛选
+//
+// 返回：
+//   - []HostConfig: 筛选后的主机列表
+//   - error: 模式语法错误时返回错误
+func filterHosts(hosts []HostConfig, patterns []string) ([]HostConfig, error) {
+	if len(patterns) == 0 {
+		return hosts, nil
+	}
+
+	selected := make([]HostConfig, 0, len(hosts))
+	for _, host := range hosts {
+		matched, err := matchHost(host, patterns)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			selected = append(selected, host)
+		}
+	}
+	return selected, nil
+}
+
+// matchHost 判断主机是否匹配任意一个通配符模式（私有函数）
+//
+// 参数：
+//   - host: 主机配置
+//   - patterns: 通配符模式列表，为空时视为匹配
 //
 // 返回：
-//   - RemoteExecResult: 执行结果
-func (e *EasySSH) execOnHost(host HostConfig, cmd string) RemoteExecResult {
-	return ExecRemoteCmd(host, cmd, e.Timeout)
+//   - bool: 是否匹配
+//   - error: 模式语法错误时返回错误
+func matchHost(host HostConfig, patterns []string) (bool, error) {
+	if len(patterns) == 0 {
+		return true, nil
+	}
+
+	hostLabel := fmt.Sprintf("%s:%d", host.Host, host.Port)
+	for _, pattern := range patterns {
+		matchedHost, err := path.Match(pattern, host.Host)
+		if err != nil {
+			return false, fmt.Errorf("无效的主机筛选模式 %q: %w", pattern, err)
+		}
+		matchedLabel, _ := path.Match(pattern, hostLabel)
+		if matchedHost || matchedLabel {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
-// execAll 通用执行逻辑（私有方法）
-func (e *EasySSH) execAll(cmd, description string, handleResult func(hostLabel string, result RemoteExecResult)) error {
-	hosts, err := e.LoadHosts()
+// runParallel 按并发数在多台主机上执行操作，结果顺序与主机顺序一致（私有函数）
+//
+// 参数：
+//   - hosts: 要操作的主机列表
+//   - parallel: 最大并发数，小于等于1时串行执行
+//   - fn: 针对单台主机的操作函数，i 为主机在列表中的下标
+//
+// 返回：
+//   - []T: 每台主机的操作结果
+func runParallel[T any](hosts []HostConfig, parallel int, fn func(i int, host HostConfig) T) []T {
+	results := make([]T, len(hosts))
+	if parallel <= 1 {
+		for i, host := range hosts {
+			results[i] = fn(i, host)
+		}
+		return results
+	}
+
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, host HostConfig) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = fn(i, host)
+		}(i, host)
+	}
+	wg.Wait()
+	return results
+}
+
+// runOnHosts 在筛选后的主机上并发执行操作并记录耗时（私有方法）
+//
+// 参数：
+//   - fn: 针对单台主机的操作，返回输出内容和错误
+//
+// 返回：
+//   - []HostResult: 每台主机的操作结果
+//   - error: 加载主机清单失败时返回错误
+func (e *EasySSH) runOnHosts(fn func(host HostConfig) (string, error)) ([]HostResult, error) {
+	hosts, err := e.SelectHosts()
 	if err != nil {
-		return fmt.Errorf("解析主机清单失败: %w", err)
+		return nil, fmt.Errorf("解析主机清单失败: %w", err)
 	}
 
-	if len(hosts) == 0 {
+	results := runParallel(hosts, e.Parallel, func(_ int, host HostConfig) HostResult {
+		startTime := time.Now()
+		output, err := fn(host)
+		return HostResult{
+			Host:     host.Host,
+			Port:     host.Port,
+			Success:  err == nil,
+			Output:   output,
+			Err:      err,
+			ExitCode: exitCodeOf(err),
+			Duration: time.Since(startTime),
+		}
+	})
+	return results, nil
+}
+
+// printResults 按统一格式打印批量操作结果（私有方法）
+//
+// 参数：
+//   - description: 描述信息
+//   - results: 每台主机的操作结果
+//   - handleResult: 成功结果的处理函数，可为 nil
+func (e *EasySSH) printResults(description string, results []HostResult, handleResult func(hostLabel string, result HostResult)) {
+	if len(results) == 0 {
 		if e.ShowFormat {
 			fmt.Printf("==> 跳过 %s: 主机清单为空\n", description)
 		}
-		return nil
+		return
 	}
 
 	if e.ShowFormat {
-		fmt.Printf("==> %s (%d hosts)\n", description, len(hosts))
+		fmt.Printf("==> %s (%d hosts)\n", description, len(results))
 		fmt.Println("----------------------------------------")
 	}
 
 	successCount := 0
-	for _, host := range hosts {
-		hostLabel := fmt.Sprintf("%s:%d", host.Host, host.Port)
-		result := e.execOnHost(host, cmd)
+	for _, result := range results {
+		hostLabel := fmt.Sprintf("%s:%d", result.Host, result.Port)
 
 		if result.Success {
 			if e.ShowFormat {
@@ -121,19 +248,47 @@ func (e *EasySSH) execAll(cmd, description string, handleResult func(hostLabel s
 			if e.ShowFormat {
 				fmt.Printf("%-20s : [ ✗ failed ]\n", hostLabel)
 			}
-			if result.Output != "" && e.ShowOutput {
-				fmt.Printf("    %s\n", strings.TrimSpace(result.Output))
+			if e.ShowOutput {
+				if result.Output != "" {
+					fmt.Printf("    %s\n", strings.TrimSpace(result.Output))
+				} else if result.Err != nil {
+					fmt.Printf("    %v\n", result.Err)
+				}
 			}
 		}
 	}
 
 	if e.ShowFormat {
 		fmt.Println("----------------------------------------")
-		fmt.Printf("==> 成功: %d/%d | 失败: %d/%d\n\n", successCount, len(hosts), len(hosts)-successCount, len(hosts))
+		fmt.Printf("==> 成功: %d/%d | 失败: %d/%d\n\n", successCount, len(results), len(results)-successCount, len(results))
 	}
+}
+
+// execAll 通用执行逻辑（私有方法）
+func (e *EasySSH) execAll(cmd, description string, handleResult func(hostLabel string, result HostResult)) error {
+	results, err := e.ExecRaw(cmd)
+	if err != nil {
+		return err
+	}
+	e.printResults(description, results, handleResult)
 	return nil
 }
 
+// ExecRaw 在所有选中的主机上执行命令，返回原始结果（不打印任何输出）
+//
+// 参数：
+//   - cmd: 要执行的命令
+//
+// 返回：
+//   - []HostResult: 每台主机的执行结果，顺序与主机清单一致
+//   - error: 如果解析主机文件失败，返回错误
+func (e *EasySSH) ExecRaw(cmd string) ([]HostResult, error) {
+	return e.runOnHosts(func(host HostConfig) (string, error) {
+		result := ExecRemoteCmd(host, cmd, e.Timeout)
+		return result.Output, result.Err
+	})
+}
+
 // Exec 在所有主机上执行命令
 //
 // 参数：
@@ -143,7 +298,7 @@ func (e *EasySSH) execAll(cmd, description string, handleResult func(hostLabel s
 // 返回：
 //   - error: 执行错误，如果发生错误则返回非 nil 错误
 func (e *EasySSH) Exec(cmd, description string) error {
-	return e.execAll(cmd, description, func(hostLabel string, result RemoteExecResult) {
+	return e.execAll(cmd, description, func(hostLabel string, result HostResult) {
 		if e.ShowOutput && result.Success {
 			output := strings.TrimSpace(result.Output)
 			fmt.Printf("    %s\n", output)
@@ -158,7 +313,7 @@ func (e *EasySSH) Exec(cmd, description string) error {
 //   - description: 描述信息
 //   - processFunc: 处理结果函数，接收两个参数：hostLabel 和 output，分别表示服务器标签和输出结果
 func (e *EasySSH) ExecWithCallback(cmd, description string, processFunc func(hostLabel, output string)) {
-	_ = e.execAll(cmd, description, func(hostLabel string, result RemoteExecResult) {
+	_ = e.execAll(cmd, description, func(hostLabel string, result HostResult) {
 		if result.Success {
 			output := strings.TrimSpace(result.Output)
 			processFunc(hostLabel, output)
@@ -166,6 +321,86 @@ func (e *EasySSH) ExecWithCallback(cmd, description string, processFunc func(hos
 	})
 }
 
+// Upload 将本地文件上传到所有选中的主机并打印结果
+//
+// 参数：
+//   - localPath: 本地文件路径
+//   - remotePath: 远程目标路径，若为已存在的目录则自动追加本地文件名
+//
+// 返回：
+//   - error: 如果解析主机文件失败，返回错误
+func (e *EasySSH) Upload(localPath, remotePath string) error {
+	results, err := e.UploadRaw(localPath, remotePath)
+	if err != nil {
+		return err
+	}
+	e.printResults(fmt.Sprintf("UPLOAD %s -> %s", localPath, remotePath), results, nil)
+	return nil
+}
+
+// UploadRaw 将本地文件上传到所有选中的主机，返回原始结果（不打印任何输出）
+//
+// 参数：
+//   - localPath: 本地文件路径
+//   - remotePath: 远程目标路径，若为已存在的目录则自动追加本地文件名
+//
+// 返回：
+//   - []HostResult: 每台主机的上传结果
+//   - error: 如果解析主机文件失败，返回错误
+func (e *EasySSH) UploadRaw(localPath, remotePath string) ([]HostResult, error) {
+	return e.runOnHosts(func(host HostConfig) (string, error) {
+		return "", UploadFile(host, localPath, remotePath, e.Timeout)
+	})
+}
+
+// Download 从所有选中的主机下载文件并打印结果
+//
+// 参数：
+//   - remotePath: 远程文件路径
+//   - localDir: 本地保存目录，每台主机的文件保存在 localDir/<主机标识>/ 下
+//
+// 返回：
+//   - error: 如果解析主机文件失败，返回错误
+func (e *EasySSH) Download(remotePath, localDir string) error {
+	results, err := e.DownloadRaw(remotePath, localDir)
+	if err != nil {
+		return err
+	}
+	e.printResults(fmt.Sprintf("DOWNLOAD %s -> %s", remotePath, localDir), results, nil)
+	return nil
+}
+
+// DownloadRaw 从所有选中的主机下载文件，返回原始结果（不打印任何输出）
+//
+// 为避免多台主机的同名文件互相覆盖，文件保存路径为：
+//   - 端口为22时：localDir/<主机地址>/<文件名>
+//   - 其他端口时：localDir/<主机地址>_<端口>/<文件名>
+//
+// 参数：
+//   - remotePath: 远程文件路径
+//   - localDir: 本地保存目录
+//
+// 返回：
+//   - []HostResult: 每台主机的下载结果，Output 为本地保存路径
+//   - error: 如果解析主机文件失败，返回错误
+func (e *EasySSH) DownloadRaw(remotePath, localDir string) ([]HostResult, error) {
+	return e.runOnHosts(func(host HostConfig) (string, error) {
+		localPath := filepath.Join(localDir, hostDirName(host), path.Base(remotePath))
+		if err := DownloadFile(host, remotePath, localPath, e.Timeout); err != nil {
+			return "", err
+		}
+		return localPath, nil
+	})
+}
+
+// hostDirName 返回主机对应的本地目录名（私有函数）
+func hostDirName(host HostConfig) string {
+	if host.Port == 22 {
+		return host.Host
+	}
+	return fmt.Sprintf("%s_%d", host.Host, host.Port)
+}
+
 // PingHosts 测试所有主机的连通性并打印结果
 //
 // 返回:
@@ -199,7 +434,7 @@ func (e *EasySSH) PingHostsRaw() ([]PingResult, error) {
 //   - []PingResult: 每台主机的连通性测试结果
 //   - error: 如果解析主机文件失败，返回错误
 func (e *EasySSH) pingHosts() ([]PingResult, error) {
-	hosts, err := e.LoadHosts()
+	hosts, err := e.SelectHosts()
 	if err != nil {
 		return nil, fmt.Errorf("解析主机清单失败: %w", err)
 	}
@@ -212,37 +447,38 @@ func (e *EasySSH) pingHosts() ([]PingResult, error) {
 	}
 
 	if e.ShowFormat {
-		fmt.Printf("==> PING (%d hosts)\n", len(hosts))
+		fmt.Printf("==> PING %s (%d hosts)\n", e.PingLevel, len(hosts))
 		fmt.Println("----------------------------------------")
 	}
 
-	results := make([]PingResult, 0, len(hosts))
-	successCount := 0
-
-	for _, host := range hosts {
-		hostLabel := fmt.Sprintf("%s:%d", host.Host, host.Port)
+	// 按检查级别并发测试连通性，结果顺序与主机清单一致
+	results := runParallel(hosts, e.Parallel, func(_ int, host HostConfig) PingResult {
+		return e.pingSingleHost(host)
+	})
 
-		// 测试 TCP 连通性
-		startTime := time.Now()
-		result := e.pingSingleHost(host.Host, host.Port)
-		latency := time.Since(startTime)
+	successCount := 0
+	for _, result := range results {
+		hostLabel := fmt.Sprintf("%s:%d", result.Host, result.Port)
 
 		if result.Connected {
 			if e.ShowFormat {
-				fmt.Printf("%-20s : [ ✓ ok (%.2fms) ]\n", hostLabel, float64(latency.Nanoseconds())/1e6)
+				fmt.Printf("%-20s : [ ✓ ok (%.2fms) ]\n", hostLabel, float64(result.Latency.Nanoseconds())/1e6)
+			}
+			if e.ShowOutput && result.ServerVersion != "" {
+				fmt.Printf("    %s\n", result.ServerVersion)
+			}
+			if e.ShowOutput && result.HostKeyFingerprint != "" {
+				fmt.Printf("    %s %s\n", result.HostKeyType, result.HostKeyFingerprint)
 			}
-			result.Latency = latency
 			successCount++
 		} else {
 			if e.ShowFormat {
-				fmt.Printf("%-20s : [ ✗ failed ]\n", hostLabel)
+				fmt.Printf("%-20s : [ ✗ failed at %s ]\n", hostLabel, result.FailedStage)
 			}
 			if e.ShowOutput && result.Err != nil {
 				fmt.Printf("    %v\n", result.Err)
 			}
 		}
-
-		results = append(results, result)
 	}
 
 	if e.ShowFormat {
@@ -251,28 +487,3 @@ func (e *EasySSH) pingHosts() ([]PingResult, error) {
 	}
 	return results, nil
 }
-
-// pingSingleHost 测试单个主机的连通性（私有方法）
-func (e *EasySSH) pingSingleHost(host string, port int) PingResult {
-	result := PingResult{
-		Host: host,
-		Port: port,
-	}
-
-	timeout := e.Timeout
-	if timeout == 0 {
-		timeout = 5 * time.Second // 默认超时5秒
-	}
-
-	// 使用 net.DialTimeout 测试 TCP 连通性
-	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", host, port), timeout)
-	if err != nil {
-		result.Connected = false
-		result.Err = err
-		return result
-	}
-	defer func() { _ = conn.Close() }()
-
-	result.Connected = true
-	return result
-}
@@ -0,0 +1,122 @@
+package easyssh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeHostsFile 创建测试用主机清单文件
+func writeHostsFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "hosts.txt")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create hosts file: %v", err)
+	}
+	return path
+}
+
+func TestSelectHosts(t *testing.T) {
+	hostsFile := writeHostsFile(t, `# 测试主机
+192.168.1.10 root pw
+192.168.1.11 2222 root pw
+10.0.0.1 admin pw
+`)
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "No patterns", patterns: nil, want: []string{"192.168.1.10", "192.168.1.11", "10.0.0.1"}},
+		{name: "Wildcard", patterns: []string{"192.168.*"}, want: []string{"192.168.1.10", "192.168.1.11"}},
+		{name: "Host with port", patterns: []string{"*:2222"}, want: []string{"192.168.1.11"}},
+		{name: "Multiple patterns", patterns: []string{"10.*", "192.168.1.10"}, want: []string{"192.168.1.10", "10.0.0.1"}},
+		{name: "No match", patterns: []string{"172.*"}, want: []string{}},
+		{name: "Invalid pattern", patterns: []string{"[192"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewDef(hostsFile)
+			e.Patterns = tt.patterns
+
+			hosts, err := e.SelectHosts()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectHosts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(hosts) != len(tt.want) {
+				t.Fatalf("SelectHosts() got %d hosts, want %d", len(hosts), len(tt.want))
+			}
+			for i, host := range hosts {
+				if host.Host != tt.want[i] {
+					t.Errorf("SelectHosts()[%d] = %s, want %s", i, host.Host, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRunParallel(t *testing.T) {
+	hosts := make([]HostConfig, 20)
+	for i := range hosts {
+		hosts[i] = HostConfig{Host: "host", Port: i + 1}
+	}
+
+	for _, parallel := range []int{0, 1, 4, 50} {
+		results := runParallel(hosts, parallel, func(_ int, host HostConfig) int {
+			time.Sleep(time.Millisecond)
+			return host.Port
+		})
+		for i, port := range results {
+			if port != i+1 {
+				t.Errorf("parallel=%d: results[%d] = %d, want %d", parallel, i, port, i+1)
+			}
+		}
+	}
+}
+
+func TestParseFacts(t *testing.T) {
+	output := `hostname=web01
+os=Ubuntu 22.04.3 LTS
+kernel=5.15.0-91-generic
+arch=x86_64
+cpus=8
+mem_kb=16303412
+uptime=86400
+garbage line
+`
+	facts := parseFacts(output)
+
+	if facts.Hostname != "web01" {
+		t.Errorf("Hostname = %q, want %q", facts.Hostname, "web01")
+	}
+	if facts.OS != "Ubuntu 22.04.3 LTS" {
+		t.Errorf("OS = %q, want %q", facts.OS, "Ubuntu 22.04.3 LTS")
+	}
+	if facts.Kernel != "5.15.0-91-generic" || facts.Arch != "x86_64" {
+		t.Errorf("Kernel/Arch = %q/%q", facts.Kernel, facts.Arch)
+	}
+	if facts.CPUs != 8 {
+		t.Errorf("CPUs = %d, want 8", facts.CPUs)
+	}
+	if want := int64(16303412) * 1024; facts.MemTotal != want {
+		t.Errorf("MemTotal = %d, want %d", facts.MemTotal, want)
+	}
+	if facts.Uptime != 24*time.Hour {
+		t.Errorf("Uptime = %v, want 24h", facts.Uptime)
+	}
+
+	// 缺失或非法的数值保持零值
+	empty := parseFacts("cpus=\nmem_kb=abc\n")
+	if empty.CPUs != 0 || empty.MemTotal != 0 {
+		t.Errorf("parseFacts() with invalid values = %+v, want zero values", empty)
+	}
+}
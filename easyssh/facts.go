@@ -0,0 +1,86 @@
+package easyssh
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// factsScript 采集主机基础信息的远程脚本，每行输出一个 key=value
+// 仅依赖 POSIX shell 和常见系统命令，缺失的信息输出为空值
+const factsScript = `echo "hostname=$(hostname 2>/dev/null || uname -n)"
+echo "os=$(. /etc/os-release 2>/dev/null && echo "$PRETTY_NAME" || uname -s)"
+echo "kernel=$(uname -r)"
+echo "arch=$(uname -m)"
+echo "cpus=$(nproc 2>/dev/null || getconf _NPROCESSORS_ONLN 2>/dev/null)"
+echo "mem_kb=$(awk '/^MemTotal:/ {print $2}' /proc/meminfo 2>/dev/null)"
+echo "uptime=$(cut -d. -f1 /proc/uptime 2>/dev/null)"`
+
+// GatherFacts 采集所有选中主机的基础信息（主机名、系统、内核、架构、CPU、内存、运行时长）
+//
+// 返回：
+//   - []HostFacts: 每台主机的基础信息，采集失败的主机 Err 字段非 nil
+//   - error: 如果解析主机文件失败，返回错误
+func (e *EasySSH) GatherFacts() ([]HostFacts, error) {
+	hosts, err := e.SelectHosts()
+	if err != nil {
+		return nil, fmt.Errorf("解析主机清单失败: %w", err)
+	}
+
+	facts := runParallel(hosts, e.Parallel, func(_ int, host HostConfig) HostFacts {
+		result := ExecRemoteCmd(host, factsScript, e.Timeout)
+		if !result.Success {
+			return HostFacts{Host: host.Host, Port: host.Port, Err: result.Err}
+		}
+		hostFacts := parseFacts(result.Output)
+		hostFacts.Host = host.Host
+		hostFacts.Port = host.Port
+		return hostFacts
+	})
+	return facts, nil
+}
+
+// parseFacts 解析 factsScript 的输出（私有函数）
+//
+// 参数：
+//   - output: 远程脚本输出，每行一个 key=value
+//
+// 返回：
+//   - HostFacts: 解析得到的主机信息（不含 Host/Port）
+func parseFacts(output string) HostFacts {
+	var facts HostFacts
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "hostname":
+			facts.Hostname = value
+		case "os":
+			facts.OS = value
+		case "kernel":
+			facts.Kernel = value
+		case "arch":
+			facts.Arch = value
+		case "cpus":
+			facts.CPUs, _ = strconv.Atoi(value)
+		case "mem_kb":
+			if kb, err := strconv.ParseInt(value, 10, 64); err == nil {
+				facts.MemTotal = kb * 1024
+			}
+		case "uptime":
+			if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+				facts.Uptime = time.Duration(sec) * time.Second
+			}
+		}
+	}
+
+	return facts
+}
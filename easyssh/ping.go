@@ -0,0 +1,230 @@
+package easyssh
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// 检查阶段名称
+const (
+	stageTCP       = "tcp"       // TCP 连接
+	stageBanner    = "banner"    // 读取 SSH 版本标识
+	stageHandshake = "handshake" // SSH 密钥交换
+	stageAuth      = "auth"      // 用户认证
+)
+
+// 版本标识之前允许出现的最大行数（RFC 4253 允许服务端先发送其他文本行）
+const maxPreBannerLines = 20
+
+// pingLevelNames 检查级别与名称的对应关系
+var pingLevelNames = map[PingLevel]string{
+	PingTCP:       stageTCP,
+	PingBanner:    stageBanner,
+	PingHandshake: stageHandshake,
+	PingAuth:      stageAuth,
+}
+
+// String 返回检查级别的名称
+func (l PingLevel) String() string {
+	if name, ok := pingLevelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("PingLevel(%d)", int(l))
+}
+
+// ParsePingLevel 根据名称解析检查级别，匹配时忽略大小写
+//
+// 参数：
+//   - name: 级别名称（tcp、banner、handshake、auth）
+//
+// 返回：
+//   - PingLevel: 对应的检查级别
+//   - error: 名称无效时返回错误
+func ParsePingLevel(name string) (PingLevel, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for level, levelName := range pingLevelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+	return PingTCP, fmt.Errorf("无效的检查级别: %q（可选 tcp、banner、handshake、auth）", name)
+}
+
+// pingSingleHost 按 PingLevel 逐阶段测试单个主机的连通性（私有方法）
+//
+// 参数：
+//   - host: 要测试的主机配置
+//
+// 返回：
+//   - PingResult: 测试结果，包含每个阶段的耗时和失败阶段
+func (e *EasySSH) pingSingleHost(host HostConfig) PingResult {
+	result := PingResult{
+		Host:  host.Host,
+		Port:  host.Port,
+		Level: e.PingLevel,
+	}
+
+	timeout := e.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second // 默认超时5秒
+	}
+
+	// runStage 执行单个阶段并记录结果，返回该阶段是否成功
+	runStage := func(name string, fn func() error) bool {
+		start := time.Now()
+		err := fn()
+		result.addStage(name, time.Since(start), err)
+		return err == nil
+	}
+
+	addr := net.JoinHostPort(host.Host, strconv.Itoa(host.Port))
+
+	// 阶段1：TCP 连接
+	var conn net.Conn
+	if !runStage(stageTCP, func() (err error) {
+		conn, err = net.DialTimeout("tcp", addr, timeout)
+		return err
+	}) {
+		return result
+	}
+	defer func() { _ = conn.Close() }()
+
+	if e.PingLevel <= PingTCP {
+		result.Connected = true
+		return result
+	}
+
+	// 后续阶段共用一个截止时间，防止 sshd 挂起导致永久阻塞
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	// 阶段2：读取服务端版本标识
+	reader := bufio.NewReader(conn)
+	var consumed string
+	if !runStage(stageBanner, func() (err error) {
+		consumed, result.ServerVersion, err = readServerVersion(reader)
+		return err
+	}) {
+		return result
+	}
+
+	if e.PingLevel <= PingBanner {
+		result.Connected = true
+		return result
+	}
+
+	// 阶段3和4：密钥交换与认证
+	// 已读取的版本标识需要回放给 SSH 客户端，由其完成完整的协议流程
+	var handshakeDone time.Time
+	config := &ssh.ClientConfig{
+		User: host.Username,
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			handshakeDone = time.Now()
+			result.HostKeyType = key.Type()
+			result.HostKeyFingerprint = ssh.FingerprintSHA256(key)
+			return nil
+		},
+		Timeout: timeout,
+	}
+	if e.PingLevel >= PingAuth {
+		config.Auth = []ssh.AuthMethod{ssh.Password(host.Password)}
+	}
+
+	replayConn := &replayConn{Conn: conn, r: io.MultiReader(strings.NewReader(consumed), reader)}
+	start := time.Now()
+	sshConn, chans, reqs, err := ssh.NewClientConn(replayConn, addr, config)
+	authDone := time.Now()
+	if err == nil {
+		go ssh.DiscardRequests(reqs)
+		go func() {
+			for ch := range chans {
+				_ = ch.Reject(ssh.Prohibited, "ping")
+			}
+		}()
+		_ = sshConn.Close()
+	}
+
+	// 未收到主机密钥说明密钥交换阶段失败
+	if handshakeDone.IsZero() {
+		result.addStage(stageHandshake, authDone.Sub(start), fmt.Errorf("SSH握手失败: %w", err))
+		return result
+	}
+	result.addStage(stageHandshake, handshakeDone.Sub(start), nil)
+
+	if e.PingLevel <= PingHandshake {
+		// 未配置认证方式时，认证失败是预期行为
+		result.Connected = true
+		return result
+	}
+
+	// 阶段4：认证结果
+	if err != nil {
+		result.addStage(stageAuth, authDone.Sub(handshakeDone), fmt.Errorf("SSH认证失败: %w", err))
+		return result
+	}
+	result.addStage(stageAuth, authDone.Sub(handshakeDone), nil)
+	result.Connected = true
+	return result
+}
+
+// addStage 记录一个检查阶段的结果并累计耗时，失败时记录失败阶段（私有方法）
+//
+// 参数：
+//   - name: 阶段名称
+//   - duration: 阶段耗时
+//   - err: 阶段错误，成功时为 nil
+func (r *PingResult) addStage(name string, duration time.Duration, err error) {
+	r.Stages = append(r.Stages, PingStage{Name: name, Duration: duration, Err: err})
+	r.Latency += duration
+	if err != nil {
+		r.FailedStage = name
+		r.Err = err
+	}
+}
+
+// readServerVersion 读取服务端 SSH 版本标识行（私有函数）
+//
+// 参数：
+//   - reader: 连接上的缓冲读取器
+//
+// 返回：
+//   - string: 已读取的原始数据（需要回放给 SSH 客户端）
+//   - string: 版本标识（如 SSH-2.0-OpenSSH_9.6）
+//   - error: 读取失败或服务端未发送合法版本标识时返回错误
+func readServerVersion(reader *bufio.Reader) (string, string, error) {
+	var consumed strings.Builder
+	for i := 0; i < maxPreBannerLines; i++ {
+		line, err := reader.ReadString('\n')
+		consumed.WriteString(line)
+		if err != nil {
+			return "", "", fmt.Errorf("读取SSH版本标识失败: %w", err)
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "SSH-") {
+			if !strings.HasPrefix(line, "SSH-2.0-") && !strings.HasPrefix(line, "SSH-1.99-") {
+				return "", "", fmt.Errorf("不支持的SSH协议版本: %s", line)
+			}
+			return consumed.String(), line, nil
+		}
+	}
+	return "", "", errors.New("未收到SSH版本标识")
+}
+
+// replayConn 先回放已读取的数据，再继续读取底层连接的网络连接包装
+type replayConn struct {
+	net.Conn
+	r io.Reader
+}
+
+// Read 从回放数据和底层连接中读取数据
+func (c *replayConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
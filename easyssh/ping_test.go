@@ -0,0 +1,111 @@
+package easyssh
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestPingLevels(t *testing.T) {
+	host := startTestServer(t, "secret")
+
+	tests := []struct {
+		name        string
+		level       PingLevel
+		password    string
+		wantOK      bool
+		wantStages  int
+		failedStage string
+	}{
+		{name: "TCP", level: PingTCP, password: "secret", wantOK: true, wantStages: 1},
+		{name: "Banner", level: PingBanner, password: "secret", wantOK: true, wantStages: 2},
+		{name: "Handshake", level: PingHandshake, password: "wrong", wantOK: true, wantStages: 3},
+		{name: "Auth ok", level: PingAuth, password: "secret", wantOK: true, wantStages: 4},
+		{name: "Auth failed", level: PingAuth, password: "wrong", wantOK: false, wantStages: 4, failedStage: "auth"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New("", 3*time.Second, false, false)
+			e.PingLevel = tt.level
+			h := host
+			h.Password = tt.password
+
+			result := e.pingSingleHost(h)
+			if result.Connected != tt.wantOK {
+				t.Fatalf("Connected = %v, want %v (err: %v)", result.Connected, tt.wantOK, result.Err)
+			}
+			if len(result.Stages) != tt.wantStages {
+				t.Errorf("len(Stages) = %d, want %d", len(result.Stages), tt.wantStages)
+			}
+			if result.FailedStage != tt.failedStage {
+				t.Errorf("FailedStage = %q, want %q", result.FailedStage, tt.failedStage)
+			}
+			if tt.level >= PingBanner && result.ServerVersion == "" {
+				t.Error("ServerVersion should be set")
+			}
+			if tt.level >= PingHandshake && result.HostKeyType != ssh.KeyAlgoED25519 {
+				t.Errorf("HostKeyType = %q, want %q", result.HostKeyType, ssh.KeyAlgoED25519)
+			}
+
+			var total time.Duration
+			for _, stage := range result.Stages {
+				total += stage.Duration
+			}
+			if total != result.Latency {
+				t.Errorf("Latency = %v, want sum of stages %v", result.Latency, total)
+			}
+		})
+	}
+}
+
+func TestPingHungServer(t *testing.T) {
+	// 只接受连接但从不发送版本标识，模拟挂起的 sshd
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+		}
+	}()
+
+	e := New("", 200*time.Millisecond, false, false)
+	host := listenerHost(t, listener, "pw")
+
+	if result := e.pingSingleHost(host); !result.Connected {
+		t.Errorf("TCP level should succeed on hung server: %v", result.Err)
+	}
+
+	e.PingLevel = PingBanner
+	result := e.pingSingleHost(host)
+	if result.Connected {
+		t.Fatal("Banner level should fail on hung server")
+	}
+	if result.FailedStage != "banner" {
+		t.Errorf("FailedStage = %q, want %q", result.FailedStage, "banner")
+	}
+}
+
+func TestParsePingLevel(t *testing.T) {
+	for _, level := range []PingLevel{PingTCP, PingBanner, PingHandshake, PingAuth} {
+		got, err := ParsePingLevel(level.String())
+		if err != nil || got != level {
+			t.Errorf("ParsePingLevel(%q) = %v, %v", level.String(), got, err)
+		}
+	}
+	if got, err := ParsePingLevel(" AUTH "); err != nil || got != PingAuth {
+		t.Errorf("ParsePingLevel should ignore case and spaces, got %v, %v", got, err)
+	}
+	if _, err := ParsePingLevel("icmp"); err == nil {
+		t.Error("ParsePingLevel(\"icmp\") should return error")
+	}
+}
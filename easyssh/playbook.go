@@ -0,0 +1,381 @@
+package easyssh
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 步骤执行条件
+const (
+	WhenSuccess = "success" // 主机此前没有失败时执行（默认）
+	WhenFailure = "failure" // 主机此前已失败时执行，用于回滚或清理
+	WhenAlways  = "always"  // 无论主机此前是否失败都执行
+)
+
+// StepStatus 步骤在单台主机上的执行状态
+type StepStatus string
+
+const (
+	StatusOK      StepStatus = "ok"      // 执行成功
+	StatusFailed  StepStatus = "failed"  // 执行失败，主机被标记为失败
+	StatusIgnored StepStatus = "ignored" // 执行失败，但按 continue_on_error 忽略
+	StatusSkipped StepStatus = "skipped" // 条件不满足或主机未选中，未执行
+	StatusPlanned StepStatus = "planned" // 检查模式下计划执行
+)
+
+// Playbook 任务剧本，按顺序在主机上执行一组步骤
+//
+// 剧本文件为 JSON 格式，示例：
+//
+//	{
+//	  "name": "发布应用",
+//	  "steps": [
+//	    {"name": "检查磁盘", "run": "df -h /opt"},
+//	    {"name": "上传安装包", "upload": {"src": "app.tar.gz", "dst": "/tmp/"}, "hosts": ["web*"]},
+//	    {"name": "检查服务", "run": "systemctl is-active app", "continue_on_error": true},
+//	    {"name": "启动服务", "run": "systemctl start app", "when_exit": [3]},
+//	    {"name": "回滚", "run": "/opt/app/rollback.sh", "when": "failure"}
+//	  ]
+//	}
+type Playbook struct {
+	Name            string `json:"name"`              // 剧本名称
+	ContinueOnError bool   `json:"continue_on_error"` // 步骤失败时不标记主机失败，继续执行后续步骤
+	Steps           []Step `json:"steps"`             // 按顺序执行的步骤
+	dir             string // 剧本文件所在目录，用于解析上传的相对路径
+}
+
+// Step 剧本中的单个步骤，Run 和 Upload 必须且只能设置一个
+type Step struct {
+	Name            string    `json:"name"`                        // 步骤名称
+	Run             string    `json:"run,omitempty"`               // 要执行的远程命令
+	Upload          *Transfer `json:"upload,omitempty"`            // 要上传的文件
+	Hosts           []string  `json:"hosts,omitempty"`             // 主机筛选模式，为空表示全部主机
+	When            string    `json:"when,omitempty"`              // 执行条件：success（默认）、failure、always
+	WhenExit        []int     `json:"when_exit,omitempty"`         // 仅当该主机上一步的退出码在列表中时执行，优先于 When
+	ContinueOnError bool      `json:"continue_on_error,omitempty"` // 失败时不标记主机失败
+}
+
+// Transfer 文件上传描述
+type Transfer struct {
+	Src string `json:"src"` // 本地文件路径，相对路径基于剧本文件所在目录
+	Dst string `json:"dst"` // 远程目标路径
+}
+
+// PlaybookOptions 剧本执行选项
+type PlaybookOptions struct {
+	Check           bool // 检查模式：只计算执行计划，不连接主机
+	ContinueOnError bool // 强制所有步骤失败时继续，覆盖剧本和步骤中的设置
+}
+
+// StepHostResult 步骤在单台主机上的执行结果
+type StepHostResult struct {
+	Host     string        // 主机地址
+	Port     int           // 端口
+	Status   StepStatus    // 执行状态
+	ExitCode int           // 远程命令退出码
+	Output   string        // 命令输出内容
+	Err      error         // 错误信息
+	Duration time.Duration // 执行耗时
+}
+
+// StepReport 单个步骤的执行报告
+type StepReport struct {
+	Name    string           // 步骤名称
+	Results []StepHostResult // 每台主机的执行结果，顺序与主机清单一致
+}
+
+// PlaybookReport 剧本执行报告
+type PlaybookReport struct {
+	Name  string       // 剧本名称
+	Check bool         // 是否为检查模式
+	Steps []StepReport // 每个步骤的执行报告
+}
+
+// Failed 判断剧本执行过程中是否有主机失败
+//
+// 返回：
+//   - bool: 任一步骤在任一主机上的状态为 failed 时返回 true
+func (r *PlaybookReport) Failed() bool {
+	for _, step := range r.Steps {
+		for _, result := range step.Results {
+			if result.Status == StatusFailed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LoadPlaybook 读取并校验 JSON 格式的剧本文件
+//
+// 参数：
+//   - filePath: 剧本文件路径
+//
+// 返回：
+//   - *Playbook: 解析后的剧本
+//   - error: 读取、解析或校验失败时返回错误
+func LoadPlaybook(filePath string) (*Playbook, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取剧本文件失败: %w", err)
+	}
+
+	var pb Playbook
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&pb); err != nil {
+		return nil, fmt.Errorf("解析剧本文件失败: %w", err)
+	}
+
+	if pb.Name == "" {
+		pb.Name = filepath.Base(filePath)
+	}
+	pb.dir = filepath.Dir(filePath)
+
+	if err := pb.Validate(); err != nil {
+		return nil, err
+	}
+	return &pb, nil
+}
+
+// Validate 校验剧本的合法性，并为未命名的步骤生成名称
+//
+// 返回：
+//   - error: 剧本不合法时返回错误
+func (pb *Playbook) Validate() error {
+	if len(pb.Steps) == 0 {
+		return errors.New("剧本中没有任何步骤")
+	}
+
+	for i := range pb.Steps {
+		step := &pb.Steps[i]
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step %d", i+1)
+		}
+
+		hasRun := strings.TrimSpace(step.Run) != ""
+		hasUpload := step.Upload != nil
+		if hasRun == hasUpload {
+			return fmt.Errorf("步骤 %d (%s): run 和 upload 必须且只能设置一个", i+1, step.Name)
+		}
+		if hasUpload && (step.Upload.Src == "" || step.Upload.Dst == "") {
+			return fmt.Errorf("步骤 %d (%s): upload 的 src 和 dst 不能为空", i+1, step.Name)
+		}
+
+		switch step.When {
+		case "", WhenSuccess, WhenFailure, WhenAlways:
+		default:
+			return fmt.Errorf("步骤 %d (%s): 无效的执行条件 %q", i+1, step.Name, step.When)
+		}
+
+		for _, pattern := range step.Hosts {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("步骤 %d (%s): 无效的主机筛选模式 %q: %w", i+1, step.Name, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// hostState 剧本执行过程中单台主机的状态
+type hostState struct {
+	failed   bool // 是否有未被忽略的失败步骤
+	lastExit int  // 上一个已执行步骤的退出码
+}
+
+// RunPlaybook 在选中的主机上按顺序执行剧本中的步骤
+//
+// 执行规则：
+//   - 每个步骤在其选中的主机上并发执行，步骤之间严格按顺序执行
+//   - 步骤失败且未设置 continue_on_error 时，该主机被标记为失败，
+//     之后只执行 when 为 failure 或 always 的步骤
+//   - when_exit 根据该主机上一个已执行步骤的退出码决定是否执行
+//   - 检查模式下不连接主机，假设所有步骤成功（退出码为0）来计算执行计划
+//
+// 参数：
+//   - pb: 要执行的剧本
+//   - opts: 执行选项
+//
+// 返回：
+//   - *PlaybookReport: 执行报告
+//   - error: 加载主机清单失败时返回错误，主机执行失败记录在报告中
+func (e *EasySSH) RunPlaybook(pb *Playbook, opts PlaybookOptions) (*PlaybookReport, error) {
+	if pb == nil {
+		return nil, errors.New("剧本不能为空")
+	}
+	if err := pb.Validate(); err != nil {
+		return nil, err
+	}
+
+	hosts, err := e.SelectHosts()
+	if err != nil {
+		return nil, fmt.Errorf("解析主机清单失败: %w", err)
+	}
+
+	report := &PlaybookReport{Name: pb.Name, Check: opts.Check}
+	states := make([]hostState, len(hosts))
+
+	if e.ShowFormat {
+		mode := ""
+		if opts.Check {
+			mode = " [CHECK]"
+		}
+		fmt.Printf("==> PLAYBOOK %s%s (%d steps, %d hosts)\n\n", pb.Name, mode, len(pb.Steps), len(hosts))
+	}
+
+	for i, step := range pb.Steps {
+		continueOnError := opts.ContinueOnError || pb.ContinueOnError || step.ContinueOnError
+
+		// 按主机筛选模式和主机状态决定是否执行，需要执行的主机并发执行
+		results := runParallel(hosts, e.Parallel, func(j int, host HostConfig) StepHostResult {
+			result := StepHostResult{Host: host.Host, Port: host.Port, Status: StatusSkipped}
+			if selected, _ := matchHost(host, step.Hosts); !selected || !step.shouldRun(states[j]) {
+				return result
+			}
+			if opts.Check {
+				result.Status = StatusPlanned
+				return result
+			}
+			return e.runStep(pb, step, host)
+		})
+
+		// 更新主机状态（检查模式下假设执行成功）
+		for j, result := range results {
+			switch result.Status {
+			case StatusOK, StatusPlanned:
+				states[j].lastExit = 0
+			case StatusFailed:
+				states[j].lastExit = result.ExitCode
+				if continueOnError {
+					results[j].Status = StatusIgnored
+				} else {
+					states[j].failed = true
+				}
+			}
+		}
+
+		stepReport := StepReport{Name: step.Name, Results: results}
+		report.Steps = append(report.Steps, stepReport)
+		e.printStepReport(i+1, len(pb.Steps), stepReport)
+	}
+
+	e.printPlaybookRecap(report)
+	return report, nil
+}
+
+// shouldRun 根据主机状态判断步骤是否需要执行（私有方法）
+func (s Step) shouldRun(state hostState) bool {
+	if len(s.WhenExit) > 0 {
+		return slices.Contains(s.WhenExit, state.lastExit)
+	}
+	switch s.When {
+	case WhenAlways:
+		return true
+	case WhenFailure:
+		return state.failed
+	default:
+		return !state.failed
+	}
+}
+
+// runStep 在单台主机上执行步骤（私有方法）
+//
+// 参数：
+//   - pb: 步骤所属剧本
+//   - step: 要执行的步骤
+//   - host: 目标主机
+//
+// 返回：
+//   - StepHostResult: 执行结果，失败时状态为 failed
+func (e *EasySSH) runStep(pb *Playbook, step Step, host HostConfig) StepHostResult {
+	result := StepHostResult{Host: host.Host, Port: host.Port}
+	startTime := time.Now()
+
+	if step.Upload != nil {
+		src := step.Upload.Src
+		if !filepath.IsAbs(src) && pb.dir != "" {
+			src = filepath.Join(pb.dir, src)
+		}
+		result.Err = UploadFile(host, src, step.Upload.Dst, e.Timeout)
+		result.ExitCode = exitCodeOf(result.Err)
+	} else {
+		execResult := ExecRemoteCmd(host, step.Run, e.Timeout)
+		result.Output = execResult.Output
+		result.Err = execResult.Err
+		result.ExitCode = execResult.ExitCode
+	}
+
+	result.Duration = time.Since(startTime)
+	result.Status = StatusOK
+	if result.Err != nil {
+		result.Status = StatusFailed
+	}
+	return result
+}
+
+// printStepReport 打印单个步骤的执行结果（私有方法）
+func (e *EasySSH) printStepReport(index, total int, step StepReport) {
+	if !e.ShowFormat {
+		return
+	}
+
+	fmt.Printf("==> [%d/%d] %s\n", index, total, step.Name)
+	fmt.Println("----------------------------------------")
+	for _, result := range step.Results {
+		hostLabel := fmt.Sprintf("%s:%d", result.Host, result.Port)
+		switch result.Status {
+		case StatusOK:
+			fmt.Printf("%-20s : [ ✓ ok ]\n", hostLabel)
+		case StatusFailed:
+			fmt.Printf("%-20s : [ ✗ failed (exit %d) ]\n", hostLabel, result.ExitCode)
+		case StatusIgnored:
+			fmt.Printf("%-20s : [ ! ignored (exit %d) ]\n", hostLabel, result.ExitCode)
+		case StatusPlanned:
+			fmt.Printf("%-20s : [ ? would run ]\n", hostLabel)
+		case StatusSkipped:
+			fmt.Printf("%-20s : [ - skipped ]\n", hostLabel)
+		}
+
+		if !e.ShowOutput {
+			continue
+		}
+		if output := strings.TrimSpace(result.Output); output != "" {
+			fmt.Printf("    %s\n", strings.ReplaceAll(output, "\n", "\n    "))
+		} else if result.Err != nil {
+			fmt.Printf("    %v\n", result.Err)
+		}
+	}
+	fmt.Println()
+}
+
+// printPlaybookRecap 打印每台主机的执行汇总（私有方法）
+func (e *EasySSH) printPlaybookRecap(report *PlaybookReport) {
+	if !e.ShowFormat || len(report.Steps) == 0 {
+		return
+	}
+
+	fmt.Println("==> RECAP")
+	fmt.Println("----------------------------------------")
+	for j, first := range report.Steps[0].Results {
+		counts := make(map[StepStatus]int)
+		for _, step := range report.Steps {
+			counts[step.Results[j].Status]++
+		}
+		hostLabel := fmt.Sprintf("%s:%d", first.Host, first.Port)
+		if report.Check {
+			fmt.Printf("%-20s : planned=%d skipped=%d\n", hostLabel, counts[StatusPlanned], counts[StatusSkipped])
+			continue
+		}
+		fmt.Printf("%-20s : ok=%d failed=%d ignored=%d skipped=%d\n",
+			hostLabel, counts[StatusOK], counts[StatusFailed], counts[StatusIgnored], counts[StatusSkipped])
+	}
+	fmt.Println()
+}
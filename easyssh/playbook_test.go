@@ -0,0 +1,155 @@
+package easyssh
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePlaybook 创建测试用剧本文件
+func writePlaybook(t *testing.T, dir, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "play.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create playbook: %v", err)
+	}
+	return path
+}
+
+func TestLoadPlaybook(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "Valid", content: `{"name":"p","steps":[{"run":"true"},{"upload":{"src":"a","dst":"/tmp/"}}]}`},
+		{name: "No steps", content: `{"name":"p","steps":[]}`, wantErr: "没有任何步骤"},
+		{name: "Run and upload", content: `{"steps":[{"run":"true","upload":{"src":"a","dst":"b"}}]}`, wantErr: "必须且只能"},
+		{name: "Neither", content: `{"steps":[{"name":"x"}]}`, wantErr: "必须且只能"},
+		{name: "Invalid when", content: `{"steps":[{"run":"true","when":"sometimes"}]}`, wantErr: "无效的执行条件"},
+		{name: "Invalid hosts", content: `{"steps":[{"run":"true","hosts":["[a"]}]}`, wantErr: "无效的主机筛选模式"},
+		{name: "Unknown field", content: `{"steps":[{"run":"true","retries":3}]}`, wantErr: "解析剧本文件失败"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pb, err := LoadPlaybook(writePlaybook(t, t.TempDir(), tt.content))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadPlaybook() error = %v", err)
+				}
+				if pb.Steps[0].Name != "step 1" {
+					t.Errorf("Unnamed step should be named %q, got %q", "step 1", pb.Steps[0].Name)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadPlaybook() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// stepStatuses 返回报告中第一台主机在各步骤的状态
+func stepStatuses(report *PlaybookReport) []StepStatus {
+	statuses := make([]StepStatus, 0, len(report.Steps))
+	for _, step := range report.Steps {
+		statuses = append(statuses, step.Results[0].Status)
+	}
+	return statuses
+}
+
+func TestRunPlaybook(t *testing.T) {
+	host := startTestServer(t, "secret")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "payload.txt"), []byte("payload"), 0644); err != nil {
+		t.Fatalf("Failed to create payload: %v", err)
+	}
+	remote := filepath.Join(dir, "remote")
+	if err := os.Mkdir(remote, 0755); err != nil {
+		t.Fatalf("Failed to create remote dir: %v", err)
+	}
+
+	pb, err := LoadPlaybook(writePlaybook(t, dir, `{
+		"name": "test",
+		"steps": [
+			{"name": "upload", "upload": {"src": "payload.txt", "dst": "`+remote+`"}},
+			{"name": "check", "run": "test -f `+remote+`/payload.txt"},
+			{"name": "probe", "run": "exit 3", "continue_on_error": true},
+			{"name": "on exit 3", "run": "echo handled", "when_exit": [3]},
+			{"name": "on exit 4", "run": "echo nope", "when_exit": [4]},
+			{"name": "break", "run": "exit 1"},
+			{"name": "after failure", "run": "true"},
+			{"name": "rescue", "run": "echo rescue", "when": "failure"},
+			{"name": "cleanup", "run": "true", "when": "always"},
+			{"name": "other hosts", "run": "true", "hosts": ["10.*"]}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("LoadPlaybook() error = %v", err)
+	}
+
+	t.Run("Run", func(t *testing.T) {
+		e := newTestEasySSH(t, host)
+		report, err := e.RunPlaybook(pb, PlaybookOptions{})
+		if err != nil {
+			t.Fatalf("RunPlaybook() error = %v", err)
+		}
+
+		want := []StepStatus{
+			StatusOK, StatusOK, StatusIgnored, StatusOK, StatusSkipped,
+			StatusFailed, StatusSkipped, StatusOK, StatusOK, StatusSkipped,
+		}
+		got := stepStatuses(report)
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("step %d (%s) status = %s, want %s", i+1, report.Steps[i].Name, got[i], want[i])
+			}
+		}
+		if exitCode := report.Steps[2].Results[0].ExitCode; exitCode != 3 {
+			t.Errorf("probe ExitCode = %d, want 3", exitCode)
+		}
+		if output := strings.TrimSpace(report.Steps[3].Results[0].Output); output != "handled" {
+			t.Errorf("handler output = %q, want %q", output, "handled")
+		}
+		if !report.Failed() {
+			t.Error("report.Failed() should be true")
+		}
+	})
+
+	t.Run("Continue on error", func(t *testing.T) {
+		e := newTestEasySSH(t, host)
+		report, err := e.RunPlaybook(pb, PlaybookOptions{ContinueOnError: true})
+		if err != nil {
+			t.Fatalf("RunPlaybook() error = %v", err)
+		}
+		got := stepStatuses(report)
+		if got[5] != StatusIgnored || got[6] != StatusOK || got[7] != StatusSkipped {
+			t.Errorf("statuses after ignored failure = %v", got[5:8])
+		}
+		if report.Failed() {
+			t.Error("report.Failed() should be false when errors are ignored")
+		}
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		// 检查模式不连接主机，使用无法连接的地址
+		e := newTestEasySSH(t, HostConfig{Host: "192.0.2.1", Port: 22, Username: "u", Password: "p"})
+		report, err := e.RunPlaybook(pb, PlaybookOptions{Check: true})
+		if err != nil {
+			t.Fatalf("RunPlaybook() error = %v", err)
+		}
+		want := []StepStatus{
+			StatusPlanned, StatusPlanned, StatusPlanned, StatusSkipped, StatusSkipped,
+			StatusPlanned, StatusPlanned, StatusSkipped, StatusPlanned, StatusSkipped,
+		}
+		got := stepStatuses(report)
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("step %d (%s) status = %s, want %s", i+1, report.Steps[i].Name, got[i], want[i])
+			}
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package easyssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// startTestServer 启动一个只支持密码认证的本地 SSH 服务端
+// exec 请求通过本机 sh -c 执行，可用于测试命令执行和 scp 传输
+//
+// 返回服务端对应的主机配置，测试结束时自动关闭
+func startTestServer(t *testing.T, password string) HostConfig {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate host key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(_ ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if string(pass) == password {
+				return nil, nil
+			}
+			return nil, errors.New("wrong password")
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConn(conn, config)
+		}
+	}()
+
+	return listenerHost(t, listener, password)
+}
+
+// serveTestConn 处理单个测试连接上的会话
+func serveTestConn(conn net.Conn, config *ssh.ServerConfig) {
+	defer func() { _ = conn.Close() }()
+
+	sshConn, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	defer func() { _ = sshConn.Close() }()
+	go ssh.DiscardRequests(reqs)
+
+	for newCh := range chans {
+		if newCh.ChannelType() != "session" {
+			_ = newCh.Reject(ssh.UnknownChannelType, "unsupported channel")
+			continue
+		}
+		ch, chReqs, err := newCh.Accept()
+		if err != nil {
+			continue
+		}
+		go serveTestSession(ch, chReqs)
+	}
+}
+
+// serveTestSession 处理会话上的 exec 和 shell 请求
+// shell 请求启动非交互式 sh，从会话输入读取命令
+func serveTestSession(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	defer func() { _ = ch.Close() }()
+
+	for req := range reqs {
+		var cmd *exec.Cmd
+		switch {
+		case req.Type == "pty-req" || req.Type == "window-change":
+			_ = req.Reply(true, nil)
+			continue
+		case req.Type == "shell":
+			cmd = exec.Command("sh")
+		case req.Type == "exec" && len(req.Payload) >= 4:
+			cmdLen := binary.BigEndian.Uint32(req.Payload)
+			cmd = exec.Command("sh", "-c", string(req.Payload[4:4+cmdLen]))
+		default:
+			_ = req.Reply(false, nil)
+			continue
+		}
+		_ = req.Reply(true, nil)
+
+		cmd.Stdin = ch
+		cmd.Stdout = ch
+		cmd.Stderr = ch.Stderr()
+		cmd.WaitDelay = time.Second
+
+		status := uint32(0)
+		if err := cmd.Run(); err != nil {
+			status = 255
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				status = uint32(exitErr.ExitCode())
+			}
+		}
+		_, _ = ch.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, status))
+		return
+	}
+}
+
+// listenerHost 根据监听地址生成主机配置
+func listenerHost(t *testing.T, listener net.Listener, password string) HostConfig {
+	t.Helper()
+
+	host, portStr, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to parse listener address: %v", err)
+	}
+	port, _ := strconv.Atoi(portStr)
+	return HostConfig{Host: host, Port: port, Username: "tester", Password: password}
+}
+
+// newTestEasySSH 创建使用给定主机的 EasySSH 实例（不打印输出）
+func newTestEasySSH(t *testing.T, hosts ...HostConfig) *EasySSH {
+	t.Helper()
+
+	content := ""
+	for _, h := range hosts {
+		content += h.Host + " " + strconv.Itoa(h.Port) + " " + h.Username + " " + h.Password + "\n"
+	}
+	return New(writeHostsFile(t, content), 0, false, false)
+}
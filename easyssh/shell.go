@@ -0,0 +1,154 @@
+package easyssh
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+// 本地终端类型未知时请求的远程终端类型
+const defaultTermType = "xterm-256color"
+
+// 本地终端尺寸无法获取时使用的默认窗口大小
+const (
+	defaultTermWidth  = 80
+	defaultTermHeight = 24
+)
+
+// Shell 在选中的单台主机上打开交互式 shell 会话
+//
+// 参数：
+//   - pattern: 主机筛选模式（语法同 Patterns），在 Patterns 筛选结果中必须恰好匹配一台主机
+//
+// 返回：
+//   - error: 主机匹配数量不为 1、连接失败或远程 shell 非零退出时返回错误
+func (e *EasySSH) Shell(pattern string) error {
+	hosts, err := e.SelectHosts()
+	if err != nil {
+		return fmt.Errorf("解析主机清单失败: %w", err)
+	}
+	hosts, err = filterHosts(hosts, []string{pattern})
+	if err != nil {
+		return err
+	}
+
+	switch len(hosts) {
+	case 0:
+		return fmt.Errorf("没有匹配 %q 的主机", pattern)
+	case 1:
+		return OpenShell(hosts[0], e.Timeout)
+	default:
+		return fmt.Errorf("模式 %q 匹配了 %d 台主机，交互式会话只能连接一台主机", pattern, len(hosts))
+	}
+}
+
+// OpenShell 连接远程主机并打开交互式 shell，使用标准输入输出作为会话终端
+//
+// 标准输入为终端时，本地终端切换为原始模式，按本地终端大小请求远程伪终端，
+// 并在本地窗口大小变化时同步到远程；会话结束后恢复本地终端状态。
+// 标准输入不是终端（如管道）时不请求伪终端，输入内容直接交给远程 shell 执行。
+//
+// 参数：
+//   - host: 主机信息结构体，认证方式与 ExecRemoteCmd 相同
+//   - timeout: 连接超时时间（零值表示不设置超时）
+//
+// 返回：
+//   - error: 连接失败、终端设置失败或远程 shell 非零退出时返回错误（可通过 *ssh.ExitError 获取退出码）
+func OpenShell(host HostConfig, timeout time.Duration) error {
+	return openShell(host, timeout, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// openShell 使用指定的输入输出打开交互式 shell（私有函数）
+//
+// 参数：
+//   - host: 主机信息结构体
+//   - timeout: 连接超时时间
+//   - stdin: 会话输入，为终端时启用原始模式和伪终端
+//   - stdout: 会话标准输出
+//   - stderr: 会话标准错误输出
+//
+// 返回：
+//   - error: 会话过程中的错误
+func openShell(host HostConfig, timeout time.Duration, stdin *os.File, stdout, stderr io.Writer) error {
+	client, err := dialHost(host, timeout)
+	if err != nil {
+		return err
+	}
+	defer closeClient(client)
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("创建SSH会话失败: %w", err)
+	}
+	defer closeSession(session)
+
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	fd := int(stdin.Fd())
+	if term.IsTerminal(fd) {
+		width, height := terminalSize(fd)
+		if err := session.RequestPty(termType(), height, width, ssh.TerminalModes{
+			ssh.ECHO:          1,
+			ssh.TTY_OP_ISPEED: 14400,
+			ssh.TTY_OP_OSPEED: 14400,
+		}); err != nil {
+			return fmt.Errorf("请求伪终端失败: %w", err)
+		}
+
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("设置终端原始模式失败: %w", err)
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+
+		stop := watchWindowSize(fd, func(width, height int) {
+			_ = session.WindowChange(height, width)
+		})
+		defer stop()
+	}
+
+	if err := session.Shell(); err != nil {
+		return fmt.Errorf("启动远程shell失败: %w", err)
+	}
+	if err := session.Wait(); err != nil {
+		// 连接断开时服务端可能不发送退出状态，不视为错误
+		var missingErr *ssh.ExitMissingError
+		if errors.As(err, &missingErr) {
+			return nil
+		}
+		return fmt.Errorf("远程shell退出: %w", err)
+	}
+	return nil
+}
+
+// terminalSize 获取本地终端大小，获取失败时返回默认大小（私有函数）
+//
+// 参数：
+//   - fd: 终端文件描述符
+//
+// 返回：
+//   - int: 宽度（列数）
+//   - int: 高度（行数）
+func terminalSize(fd int) (int, int) {
+	width, height, err := term.GetSize(fd)
+	if err != nil || width <= 0 || height <= 0 {
+		return defaultTermWidth, defaultTermHeight
+	}
+	return width, height
+}
+
+// termType 返回请求远程伪终端时使用的终端类型，优先使用本地 $TERM（私有函数）
+func termType() string {
+	if t := strings.TrimSpace(os.Getenv("TERM")); t != "" {
+		return t
+	}
+	return defaultTermType
+}
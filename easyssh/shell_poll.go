@@ -0,0 +1,40 @@
+//go:build !unix
+
+package easyssh
+
+import "time"
+
+// Windows 控制台等没有 SIGWINCH 信号的平台改为定时检查窗口大小
+const windowPollInterval = 500 * time.Millisecond
+
+// watchWindowSize 定时检查本地终端大小，变化时回调新的大小
+//
+// 参数：
+//   - fd: 终端文件描述符
+//   - onResize: 大小变化时的回调函数
+//
+// 返回：
+//   - func(): 停止监听的函数
+func watchWindowSize(fd int, onResize func(width, height int)) func() {
+	ticker := time.NewTicker(windowPollInterval)
+	done := make(chan struct{})
+
+	go func() {
+		defer ticker.Stop()
+		lastWidth, lastHeight := terminalSize(fd)
+		for {
+			select {
+			case <-ticker.C:
+				width, height := terminalSize(fd)
+				if width != lastWidth || height != lastHeight {
+					lastWidth, lastHeight = width, height
+					onResize(width, height)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
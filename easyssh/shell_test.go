@@ -0,0 +1,83 @@
+package easyssh
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// pipeInput 返回一个内容为 input 的管道读端，模拟非终端标准输入
+func pipeInput(t *testing.T, input string) *os.File {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+
+	go func() {
+		_, _ = w.WriteString(input)
+		_ = w.Close()
+	}()
+	return r
+}
+
+func TestOpenShell(t *testing.T) {
+	host := startTestServer(t, "secret")
+
+	t.Run("Pipe input", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		err := openShell(host, 0, pipeInput(t, "echo hello\necho oops >&2\n"), &stdout, &stderr)
+		if err != nil {
+			t.Fatalf("openShell failed: %v", err)
+		}
+		if stdout.String() != "hello\n" {
+			t.Errorf("stdout = %q, want %q", stdout.String(), "hello\n")
+		}
+		if stderr.String() != "oops\n" {
+			t.Errorf("stderr = %q, want %q", stderr.String(), "oops\n")
+		}
+	})
+
+	t.Run("Exit code", func(t *testing.T) {
+		// 会话在各自的协程中复制标准输出和标准错误，不能共用同一个 bytes.Buffer
+		var stdout, stderr bytes.Buffer
+		err := openShell(host, 0, pipeInput(t, "exit 3\n"), &stdout, &stderr)
+		if err == nil {
+			t.Fatal("Expected error for non-zero exit")
+		}
+		if code := exitCodeOf(err); code != 3 {
+			t.Errorf("exitCodeOf = %d, want 3", code)
+		}
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		bad := host
+		bad.Password = "wrong"
+		var stdout, stderr bytes.Buffer
+		if err := openShell(bad, 0, pipeInput(t, ""), &stdout, &stderr); err == nil {
+			t.Fatal("Expected authentication error")
+		}
+	})
+}
+
+func TestShellHostSelection(t *testing.T) {
+	e := New(writeHostsFile(t, "10.0.0.1 root pass\n10.0.0.2 root pass\n"), 0, false, false)
+
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"192.168.*", "没有匹配"},
+		{"10.0.0.*", "匹配了 2 台主机"},
+		{"[", "syntax error"},
+	}
+	for _, tt := range tests {
+		err := e.Shell(tt.pattern)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Shell(%q) error = %v, want containing %q", tt.pattern, err, tt.want)
+		}
+	}
+}
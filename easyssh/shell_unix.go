@@ -0,0 +1,39 @@
+//go:build unix
+
+package easyssh
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchWindowSize 监听 SIGWINCH 信号，本地终端大小变化时回调新的大小
+//
+// 参数：
+//   - fd: 终端文件描述符
+//   - onResize: 大小变化时的回调函数
+//
+// 返回：
+//   - func(): 停止监听的函数
+func watchWindowSize(fd int, onResize func(width, height int)) func() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGWINCH)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-sigCh:
+				onResize(terminalSize(fd))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
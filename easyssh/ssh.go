@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -102,83 +103,144 @@ func ExecRemoteCmd(host HostConfig, cmd string, timeout time.Duration) RemoteExe
 	// 1. 校验入参合法性
 	if err := validateHostConfig(host); err != nil {
 		return RemoteExecResult{
-			Success: false,
-			Output:  "",
-			Err:     err,
+			Success:  false,
+			Output:   "",
+			Err:      err,
+			ExitCode: -1,
 		}
 	}
 	if strings.TrimSpace(cmd) == "" {
 		return RemoteExecResult{
-			Success: false,
-			Output:  "",
-			Err:     errors.New("执行的命令不能为空"),
+			Success:  false,
+			Output:   "",
+			Err:      errors.New("执行的命令不能为空"),
+			ExitCode: -1,
 		}
 	}
 
-	// 2. 配置SSH客户端参数
-	config := &ssh.ClientConfig{
-		User: host.Username,
-		Auth: []ssh.AuthMethod{
-			ssh.Password(host.Password), // 密码认证
-		},
-		// 生产环境需替换为 ssh.FixedHostKey(hostKey) 进行主机密钥校验
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         timeout, // TCP连接超时时间
-	}
-
-	// 3. 建立SSH连接
-	addr := fmt.Sprintf("%s:%d", host.Host, host.Port)
-	client, err := ssh.Dial("tcp", addr, config)
+	// 2. 建立SSH连接
+	client, err := dialHost(host, timeout)
 	if err != nil {
 		return RemoteExecResult{
-			Success: false,
-			Output:  "",
-			Err:     fmt.Errorf("SSH连接失败: %w", err),
+			Success:  false,
+			Output:   "",
+			Err:      err,
+			ExitCode: -1,
 		}
 	}
-	defer func() {
-		if closeErr := client.Close(); closeErr != nil {
-			// EOF是SSH连接关闭时的正常情况，不需要记录为错误
-			if !errors.Is(closeErr, io.EOF) {
-				fmt.Printf("关闭SSH客户端失败: %v\n", closeErr)
-			}
-		}
-	}() // 延迟关闭客户端连接
+	defer closeClient(client) // 延迟关闭客户端连接
 
-	// 4. 创建SSH会话
+	// 3. 创建SSH会话
 	session, err := client.NewSession()
 	if err != nil {
 		return RemoteExecResult{
-			Success: false,
-			Output:  "",
-			Err:     fmt.Errorf("创建SSH会话失败: %w", err),
+			Success:  false,
+			Output:   "",
+			Err:      fmt.Errorf("创建SSH会话失败: %w", err),
+			ExitCode: -1,
 		}
 	}
-	defer func() {
-		if closeErr := session.Close(); closeErr != nil {
-			// EOF是SSH会话关闭时的正常情况，不需要记录为错误
-			if !errors.Is(closeErr, io.EOF) {
-				fmt.Printf("关闭SSH会话失败: %v\n", closeErr)
-			}
-		}
-	}() // 延迟关闭会话
+	defer closeSession(session) // 延迟关闭会话
 
-	// 5. 执行远程命令并获取输出
+	// 4. 执行远程命令并获取输出
 	output, err := session.CombinedOutput(cmd)
 
 	if err != nil {
 		return RemoteExecResult{
-			Success: false,
-			Output:  string(output),
-			Err:     fmt.Errorf("命令执行失败: %w", err),
+			Success:  false,
+			Output:   string(output),
+			Err:      fmt.Errorf("命令执行失败: %w", err),
+			ExitCode: exitCodeOf(err),
 		}
 	}
 
-	// 6. 执行成功返回结果
+	// 5. 执行成功返回结果
 	return RemoteExecResult{
-		Success: true,
-		Output:  string(output),
-		Err:     nil,
+		Success:  true,
+		Output:   string(output),
+		Err:      nil,
+		ExitCode: 0,
+	}
+}
+
+// exitCodeOf 从错误中提取远程命令的退出码
+//
+// 参数：
+//   - err: 远程操作返回的错误（可以是包装后的错误）
+//
+// 返回：
+//   - int: nil 返回 0，远程命令非零退出返回其退出码，其他错误返回 -1
+func exitCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus()
+	}
+	return -1
+}
+
+// dialHost 校验主机配置并建立SSH连接
+// 所有远程操作（命令执行、文件传输等）共用此函数，保证认证方式一致
+//
+// 参数：
+//   - host: 主机信息结构体
+//   - timeout: 连接超时时间（零值表示不设置超时）
+//
+// 返回：
+//   - *ssh.Client: 已建立的SSH客户端，调用方负责关闭
+//   - error: 校验或连接失败时返回错误
+func dialHost(host HostConfig, timeout time.Duration) (*ssh.Client, error) {
+	if err := validateHostConfig(host); err != nil {
+		return nil, err
+	}
+
+	addr := net.JoinHostPort(host.Host, strconv.Itoa(host.Port))
+	client, err := ssh.Dial("tcp", addr, newClientConfig(host, timeout))
+	if err != nil {
+		return nil, fmt.Errorf("SSH连接失败: %w", err)
+	}
+	return client, nil
+}
+
+// newClientConfig 根据主机配置构建SSH客户端参数
+//
+// 参数：
+//   - host: 主机信息结构体
+//   - timeout: 连接超时时间（零值表示不设置超时）
+//
+// 返回：
+//   - *ssh.ClientConfig: SSH客户端参数
+func newClientConfig(host HostConfig, timeout time.Duration) *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User: host.Username,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(host.Password), // 密码认证
+		},
+		// 生产环境需替换为 ssh.FixedHostKey(hostKey) 进行主机密钥校验
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         timeout, // TCP连接超时时间
+	}
+}
+
+// closeClient 关闭SSH客户端，忽略连接关闭时正常出现的 EOF
+func closeClient(client *ssh.Client) {
+	if closeErr := client.Close(); closeErr != nil {
+		// EOF是SSH连接关闭时的正常情况，不需要记录为错误
+		if !errors.Is(closeErr, io.EOF) {
+			fmt.Printf("关闭SSH客户端失败: %v\n", closeErr)
+		}
+	}
+}
+
+// closeSession 关闭SSH会话，忽略会话关闭时正常出现的 EOF
+func closeSession(session *ssh.Session) {
+	if closeErr := session.Close(); closeErr != nil {
+		// EOF是SSH会话关闭时的正常情况，不需要记录为错误
+		if !errors.Is(closeErr, io.EOF) {
+			fmt.Printf("关闭SSH会话失败: %v\n", closeErr)
+		}
 	}
 }
 
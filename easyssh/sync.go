@@ -0,0 +1,565 @@
+package easyssh
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gitee.com/MM-Q/go-kit/hash"
+	"golang.org/x/crypto/ssh"
+)
+
+// localFile 本地待同步文件的信息
+type localFile struct {
+	path string      // 本地绝对路径
+	info fs.FileInfo // 文件信息
+}
+
+// remoteFile 远程已有文件的信息
+type remoteFile struct {
+	size  int64 // 文件大小
+	mtime int64 // 修改时间（Unix 秒）
+}
+
+// localTree 本地同步目录的文件列表，多台主机同步时共享，本地哈希只计算一次
+type localTree struct {
+	root  string               // 本地根目录
+	files map[string]localFile // 相对路径 -> 文件信息
+	names []string             // 按字典序排列的相对路径
+
+	// skipped 未同步的本地条目（特殊文件、指向目录或不存在目标的符号链接），远程同名路径及其下的文件不会被删除
+	skipped []string
+
+	mu     sync.Mutex
+	hashes map[string]string // 相对路径 -> SHA256 哈希
+}
+
+// Sync 将本地目录同步到所有选中主机的远程目录并打印每台主机的变更统计
+//
+// 参数：
+//   - localDir: 本地源目录
+//   - remoteDir: 远程目标目录，不存在时自动创建
+//   - opts: 同步选项
+//
+// 返回：
+//   - error: 如果本地目录无效或解析主机文件失败，返回错误
+func (e *EasySSH) Sync(localDir, remoteDir string, opts SyncOptions) error {
+	results, err := e.SyncRaw(localDir, remoteDir, opts)
+	if err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("SYNC %s -> %s", localDir, remoteDir)
+	if opts.DryRun {
+		description += " [DRY RUN]"
+	}
+	if len(results) == 0 {
+		if e.ShowFormat {
+			fmt.Printf("==> 跳过 %s: 主机清单为空\n", description)
+		}
+		return nil
+	}
+
+	if e.ShowFormat {
+		fmt.Printf("==> %s (%d hosts)\n", description, len(results))
+		fmt.Println("----------------------------------------")
+	}
+
+	successCount := 0
+	for _, result := range results {
+		hostLabel := fmt.Sprintf("%s:%d", result.Host, result.Port)
+		if !result.Success {
+			if e.ShowFormat {
+				fmt.Printf("%-20s : [ ✗ failed ]\n", hostLabel)
+			}
+			if e.ShowOutput && result.Err != nil {
+				fmt.Printf("    %v\n", result.Err)
+			}
+			continue
+		}
+
+		successCount++
+		if e.ShowFormat {
+			fmt.Printf("%-20s : [ ✓ ok ] added=%d changed=%d deleted=%d unchanged=%d\n",
+				hostLabel, len(result.Added), len(result.Changed), len(result.Deleted), result.Unchanged)
+		}
+		if e.ShowOutput {
+			printSyncFiles("+", result.Added)
+			printSyncFiles("~", result.Changed)
+			printSyncFiles("-", result.Deleted)
+		}
+	}
+
+	if e.ShowFormat {
+		fmt.Println("----------------------------------------")
+		fmt.Printf("==> 成功: %d/%d | 失败: %d/%d\n\n", successCount, len(results), len(results)-successCount, len(results))
+	}
+	return nil
+}
+
+// printSyncFiles 按行打印带标记的文件列表（私有函数）
+func printSyncFiles(mark string, files []string) {
+	for _, name := range files {
+		fmt.Printf("    %s %s\n", mark, name)
+	}
+}
+
+// SyncRaw 将本地目录同步到所有选中主机的远程目录，返回原始结果（不打印任何输出）
+//
+// 参数：
+//   - localDir: 本地源目录
+//   - remoteDir: 远程目标目录，不存在时自动创建
+//   - opts: 同步选项
+//
+// 返回：
+//   - []SyncResult: 每台主机的同步结果
+//   - error: 如果本地目录无效或解析主机文件失败，返回错误
+func (e *EasySSH) SyncRaw(localDir, remoteDir string, opts SyncOptions) ([]SyncResult, error) {
+	hosts, err := e.SelectHosts()
+	if err != nil {
+		return nil, fmt.Errorf("解析主机清单失败: %w", err)
+	}
+
+	tree, err := scanLocalTree(localDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return runParallel(hosts, e.Parallel, func(_ int, host HostConfig) SyncResult {
+		return syncHost(host, tree, remoteDir, opts, e.Timeout)
+	}), nil
+}
+
+// SyncDir 将本地目录同步到单台远程主机
+//
+// 同步流程：
+//  1. 通过远程 find 列出目标目录中的文件及其大小和修改时间
+//  2. 大小不同的文件直接视为变化；大小和修改时间都相同的文件视为未变化（Checksum 选项除外）
+//  3. 其余文件比较本地与远程（sha256sum）的 SHA256 哈希，内容相同时将远程修改时间更新为本地值
+//  4. 通过 SCP 上传新增和变化的文件（保留权限和修改时间），按需删除远程多余文件
+//
+// 参数：
+//   - host: 主机信息结构体，包含连接信息（主机地址、端口、用户名、密码）
+//   - localDir: 本地源目录，只同步其中的普通文件（跟随指向普通文件的符号链接，忽略其他特殊文件）
+//   - remoteDir: 远程目标目录，不存在时自动创建
+//   - opts: 同步选项
+//   - timeout: 连接超时时间（零值表示不设置超时）
+//
+// 返回：
+//   - SyncResult: 同步结果，失败时 Err 非 nil
+//
+// 注意：
+//   - 远程主机需要提供 GNU find、sha256sum、xargs 和 scp 命令
+//   - 不会删除远程目录中的空目录
+//   - 被忽略的本地条目在远程的同名路径（包括其下的文件）不会被 Delete 删除
+func SyncDir(host HostConfig, localDir, remoteDir string, opts SyncOptions, timeout time.Duration) SyncResult {
+	tree, err := scanLocalTree(localDir)
+	if err != nil {
+		return SyncResult{Host: host.Host, Port: host.Port, Err: err}
+	}
+	return syncHost(host, tree, remoteDir, opts, timeout)
+}
+
+// syncHost 使用已扫描的本地文件列表同步单台主机（私有函数）
+//
+// 参数：
+//   - host: 主机信息结构体
+//   - tree: 本地文件列表
+//   - remoteDir: 远程目标目录
+//   - opts: 同步选项
+//   - timeout: 连接超时时间
+//
+// 返回：
+//   - SyncResult: 同步结果
+func syncHost(host HostConfig, tree *localTree, remoteDir string, opts SyncOptions, timeout time.Duration) SyncResult {
+	start := time.Now()
+	result := SyncResult{Host: host.Host, Port: host.Port}
+	err := func() error {
+		if strings.TrimSpace(remoteDir) == "" {
+			return errors.New("远程目录不能为空")
+		}
+
+		client, err := dialHost(host, timeout)
+		if err != nil {
+			return err
+		}
+		defer closeClient(client)
+
+		remote, err := listRemoteFiles(client, remoteDir)
+		if err != nil {
+			return err
+		}
+
+		// 按大小和修改时间初步比较，无法确定的文件留待哈希比较
+		var candidates, touched []string
+		for _, name := range tree.names {
+			local := tree.files[name]
+			rf, ok := remote[name]
+			switch {
+			case !ok:
+				result.Added = append(result.Added, name)
+			case rf.size != local.info.Size():
+				result.Changed = append(result.Changed, name)
+			case rf.mtime == local.info.ModTime().Unix() && !opts.Checksum:
+				result.Unchanged++
+			default:
+				candidates = append(candidates, name)
+			}
+		}
+
+		if len(candidates) > 0 {
+			remoteHashes, err := remoteChecksums(client, remoteDir, candidates)
+			if err != nil {
+				return err
+			}
+			for _, name := range candidates {
+				localHash, err := tree.checksum(name)
+				if err != nil {
+					return err
+				}
+				if remoteHashes[name] == localHash {
+					result.Unchanged++
+					if remote[name].mtime != tree.files[name].info.ModTime().Unix() {
+						touched = append(touched, name)
+					}
+				} else {
+					result.Changed = append(result.Changed, name)
+				}
+			}
+			slices.Sort(result.Changed)
+		}
+
+		if opts.Delete {
+			for name := range remote {
+				if _, ok := tree.files[name]; !ok && !tree.isSkipped(name) {
+					result.Deleted = append(result.Deleted, name)
+				}
+			}
+			slices.Sort(result.Deleted)
+		}
+
+		if opts.DryRun {
+			return nil
+		}
+
+		uploads := append(slices.Clone(result.Added), result.Changed...)
+		if len(uploads) > 0 {
+			if err := uploadSyncFiles(client, tree, remoteDir, uploads); err != nil {
+				return err
+			}
+		}
+		if len(touched) > 0 {
+			if err := touchRemoteFiles(client, tree, remoteDir, touched); err != nil {
+				return err
+			}
+		}
+		if len(result.Deleted) > 0 {
+			if _, err := runRemoteCommand(client, "cd -- "+shellQuote(remoteDir)+" && xargs -0 rm -f --", nulJoin(result.Deleted)); err != nil {
+				return fmt.Errorf("删除远程文件失败: %w", err)
+			}
+		}
+		return nil
+	}()
+
+	result.Duration = time.Since(start)
+	result.Err = err
+	result.Success = err == nil
+	return result
+}
+
+// scanLocalTree 扫描本地目录中的所有普通文件（私有函数）
+// 指向普通文件的符号链接按目标文件同步，其他非普通文件记录在 skipped 中
+//
+// 参数：
+//   - localDir: 本地目录
+//
+// 返回：
+//   - *localTree: 本地文件列表
+//   - error: 目录不存在、不是目录或遍历失败时返回错误
+func scanLocalTree(localDir string) (*localTree, error) {
+	info, err := os.Stat(localDir)
+	if err != nil {
+		return nil, fmt.Errorf("获取本地目录信息失败: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("本地路径 '%s' 不是目录", localDir)
+	}
+
+	tree := &localTree{root: localDir, files: make(map[string]localFile), hashes: make(map[string]string)}
+	err = filepath.WalkDir(localDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(localDir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+
+		var fi fs.FileInfo
+		switch {
+		case d.Type().IsRegular():
+			fi, err = d.Info()
+		case d.Type()&fs.ModeSymlink != 0:
+			fi, err = os.Stat(p)
+			if os.IsNotExist(err) {
+				err = nil // 目标不存在的符号链接
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if fi == nil || !fi.Mode().IsRegular() {
+			tree.skipped = append(tree.skipped, name)
+			return nil
+		}
+		tree.files[name] = localFile{path: p, info: fi}
+		tree.names = append(tree.names, name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("遍历本地目录失败: %w", err)
+	}
+
+	slices.Sort(tree.names)
+	return tree, nil
+}
+
+// isSkipped 判断远程路径是否对应被忽略的本地条目或位于其下（如指向目录的符号链接）
+func (t *localTree) isSkipped(name string) bool {
+	for _, skipped := range t.skipped {
+		if name == skipped || strings.HasPrefix(name, skipped+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checksum 返回本地文件的 SHA256 哈希，结果会被缓存供其他主机复用
+// 计算哈希时不持有锁，多台主机可以并行计算不同文件的哈希
+//
+// 参数：
+//   - name: 相对路径
+//
+// 返回：
+//   - string: 十六进制哈希值
+//   - error: 计算失败时返回错误
+func (t *localTree) checksum(name string) (string, error) {
+	t.mu.Lock()
+	sum, ok := t.hashes[name]
+	t.mu.Unlock()
+	if ok {
+		return sum, nil
+	}
+
+	sum, err := hash.Checksum(t.files[name].path, "sha256")
+	if err != nil {
+		return "", fmt.Errorf("计算本地文件哈希失败: %w", err)
+	}
+
+	t.mu.Lock()
+	t.hashes[name] = sum
+	t.mu.Unlock()
+	return sum, nil
+}
+
+// listRemoteFiles 列出远程目录中的所有普通文件，目录不存在时返回空列表（私有函数）
+//
+// 参数：
+//   - client: 已建立的SSH客户端
+//   - remoteDir: 远程目录
+//
+// 返回：
+//   - map[string]remoteFile: 相对路径 -> 文件信息
+//   - error: 远程命令失败或输出格式错误时返回错误
+func listRemoteFiles(client *ssh.Client, remoteDir string) (map[string]remoteFile, error) {
+	dir := shellQuote(remoteDir)
+	cmd := fmt.Sprintf(`[ ! -d %s ] || find %s -type f -printf '%%s %%T@ %%P\0'`, dir, dir)
+	output, err := runRemoteCommand(client, cmd, nil)
+	if err != nil {
+		return nil, fmt.Errorf("列出远程文件失败: %w", err)
+	}
+
+	files := make(map[string]remoteFile)
+	for _, record := range splitNul(output) {
+		// 格式：<大小> <修改时间.小数> <相对路径>
+		fields := strings.SplitN(record, " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("无效的远程文件记录: %q", record)
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的远程文件大小: %w", err)
+		}
+		secs, _, _ := strings.Cut(fields[1], ".")
+		mtime, err := strconv.ParseInt(secs, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的远程修改时间: %w", err)
+		}
+		files[fields[2]] = remoteFile{size: size, mtime: mtime}
+	}
+	return files, nil
+}
+
+// remoteChecksums 使用 sha256sum 计算远程文件的哈希（私有函数）
+//
+// 参数：
+//   - client: 已建立的SSH客户端
+//   - remoteDir: 远程目录
+//   - names: 相对路径列表
+//
+// 返回：
+//   - map[string]string: 相对路径 -> 十六进制哈希值
+//   - error: 远程命令失败或输出格式错误时返回错误
+func remoteChecksums(client *ssh.Client, remoteDir string, names []string) (map[string]string, error) {
+	cmd := "cd -- " + shellQuote(remoteDir) + " && xargs -0 sha256sum -z --"
+	output, err := runRemoteCommand(client, cmd, nulJoin(names))
+	if err != nil {
+		return nil, fmt.Errorf("计算远程文件哈希失败: %w", err)
+	}
+
+	sums := make(map[string]string, len(names))
+	for _, record := range splitNul(output) {
+		// 格式：<哈希>  <文件名>（二进制模式时文件名前为 *）
+		sum, name, ok := strings.Cut(record, " ")
+		if !ok || len(name) < 2 {
+			return nil, fmt.Errorf("无效的 sha256sum 输出: %q", record)
+		}
+		sums[name[1:]] = sum
+	}
+	return sums, nil
+}
+
+// uploadSyncFiles 在同一连接上上传多个文件，自动创建远程子目录（私有函数）
+//
+// 参数：
+//   - client: 已建立的SSH客户端
+//   - tree: 本地文件列表
+//   - remoteDir: 远程目录
+//   - names: 要上传的相对路径列表
+//
+// 返回：
+//   - error: 创建目录或上传失败时返回错误
+func uploadSyncFiles(client *ssh.Client, tree *localTree, remoteDir string, names []string) error {
+	var dirs []string
+	for _, name := range names {
+		if dir := path.Dir(name); dir != "." && !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	cmd := "mkdir -p -- " + shellQuote(remoteDir) + " && cd -- " + shellQuote(remoteDir) + " && xargs -0 -r mkdir -p --"
+	if _, err := runRemoteCommand(client, cmd, nulJoin(dirs)); err != nil {
+		return fmt.Errorf("创建远程目录失败: %w", err)
+	}
+
+	for _, name := range names {
+		if err := uploadSyncFile(client, tree.files[name], path.Join(remoteDir, name)); err != nil {
+			return fmt.Errorf("上传 %s 失败: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// touchRemoteFiles 将内容相同的远程文件的修改时间更新为本地文件的修改时间（私有函数）
+// 下次同步时这些文件可直接通过大小和修改时间判断为未变化，无需再次计算哈希
+//
+// 参数：
+//   - client: 已建立的SSH客户端
+//   - tree: 本地文件列表
+//   - remoteDir: 远程目录
+//   - names: 相对路径列表
+//
+// 返回：
+//   - error: 远程命令失败时返回错误
+func touchRemoteFiles(client *ssh.Client, tree *localTree, remoteDir string, names []string) error {
+	var script strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&script, "touch -c -m -d @%d -- %s\n", tree.files[name].info.ModTime().Unix(), shellQuote(name))
+	}
+	if _, err := runRemoteCommand(client, "cd -- "+shellQuote(remoteDir)+" && sh -e", []byte(script.String())); err != nil {
+		return fmt.Errorf("更新远程文件修改时间失败: %w", err)
+	}
+	return nil
+}
+
+// uploadSyncFile 上传单个本地文件（私有函数）
+func uploadSyncFile(client *ssh.Client, local localFile, remotePath string) error {
+	file, err := os.Open(local.path)
+	if err != nil {
+		return fmt.Errorf("打开本地文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	// 重新获取文件信息，避免扫描后文件被修改导致大小不一致
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("获取本地文件信息失败: %w", err)
+	}
+	return scpUpload(client, file, info, remotePath)
+}
+
+// runRemoteCommand 在已建立的连接上执行命令并返回标准输出（私有函数）
+//
+// 参数：
+//   - client: 已建立的SSH客户端
+//   - cmd: 要执行的命令
+//   - stdin: 命令的标准输入，可为 nil
+//
+// 返回：
+//   - []byte: 标准输出内容
+//   - error: 执行失败时返回错误，包含远程标准错误输出
+func runRemoteCommand(client *ssh.Client, cmd string, stdin []byte) ([]byte, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("创建SSH会话失败: %w", err)
+	}
+	defer closeSession(session)
+
+	var stderr bytes.Buffer
+	session.Stderr = &stderr
+	if stdin != nil {
+		session.Stdin = bytes.NewReader(stdin)
+	}
+
+	output, err := session.Output(cmd)
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// nulJoin 使用 NUL 字符连接路径列表，供远程 xargs -0 读取（私有函数）
+func nulJoin(names []string) []byte {
+	var buf bytes.Buffer
+	for _, name := range names {
+		buf.WriteString(name)
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
+
+// splitNul 按 NUL 字符拆分远程命令输出，忽略空记录（私有函数）
+func splitNul(data []byte) []string {
+	var records []string
+	for _, record := range strings.Split(string(data), "\x00") {
+		if record != "" {
+			records = append(records, record)
+		}
+	}
+	return records
+}
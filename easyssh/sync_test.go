@@ -0,0 +1,176 @@
+package easyssh
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeTestFile 写入测试文件并设置修改时间，自动创建父目录
+func writeTestFile(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("Failed to set mtime: %v", err)
+	}
+}
+
+// checkSyncResult 校验同步结果的变更列表
+func checkSyncResult(t *testing.T, result SyncResult, added, changed, deleted []string, unchanged int) {
+	t.Helper()
+
+	if result.Err != nil || !result.Success {
+		t.Fatalf("Sync failed: %v", result.Err)
+	}
+	if !slices.Equal(result.Added, added) {
+		t.Errorf("Added = %v, want %v", result.Added, added)
+	}
+	if !slices.Equal(result.Changed, changed) {
+		t.Errorf("Changed = %v, want %v", result.Changed, changed)
+	}
+	if !slices.Equal(result.Deleted, deleted) {
+		t.Errorf("Deleted = %v, want %v", result.Deleted, deleted)
+	}
+	if result.Unchanged != unchanged {
+		t.Errorf("Unchanged = %d, want %d", result.Unchanged, unchanged)
+	}
+}
+
+func TestSyncDir(t *testing.T) {
+	host := startTestServer(t, "secret")
+	localDir := t.TempDir()
+	remoteDir := filepath.Join(t.TempDir(), "deploy")
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	writeTestFile(t, filepath.Join(localDir, "a.txt"), "alpha", base)
+	writeTestFile(t, filepath.Join(localDir, "sub", "b.txt"), "bravo", base)
+
+	t.Run("Initial upload", func(t *testing.T) {
+		result := SyncDir(host, localDir, remoteDir, SyncOptions{}, 0)
+		checkSyncResult(t, result, []string{"a.txt", "sub/b.txt"}, nil, nil, 0)
+
+		data, err := os.ReadFile(filepath.Join(remoteDir, "sub", "b.txt"))
+		if err != nil || string(data) != "bravo" {
+			t.Errorf("Remote sub/b.txt = %q, %v", data, err)
+		}
+	})
+
+	t.Run("No changes", func(t *testing.T) {
+		result := SyncDir(host, localDir, remoteDir, SyncOptions{}, 0)
+		checkSyncResult(t, result, nil, nil, nil, 2)
+	})
+
+	t.Run("Hash comparison", func(t *testing.T) {
+		// a.txt 大小相同但内容不同，b.txt 只有修改时间变化
+		writeTestFile(t, filepath.Join(localDir, "a.txt"), "ALPHA", base.Add(time.Minute))
+		writeTestFile(t, filepath.Join(localDir, "sub", "b.txt"), "bravo", base.Add(time.Minute))
+
+		result := SyncDir(host, localDir, remoteDir, SyncOptions{}, 0)
+		checkSyncResult(t, result, nil, []string{"a.txt"}, nil, 1)
+
+		data, _ := os.ReadFile(filepath.Join(remoteDir, "a.txt"))
+		if string(data) != "ALPHA" {
+			t.Errorf("Remote a.txt = %q, want %q", data, "ALPHA")
+		}
+
+		// 内容相同的文件只更新远程修改时间
+		info, err := os.Stat(filepath.Join(remoteDir, "sub", "b.txt"))
+		if err != nil || !info.ModTime().Equal(base.Add(time.Minute)) {
+			t.Errorf("Remote sub/b.txt mtime not updated: %v, %v", info, err)
+		}
+	})
+
+	t.Run("Delete dry run", func(t *testing.T) {
+		extra := filepath.Join(remoteDir, "old", "extra.txt")
+		writeTestFile(t, extra, "stale", base)
+		writeTestFile(t, filepath.Join(localDir, "c.txt"), "charlie", base)
+
+		result := SyncDir(host, localDir, remoteDir, SyncOptions{Delete: true, DryRun: true}, 0)
+		checkSyncResult(t, result, []string{"c.txt"}, nil, []string{"old/extra.txt"}, 2)
+		if _, err := os.Stat(extra); err != nil {
+			t.Errorf("Dry run removed remote file: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(remoteDir, "c.txt")); !os.IsNotExist(err) {
+			t.Errorf("Dry run uploaded c.txt: %v", err)
+		}
+
+		result = SyncDir(host, localDir, remoteDir, SyncOptions{Delete: true}, 0)
+		checkSyncResult(t, result, []string{"c.txt"}, nil, []string{"old/extra.txt"}, 2)
+		if _, err := os.Stat(extra); !os.IsNotExist(err) {
+			t.Errorf("Extra file not deleted: %v", err)
+		}
+	})
+
+	t.Run("Checksum option", func(t *testing.T) {
+		result := SyncDir(host, localDir, remoteDir, SyncOptions{Checksum: true}, 0)
+		checkSyncResult(t, result, nil, nil, nil, 3)
+	})
+}
+
+func TestSyncDirSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on Windows")
+	}
+	host := startTestServer(t, "secret")
+	localDir := t.TempDir()
+	remoteDir := filepath.Join(t.TempDir(), "deploy")
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	writeTestFile(t, filepath.Join(localDir, "conf", "app.conf"), "config", base)
+	for link, target := range map[string]string{
+		"app.conf": filepath.Join("conf", "app.conf"), // 指向普通文件，按目标文件上传
+		"etc":      "conf",                            // 指向目录，不同步
+		"broken":   "missing",                         // 目标不存在，不同步
+	} {
+		if err := os.Symlink(target, filepath.Join(localDir, link)); err != nil {
+			t.Fatalf("Failed to create symlink: %v", err)
+		}
+	}
+	writeTestFile(t, filepath.Join(remoteDir, "etc", "app.conf"), "remote", base)
+	writeTestFile(t, filepath.Join(remoteDir, "broken"), "remote", base)
+	writeTestFile(t, filepath.Join(remoteDir, "stale.txt"), "stale", base)
+
+	// 被忽略的本地条目在远程的同名路径不会被删除
+	result := SyncDir(host, localDir, remoteDir, SyncOptions{Delete: true}, 0)
+	checkSyncResult(t, result, []string{"app.conf", "conf/app.conf"}, nil, []string{"stale.txt"}, 0)
+
+	if data, err := os.ReadFile(filepath.Join(remoteDir, "app.conf")); err != nil || string(data) != "config" {
+		t.Errorf("Remote app.conf = %q, %v", data, err)
+	}
+	for _, name := range []string{"etc/app.conf", "broken"} {
+		if _, err := os.Stat(filepath.Join(remoteDir, name)); err != nil {
+			t.Errorf("Remote %s deleted: %v", name, err)
+		}
+	}
+}
+
+func TestSyncRaw(t *testing.T) {
+	e := newTestEasySSH(t, startTestServer(t, "secret"), startTestServer(t, "secret"))
+	localDir := t.TempDir()
+	writeTestFile(t, filepath.Join(localDir, "app.conf"), "port=80", time.Now())
+
+	if _, err := e.SyncRaw(filepath.Join(localDir, "missing"), "/tmp", SyncOptions{}); err == nil {
+		t.Error("Expected error for missing local directory")
+	}
+
+	remoteDir := t.TempDir()
+	results, err := e.SyncRaw(localDir, remoteDir, SyncOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("SyncRaw failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	for _, result := range results {
+		checkSyncResult(t, result, []string{"app.conf"}, nil, nil, 0)
+	}
+}
@@ -0,0 +1,341 @@
+package easyssh
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// UploadFile 通过 SCP 协议将本地文件上传到远程主机
+//
+// 参数：
+//   - host: 主机信息结构体，包含连接信息（主机地址、端口、用户名、密码）
+//   - localPath: 本地文件路径（必须是普通文件）
+//   - remotePath: 远程目标路径，若为已存在的目录则自动追加本地文件名
+//   - timeout: 连接超时时间（零值表示不设置超时）
+//
+// 返回：
+//   - error: 上传失败时返回错误
+//
+// 注意：
+//   - 远程主机需要安装 scp 命令（OpenSSH 默认提供）
+//   - 会同时保留文件权限和修改时间
+func UploadFile(host HostConfig, localPath, remotePath string, timeout time.Duration) error {
+	if strings.TrimSpace(localPath) == "" || strings.TrimSpace(remotePath) == "" {
+		return errors.New("本地路径和远程路径不能为空")
+	}
+
+	// 打开并检查本地文件
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("打开本地文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("获取本地文件信息失败: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("本地路径 '%s' 不是普通文件", localPath)
+	}
+
+	client, err := dialHost(host, timeout)
+	if err != nil {
+		return err
+	}
+	defer closeClient(client)
+
+	return scpUpload(client, file, info, remotePath)
+}
+
+// DownloadFile 通过 SCP 协议从远程主机下载文件
+//
+// 参数：
+//   - host: 主机信息结构体，包含连接信息（主机地址、端口、用户名、密码）
+//   - remotePath: 远程文件路径（必须是普通文件）
+//   - localPath: 本地目标路径，若为已存在的目录则自动追加远程文件名
+//   - timeout: 连接超时时间（零值表示不设置超时）
+//
+// 返回：
+//   - error: 下载失败时返回错误
+func DownloadFile(host HostConfig, remotePath, localPath string, timeout time.Duration) error {
+	if strings.TrimSpace(localPath) == "" || strings.TrimSpace(remotePath) == "" {
+		return errors.New("本地路径和远程路径不能为空")
+	}
+
+	// 目标是已存在的目录时，自动追加远程文件名
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		localPath = filepath.Join(localPath, path.Base(remotePath))
+	}
+
+	client, err := dialHost(host, timeout)
+	if err != nil {
+		return err
+	}
+	defer closeClient(client)
+
+	return scpDownload(client, remotePath, localPath)
+}
+
+// scpUpload 在已建立的连接上以 scp sink 模式发送单个文件
+//
+// 参数：
+//   - client: 已建立的SSH客户端
+//   - file: 已打开的本地文件
+//   - info: 本地文件信息
+//   - remotePath: 远程目标路径
+//
+// 返回：
+//   - error: 传输失败时返回错误
+func scpUpload(client *ssh.Client, file io.Reader, info os.FileInfo, remotePath string) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("创建SSH会话失败: %w", err)
+	}
+	defer closeSession(session)
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("获取会话输入流失败: %w", err)
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("获取会话输出流失败: %w", err)
+	}
+	reader := bufio.NewReader(stdout)
+
+	if err := session.Start("scp -qpt -- " + shellQuote(remotePath)); err != nil {
+		return fmt.Errorf("启动远程 scp 失败: %w", err)
+	}
+
+	// 按 scp 协议依次发送时间戳、文件头、文件内容和结束标记
+	sendErr := func() error {
+		if err := scpReadAck(reader); err != nil {
+			return err
+		}
+		mtime := info.ModTime().Unix()
+		if _, err := fmt.Fprintf(stdin, "T%d 0 %d 0\n", mtime, mtime); err != nil {
+			return err
+		}
+		if err := scpReadAck(reader); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(stdin, "C%04o %d %s\n", info.Mode().Perm(), info.Size(), info.Name()); err != nil {
+			return err
+		}
+		if err := scpReadAck(reader); err != nil {
+			return err
+		}
+		if _, err := io.Copy(stdin, file); err != nil {
+			return err
+		}
+		if _, err := stdin.Write([]byte{0}); err != nil {
+			return err
+		}
+		return scpReadAck(reader)
+	}()
+	_ = stdin.Close()
+
+	if sendErr != nil {
+		return fmt.Errorf("上传文件失败: %w", sendErr)
+	}
+	if err := session.Wait(); err != nil {
+		return fmt.Errorf("远程 scp 执行失败: %w", err)
+	}
+	return nil
+}
+
+// scpDownload 在已建立的连接上以 scp source 模式接收单个文件
+// 文件先写入临时文件，接收完整后再重命名为目标文件
+//
+// 参数：
+//   - client: 已建立的SSH客户端
+//   - remotePath: 远程文件路径
+//   - localPath: 本地目标文件路径
+//
+// 返回：
+//   - error: 传输失败时返回错误
+func scpDownload(client *ssh.Client, remotePath, localPath string) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("创建SSH会话失败: %w", err)
+	}
+	defer closeSession(session)
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("获取会话输入流失败: %w", err)
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("获取会话输出流失败: %w", err)
+	}
+	reader := bufio.NewReader(stdout)
+
+	if err := session.Start("scp -qf -- " + shellQuote(remotePath)); err != nil {
+		return fmt.Errorf("启动远程 scp 失败: %w", err)
+	}
+
+	recvErr := func() error {
+		// 通知远端开始发送
+		if _, err := stdin.Write([]byte{0}); err != nil {
+			return err
+		}
+		mode, size, err := scpReadHeader(reader)
+		if err != nil {
+			return err
+		}
+		if _, err := stdin.Write([]byte{0}); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(localPath, io.LimitReader(reader, size), size, mode); err != nil {
+			return err
+		}
+		if err := scpReadAck(reader); err != nil {
+			return err
+		}
+		_, err = stdin.Write([]byte{0})
+		return err
+	}()
+	_ = stdin.Close()
+
+	if recvErr != nil {
+		return fmt.Errorf("下载文件失败: %w", recvErr)
+	}
+	if err := session.Wait(); err != nil {
+		return fmt.Errorf("远程 scp 执行失败: %w", err)
+	}
+	return nil
+}
+
+// scpReadAck 读取 scp 协议的应答字节
+//
+// 参数：
+//   - reader: 远端输出流
+//
+// 返回：
+//   - error: 应答为警告或错误时返回远端给出的错误信息
+func scpReadAck(reader *bufio.Reader) error {
+	code, err := reader.ReadByte()
+	if err != nil {
+		return fmt.Errorf("读取 scp 应答失败: %w", err)
+	}
+	if code == 0 {
+		return nil
+	}
+
+	// 1 表示警告，2 表示致命错误，后跟一行错误信息
+	msg, _ := reader.ReadString('\n')
+	return fmt.Errorf("远端 scp 错误: %s", strings.TrimSpace(msg))
+}
+
+// scpReadHeader 读取 scp 文件头（跳过时间戳行）
+//
+// 参数：
+//   - reader: 远端输出流
+//
+// 返回：
+//   - os.FileMode: 文件权限
+//   - int64: 文件大小
+//   - error: 文件头格式错误或远端报错时返回错误
+func scpReadHeader(reader *bufio.Reader) (os.FileMode, int64, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, 0, fmt.Errorf("读取 scp 文件头失败: %w", err)
+	}
+	if line == "" {
+		return 0, 0, errors.New("scp 文件头为空")
+	}
+
+	switch line[0] {
+	case 'C':
+		// 格式：C<权限> <大小> <文件名>
+		fields := strings.SplitN(strings.TrimSpace(line[1:]), " ", 3)
+		if len(fields) != 3 {
+			return 0, 0, fmt.Errorf("无效的 scp 文件头: %q", line)
+		}
+		mode, err := strconv.ParseUint(fields[0], 8, 32)
+		if err != nil {
+			return 0, 0, fmt.Errorf("无效的文件权限: %w", err)
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("无效的文件大小: %w", err)
+		}
+		return os.FileMode(mode).Perm(), size, nil
+	case 'D':
+		return 0, 0, errors.New("远程路径是目录，仅支持下载单个文件")
+	case 1, 2:
+		return 0, 0, fmt.Errorf("远端 scp 错误: %s", strings.TrimSpace(line[1:]))
+	default:
+		return 0, 0, fmt.Errorf("无效的 scp 文件头: %q", line)
+	}
+}
+
+// writeFileAtomic 将数据写入临时文件后原子重命名为目标文件
+//
+// 参数：
+//   - dst: 目标文件路径
+//   - r: 数据源
+//   - size: 期望写入的字节数
+//   - mode: 文件权限
+//
+// 返回：
+//   - error: 写入失败时返回错误，失败时不会留下不完整的目标文件
+func writeFileAtomic(dst string, r io.Reader, size int64, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("创建本地目录失败: %w", err)
+	}
+
+	tmp := dst + ".tmp." + fmt.Sprintf("%d.%d", os.Getpid(), time.Now().UnixNano())
+	out, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+
+	success := false
+	defer func() {
+		if !success {
+			_ = out.Close()
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	n, err := io.Copy(out, r)
+	if err != nil {
+		return fmt.Errorf("写入本地文件失败: %w", err)
+	}
+	if n != size {
+		return fmt.Errorf("文件不完整: 期望 %d 字节, 实际 %d 字节", size, n)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("关闭临时文件失败: %w", err)
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return fmt.Errorf("重命名临时文件失败: %w", err)
+	}
+
+	success = true
+	return nil
+}
+
+// shellQuote 使用单引号转义字符串，使其可以安全地拼接到远程 shell 命令中
+//
+// 参数：
+//   - s: 原始字符串
+//
+// 返回：
+//   - string: 转义后的字符串
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,130 @@
+package easyssh
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"/tmp/a.txt", `'/tmp/a.txt'`},
+		{"with space", `'with space'`},
+		{"it's", `'it'\''s'`},
+		{"$(rm -rf /)", `'$(rm -rf /)'`},
+		{"", `''`},
+	}
+
+	for _, tt := range tests {
+		if got := shellQuote(tt.input); got != tt.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestScpReadHeader(t *testing.T) {
+	t.Run("File header", func(t *testing.T) {
+		reader := bufio.NewReader(strings.NewReader("C0644 1234 file name.txt\n"))
+		mode, size, err := scpReadHeader(reader)
+		if err != nil {
+			t.Fatalf("scpReadHeader() error = %v", err)
+		}
+		if mode != os.FileMode(0644) || size != 1234 {
+			t.Errorf("scpReadHeader() = %v, %d, want -rw-r--r--, 1234", mode, size)
+		}
+	})
+
+	t.Run("Remote error", func(t *testing.T) {
+		reader := bufio.NewReader(strings.NewReader("\x01scp: /nope: No such file or directory\n"))
+		_, _, err := scpReadHeader(reader)
+		if err == nil || !strings.Contains(err.Error(), "No such file") {
+			t.Errorf("scpReadHeader() error = %v, want remote error", err)
+		}
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		reader := bufio.NewReader(strings.NewReader("D0755 0 dir\n"))
+		if _, _, err := scpReadHeader(reader); err == nil {
+			t.Error("scpReadHeader() should reject directories")
+		}
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		reader := bufio.NewReader(strings.NewReader("C0644 abc x\n"))
+		if _, _, err := scpReadHeader(reader); err == nil {
+			t.Error("scpReadHeader() should reject invalid size")
+		}
+	})
+}
+
+func TestScpReadAck(t *testing.T) {
+	if err := scpReadAck(bufio.NewReader(strings.NewReader("\x00"))); err != nil {
+		t.Errorf("scpReadAck() ok ack error = %v", err)
+	}
+	if err := scpReadAck(bufio.NewReader(strings.NewReader("\x02fatal\n"))); err == nil {
+		t.Error("scpReadAck() should return error for fatal ack")
+	}
+	if err := scpReadAck(bufio.NewReader(strings.NewReader(""))); err == nil {
+		t.Error("scpReadAck() should return error on EOF")
+	}
+}
+
+func TestUploadDownloadFile(t *testing.T) {
+	host := startTestServer(t, "secret")
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "src.txt")
+	content := strings.Repeat("easyssh scp transfer\n", 5000)
+	if err := os.WriteFile(src, []byte(content), 0640); err != nil {
+		t.Fatalf("Failed to create source file: %v", err)
+	}
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(src, mtime, mtime); err != nil {
+		t.Fatalf("Failed to set mtime: %v", err)
+	}
+
+	// 上传到已存在的目录时自动追加文件名
+	remoteDir := filepath.Join(dir, "remote")
+	if err := os.Mkdir(remoteDir, 0755); err != nil {
+		t.Fatalf("Failed to create remote dir: %v", err)
+	}
+	if err := UploadFile(host, src, remoteDir, 5*time.Second); err != nil {
+		t.Fatalf("UploadFile() error = %v", err)
+	}
+	uploaded := filepath.Join(remoteDir, "src.txt")
+	info, err := os.Stat(uploaded)
+	if err != nil {
+		t.Fatalf("Uploaded file missing: %v", err)
+	}
+	if info.Mode().Perm() != 0640 || !info.ModTime().Equal(mtime) {
+		t.Errorf("Uploaded file mode/mtime = %v/%v, want -rw-r-----/%v", info.Mode().Perm(), info.ModTime(), mtime)
+	}
+
+	// 下载到已存在的目录时自动追加文件名
+	localDir := filepath.Join(dir, "local")
+	if err := os.Mkdir(localDir, 0755); err != nil {
+		t.Fatalf("Failed to create local dir: %v", err)
+	}
+	if err := DownloadFile(host, uploaded, localDir, 5*time.Second); err != nil {
+		t.Fatalf("DownloadFile() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(localDir, "src.txt"))
+	if err != nil || string(got) != content {
+		t.Errorf("Downloaded content mismatch (err: %v)", err)
+	}
+
+	// 下载不存在的文件应返回错误且不留下临时文件
+	if err := DownloadFile(host, filepath.Join(dir, "missing"), filepath.Join(localDir, "missing"), 5*time.Second); err == nil {
+		t.Error("DownloadFile() should fail for missing remote file")
+	}
+	entries, _ := os.ReadDir(localDir)
+	if len(entries) != 1 {
+		t.Errorf("Local dir should only contain the downloaded file, got %d entries", len(entries))
+	}
+}
@@ -12,18 +12,67 @@ type HostConfig struct {
 
 // RemoteExecResult 远程命令执行结果结构体
 type RemoteExecResult struct {
-	Success bool   // 执行是否成功
-	Output  string // 命令输出内容（标准输出+标准错误）
-	Err     error  // 执行过程中的错误信息
+	Success  bool   // 执行是否成功
+	Output   string // 命令输出内容（标准输出+标准错误）
+	Err      error  // 执行过程中的错误信息
+	ExitCode int    // 远程命令退出码（连接失败等无法获取时为 -1）
+}
+
+// HostResult 单台主机的批量操作结果
+type HostResult struct {
+	Host     string        // 主机地址
+	Port     int           // 端口
+	Success  bool          // 操作是否成功
+	Output   string        // 命令输出内容（标准输出+标准错误）
+	Err      error         // 错误信息
+	ExitCode int           // 远程命令退出码（非命令操作成功时为 0，无法获取时为 -1）
+	Duration time.Duration // 操作耗时
+}
+
+// HostFacts 主机基础信息
+type HostFacts struct {
+	Host     string        // 主机地址
+	Port     int           // 端口
+	Hostname string        // 主机名
+	OS       string        // 操作系统发行版名称
+	Kernel   string        // 内核版本
+	Arch     string        // CPU 架构
+	CPUs     int           // CPU 核数
+	MemTotal int64         // 内存总量（字节）
+	Uptime   time.Duration // 运行时长
+	Err      error         // 采集过程中的错误信息
+}
+
+// PingLevel 连通性检查级别，级别越高检查越深入，每个级别包含之前所有级别的检查
+type PingLevel int
+
+const (
+	PingTCP       PingLevel = iota // 仅检查 TCP 端口是否可连接（默认）
+	PingBanner                     // 读取 SSH 版本标识，检测 sshd 是否正常响应
+	PingHandshake                  // 完成 SSH 密钥交换，获取主机密钥指纹
+	PingAuth                       // 使用主机清单中的凭据完成认证
+)
+
+// PingStage 单个检查阶段的结果
+type PingStage struct {
+	Name     string        // 阶段名称（tcp、banner、handshake、auth）
+	Duration time.Duration // 阶段耗时
+	Err      error         // 阶段错误信息，成功时为 nil
 }
 
 // PingResult Ping 结果结构体
 type PingResult struct {
-	Host      string        // 主机地址
-	Port      int           // 端口
-	Connected bool          // 是否连接成功
-	Latency   time.Duration // 连接延迟
-	Err       error         // 错误信息
+	Host               string        // 主机地址
+	Port               int           // 端口
+	Connected          bool          // 是否通过所有检查阶段
+	Latency            time.Duration // 所有检查阶段的总耗时
+	Err                error         // 错误信息
+	Level              PingLevel     // 检查级别
+	FailedStage        string        // 失败的阶段名称，成功时为空
+	Stages             []PingStage   // 已执行的各阶段结果（按执行顺序）
+	ServerVersion      string        // 服务端 SSH 版本标识（banner 及以上级别）
+	HostKeyType        string        // 主机密钥类型（handshake 及以上级别）
+	HostKeyFingerprint string        // 主机密钥 SHA256 指纹（handshake 及以上级别）
 }
 
 // EasySSH SSH管理器
@@ -32,5 +81,28 @@ type EasySSH struct {
 	Timeout    time.Duration // 连接超时时间
 	ShowOutput bool          // 是否显示命令输出
 	ShowFormat bool          // 是否显示格式化执行输出
+	Parallel   int           // 并发操作的主机数量（小于等于1表示串行）
+	PingLevel  PingLevel     // PingHosts 的检查级别（默认仅检查 TCP 连通性）
+	Patterns   []string      // 主机筛选模式，支持通配符（为空表示全部主机）
 	hosts      []HostConfig  // 缓存的主机列表
 }
+
+// SyncOptions 目录同步选项
+type SyncOptions struct {
+	Delete   bool // 删除远程目录中本地不存在的文件（被忽略的本地符号链接和特殊文件对应的路径除外）
+	Checksum bool // 大小和修改时间相同时仍比较 SHA256 哈希
+	DryRun   bool // 只计算需要的变更，不实际传输或删除
+}
+
+// SyncResult 单台主机的目录同步结果，文件路径均为相对同步根目录的路径（使用 / 分隔）
+type SyncResult struct {
+	Host      string        // 主机地址
+	Port      int           // 端口
+	Success   bool          // 同步是否成功
+	Added     []string      // 新增的文件
+	Changed   []string      // 内容变化后重新上传的文件
+	Deleted   []string      // 删除的远程多余文件（仅 SyncOptions.Delete 为 true 时）
+	Unchanged int           // 未变化的文件数量
+	Err       error         // 错误信息
+	Duration  time.Duration // 同步耗时
+}
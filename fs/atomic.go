@@ -0,0 +1,267 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AtomicWriter 原子写入器，数据先写入同目录下的临时文件，Commit 时刷盘并重命名为目标文件
+// 提交前目标文件保持原样，其他进程不会读到写了一半的内容
+// 替换已存在的文件时保留其权限位和所有者（非 root 用户无权修改所有者时忽略）
+type AtomicWriter struct {
+	f    *os.File
+	path string      // 目标文件绝对路径（已解析符号链接）
+	tmp  string      // 临时文件路径
+	prev os.FileInfo // 被替换的文件信息，目标不存在时为 nil
+	done bool        // 已提交或已放弃
+}
+
+// NewAtomicWriter 创建原子写入器，必须调用 Commit/Close 提交或调用 Abort 放弃
+// 目标为符号链接时写入链接指向的文件，父目录不存在时自动创建
+//
+// 参数:
+//   - path: 目标文件路径
+//   - perm: 新建文件的权限（受 umask 影响），替换已存在的文件时使用原文件的权限
+//
+// 返回:
+//   - *AtomicWriter: 写入器
+//   - error: 目标是目录或创建临时文件失败时返回错误
+//
+// 示例:
+//
+//	w, err := fs.NewAtomicWriter("config.json", 0o644)
+//	if err != nil {
+//	    return err
+//	}
+//	defer w.Abort() // 提交后调用无影响
+//	if err := json.NewEncoder(w).Encode(cfg); err != nil {
+//	    return err
+//	}
+//	return w.Commit()
+func NewAtomicWriter(path string, perm os.FileMode) (*AtomicWriter, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for '%s': %w", path, err)
+	}
+
+	// 跟随符号链接，替换链接指向的文件而不是链接本身
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		absPath = resolved
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to resolve path '%s': %w", absPath, err)
+	}
+
+	w := &AtomicWriter{path: absPath}
+	info, err := os.Stat(absPath)
+	switch {
+	case err == nil:
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("target '%s' is not a regular file", absPath)
+		}
+		w.prev = info
+		perm = info.Mode().Perm()
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to get file info '%s': %w", absPath, err)
+	}
+
+	dir := filepath.Dir(absPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory '%s': %w", dir, err)
+	}
+
+	// 临时文件与目标同目录，保证 rename 原子性
+	w.tmp = absPath + ".tmp." + fmt.Sprintf("%d.%d", os.Getpid(), time.Now().UnixNano())
+	w.f, err = os.OpenFile(w.tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file '%s': %w", w.tmp, err)
+	}
+	return w, nil
+}
+
+// Write 写入数据到临时文件
+//
+// 参数:
+//   - p: 数据
+//
+// 返回:
+//   - int: 写入的字节数
+//   - error: 写入失败或写入器已提交/放弃时返回错误
+func (w *AtomicWriter) Write(p []byte) (int, error) {
+	if w.done {
+		return 0, os.ErrClosed
+	}
+	return w.f.Write(p)
+}
+
+// Name 返回目标文件的绝对路径
+func (w *AtomicWriter) Name() string {
+	return w.path
+}
+
+// Commit 提交写入：刷盘、恢复原文件的权限和所有者、重命名为目标文件并刷新父目录
+// 失败时删除临时文件，目标文件保持原样
+//
+// 返回:
+//   - error: 提交失败或写入器已提交/放弃时返回错误
+func (w *AtomicWriter) Commit() error {
+	if w.done {
+		return os.ErrClosed
+	}
+	w.done = true
+
+	err := w.commit()
+	if err != nil {
+		_ = w.f.Close()
+		_ = os.Remove(w.tmp)
+	}
+	return err
+}
+
+// commit 执行提交步骤
+func (w *AtomicWriter) commit() error {
+	if err := w.f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temporary file '%s': %w", w.tmp, err)
+	}
+	// 在重命名前关闭文件句柄(Windows要求)
+	if err := w.f.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file '%s': %w", w.tmp, err)
+	}
+
+	if w.prev != nil {
+		// 显式设置权限，不受 umask 影响
+		if err := os.Chmod(w.tmp, w.prev.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to set permissions on '%s': %w", w.tmp, err)
+		}
+		if err := lchown(w.tmp, w.prev); err != nil {
+			return fmt.Errorf("failed to set owner on '%s': %w", w.tmp, err)
+		}
+	}
+
+	if err := os.Rename(w.tmp, w.path); err != nil {
+		return fmt.Errorf("failed to rename temporary file '%s' to '%s': %w", w.tmp, w.path, err)
+	}
+	// 刷新父目录，确保重命名本身持久化
+	if err := syncDir(filepath.Dir(w.path)); err != nil {
+		return fmt.Errorf("failed to sync directory '%s': %w", filepath.Dir(w.path), err)
+	}
+	return nil
+}
+
+// Close 等价于 Commit，已提交或已放弃时返回 nil，便于作为 io.WriteCloser 使用
+//
+// 返回:
+//   - error: 提交失败时返回错误
+func (w *AtomicWriter) Close() error {
+	if w.done {
+		return nil
+	}
+	return w.Commit()
+}
+
+// Abort 放弃写入并删除临时文件，目标文件保持原样；已提交或已放弃时不做任何操作
+//
+// 返回:
+//   - error: 删除临时文件失败时返回错误
+func (w *AtomicWriter) Abort() error {
+	if w.done {
+		return nil
+	}
+	w.done = true
+
+	_ = w.f.Close()
+	if err := os.Remove(w.tmp); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove temporary file '%s': %w", w.tmp, err)
+	}
+	return nil
+}
+
+// WriteFileAtomic 原子地写入文件，与 os.WriteFile 用法相同
+// 数据写入临时文件并刷盘后重命名为目标文件，再刷新父目录；任何时刻目标文件要么是旧内容，要么是完整的新内容
+// 替换已存在的文件时保留其权限位和所有者
+//
+// 参数:
+//   - path: 目标文件路径
+//   - data: 文件内容
+//   - perm: 新建文件的权限（受 umask 影响）
+//
+// 返回:
+//   - error: 写入失败时返回错误，目标文件保持原样
+//
+// 示例:
+//
+//	err := fs.WriteFileAtomic("state.json", data, 0o600)
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	w, err := NewAtomicWriter(path, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		_ = w.Abort()
+		return fmt.Errorf("failed to write temporary file '%s': %w", w.tmp, err)
+	}
+	return w.Commit()
+}
+
+// updateLocks 文件路径到互斥锁的映射，串行化同一进程内对同一文件的读-改-写
+var updateLocks sync.Map
+
+// UpdateFileAtomic 在锁的保护下读取文件、调用 fn 修改内容并原子地写回
+// 同一进程内对同一文件（按绝对路径区分）的更新依次执行；Unix（AIX 除外）上还会对 path+".lock"
+// 加排他文件锁，多个进程同时更新同一文件也不会丢失更新（锁文件保留，不会被删除）
+// 其他平台不支持文件锁，只保证同一进程内的更新不会丢失
+//
+// 参数:
+//   - path: 目标文件路径
+//   - perm: 文件不存在时新建文件的权限
+//   - fn: 修改函数，参数为当前内容（文件不存在时为 nil），返回新内容；返回错误时不写入
+//
+// 返回:
+//   - error: 读取、修改或写入失败时返回错误
+//
+// 示例:
+//
+//	err := fs.UpdateFileAtomic("counter", 0o644, func(data []byte) ([]byte, error) {
+//	    n, _ := strconv.Atoi(string(data))
+//	    return []byte(strconv.Itoa(n + 1)), nil
+//	})
+func UpdateFileAtomic(path string, perm os.FileMode, fn func([]byte) ([]byte, error)) error {
+	if fn == nil {
+		return fmt.Errorf("update function cannot be nil")
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path for '%s': %w", path, err)
+	}
+
+	mu, _ := updateLocks.LoadOrStore(absPath, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	defer mu.(*sync.Mutex).Unlock()
+
+	// 跨进程互斥，不支持文件锁的平台只保证进程内互斥
+	dir := filepath.Dir(absPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", dir, err)
+	}
+	lock := NewFileLock(absPath + ".lock")
+	if err := lock.Lock(LockExclusive); err != nil && !errors.Is(err, ErrLockUnsupported) {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	data, err := os.ReadFile(absPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read file '%s': %w", absPath, err)
+	}
+	data, err = fn(data)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(absPath, data, perm)
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package fs
+
+// syncDir 其他平台不支持刷新目录，直接返回
+func syncDir(dir string) error {
+	return nil
+}
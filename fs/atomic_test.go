@@ -0,0 +1,167 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// assertNoTempFiles 确认目录中没有残留的临时文件
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.tmp.*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) > 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "a.txt")
+
+	if err := WriteFileAtomic(path, []byte("first"), 0o600); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "first" {
+		t.Errorf("content = %q, want %q", data, "first")
+	}
+
+	if runtime.GOOS != "windows" {
+		// 替换时保留原文件权限，忽略 perm 参数
+		if err := os.Chmod(path, 0o640); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFileAtomic(path, []byte("second"), 0o600); err != nil {
+			t.Fatalf("WriteFileAtomic failed: %v", err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0o640 {
+			t.Errorf("mode = %o, want 640", info.Mode().Perm())
+		}
+
+		// 符号链接：写入链接指向的文件，链接本身保留
+		link := filepath.Join(dir, "link")
+		if err := os.Symlink(path, link); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFileAtomic(link, []byte("third"), 0o600); err != nil {
+			t.Fatalf("WriteFileAtomic via symlink failed: %v", err)
+		}
+		if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("symlink was replaced: %v", err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != "third" {
+			t.Errorf("content = %q, want %q", data, "third")
+		}
+	}
+
+	if err := WriteFileAtomic(dir, []byte("x"), 0o600); err == nil {
+		t.Error("WriteFileAtomic to directory: expected error")
+	}
+	assertNoTempFiles(t, filepath.Dir(path))
+}
+
+func TestAtomicWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	writeSyncFile(t, path, "old")
+
+	t.Run("Abort", func(t *testing.T) {
+		w, err := NewAtomicWriter(path, 0o644)
+		if err != nil {
+			t.Fatalf("NewAtomicWriter failed: %v", err)
+		}
+		if _, err := w.Write([]byte("new")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Abort(); err != nil {
+			t.Fatalf("Abort failed: %v", err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != "old" {
+			t.Errorf("content = %q, want %q", data, "old")
+		}
+		if _, err := w.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+			t.Errorf("Write after Abort: err = %v, want os.ErrClosed", err)
+		}
+		assertNoTempFiles(t, dir)
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		w, err := NewAtomicWriter(path, 0o644)
+		if err != nil {
+			t.Fatalf("NewAtomicWriter failed: %v", err)
+		}
+		defer func() { _ = w.Abort() }()
+		if _, err := w.Write([]byte("new ")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if _, err := w.Write([]byte("content")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		// 提交前目标文件保持原样
+		if data, _ := os.ReadFile(path); string(data) != "old" {
+			t.Errorf("content before commit = %q, want %q", data, "old")
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != "new content" {
+			t.Errorf("content = %q, want %q", data, "new content")
+		}
+		if err := w.Commit(); !errors.Is(err, os.ErrClosed) {
+			t.Errorf("second Commit: err = %v, want os.ErrClosed", err)
+		}
+		assertNoTempFiles(t, dir)
+	})
+}
+
+func TestUpdateFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- UpdateFileAtomic(path, 0o644, func(data []byte) ([]byte, error) {
+				count, _ := strconv.Atoi(string(data))
+				return []byte(strconv.Itoa(count + 1)), nil
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("UpdateFileAtomic failed: %v", err)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != strconv.Itoa(n) {
+		t.Errorf("counter = %q, want %d", data, n)
+	}
+
+	// 修改函数返回错误时不写入
+	errStop := errors.New("stop")
+	err := UpdateFileAtomic(path, 0o644, func(data []byte) ([]byte, error) {
+		return nil, errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("err = %v, want %v", err, errStop)
+	}
+	if data, _ := os.ReadFile(path); string(data) != strconv.Itoa(n) {
+		t.Errorf("counter = %q after failed update, want %d", data, n)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package fs
+
+import "os"
+
+// syncDir 刷新目录，确保目录中的创建和重命名操作持久化
+//
+// 参数:
+//   - dir: 目录路径
+//
+// 返回:
+//   - error: 打开或刷新目录失败时返回错误
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = d.Close() }()
+	return d.Sync()
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package fs
+
+// syncDir Windows 不支持通过普通句柄刷新目录，直接返回
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,45 @@
+//go:build !unix && !windows
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// IsHidden 判断文件或目录是否为隐藏（按 Unix 风格的点文件判断）
+//
+// 参数:
+//   - path: 文件或目录路径
+//
+// 返回:
+//   - bool: 是否为隐藏
+func IsHidden(path string) bool {
+	name := filepath.Base(path)
+	return len(name) > 0 && name[0] == '.' && name != "." && name != ".."
+}
+
+// IsReadOnly 判断文件或目录是否为只读（所有写权限位均未设置）
+//
+// 参数:
+//   - path: 文件或目录路径
+//
+// 返回:
+//   - bool: 是否为只读，路径不存在时返回 false
+func IsReadOnly(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.Mode().Perm()&0222 == 0
+}
+
+// IsDriveRoot 判断路径是否为盘符根目录，其他平台没有盘符概念，始终返回 false
+func IsDriveRoot(path string) bool {
+	return false
+}
+
+// GetFileOwner 获取文件的所有者和所属组，其他平台不支持，始终返回 "?", "?"
+func GetFileOwner(filePath string) (string, string) {
+	return "?", "?"
+}
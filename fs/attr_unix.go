@@ -1,4 +1,4 @@
-//go:build linux || darwin
+//go:build unix
 
 // Package utils 提供了 Unix/Linux/macOS 系统特定的文件属性检查功能。
 // 该文件实现了 Unix 平台下的隐藏文件检测、只读属性检查和文件所有者获取等系统相关功能。
@@ -0,0 +1,381 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gitee.com/MM-Q/go-kit/hash"
+)
+
+// CompareOptions 目录比较选项
+type CompareOptions struct {
+	// Include 只比较匹配的文件（通配符规则与 CopyOptions.Include 相同），目录始终遍历
+	Include []string
+
+	// Exclude 排除匹配的文件和目录，目录被排除时整个子树都不比较
+	Exclude []string
+
+	// Filter 自定义过滤函数，以 A 侧路径调用（只存在于 B 的条目以 B 侧路径调用）
+	Filter FilterFunc
+
+	// MaxDepth 最大比较深度，0 表示不限制
+	MaxDepth int
+
+	// FollowSymlinks 跟随符号链接比较其指向的内容，默认只比较符号链接本身的目标路径
+	// 指向不存在路径的符号链接始终按链接本身比较
+	FollowSymlinks bool
+
+	// Checksum 对大小相同的文件总是比较校验和，默认只在修改时间不同时通过校验和确认内容是否变化
+	Checksum bool
+
+	// Algorithm 校验和算法（md5、sha1、sha256、sha512），为空时使用 sha256
+	Algorithm string
+}
+
+// DiffKind 条目差异类型，可以是多个差异的组合
+type DiffKind uint
+
+const (
+	DiffOnlyInA DiffKind = 1 << iota // 只存在于 A
+	DiffOnlyInB                      // 只存在于 B
+	DiffType                         // 类型不同（如一侧为文件，另一侧为目录）
+	DiffSize                         // 文件大小不同
+	DiffModTime                      // 修改时间不同（目录不比较）
+	DiffMode                         // 权限位不同
+	DiffContent                      // 内容不同（文件数据或符号链接目标）
+)
+
+// diffKindNames 差异类型与名称的对应关系，按位从低到高排列
+var diffKindNames = []string{"only-in-a", "only-in-b", "type", "size", "mtime", "mode", "content"}
+
+// String 返回差异类型的名称，多个差异以 | 连接（如 size|mtime|content）
+func (k DiffKind) String() string {
+	if k == 0 {
+		return "none"
+	}
+
+	var names []string
+	for i, name := range diffKindNames {
+		if k&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if rest := k &^ (1<<len(diffKindNames) - 1); rest != 0 {
+		names = append(names, fmt.Sprintf("DiffKind(%#x)", uint(rest)))
+	}
+	return strings.Join(names, "|")
+}
+
+// Has 是否包含指定的差异
+func (k DiffKind) Has(flag DiffKind) bool {
+	return k&flag != 0
+}
+
+// DiffEntry 两个目录中的一处差异
+type DiffEntry struct {
+	Path string      // 相对比较根目录的路径（使用 / 分隔，根路径本身为 "."）
+	Kind DiffKind    // 差异类型
+	A    os.FileInfo // A 侧的文件信息，只存在于 B 时为 nil
+	B    os.FileInfo // B 侧的文件信息，只存在于 A 时为 nil
+}
+
+// String 返回差异的可读描述
+func (d DiffEntry) String() string {
+	return fmt.Sprintf("%s: %s", d.Path, d.Kind)
+}
+
+// Compare 递归比较两个目录，返回结构化的差异列表（类似 diff -rq，额外比较权限和修改时间）
+// 只存在于一侧的目录只报告目录本身，不展开其内容
+//
+// 参数:
+//   - a: 路径 A（目录或文件）
+//   - b: 路径 B（目录或文件）
+//   - opts: 比较选项，为 nil 时使用默认选项
+//
+// 返回:
+//   - []DiffEntry: 按目录树顺序（深度优先，同级按名称）排列的差异列表，两侧完全一致时为空
+//   - error: 比较失败时返回错误
+func Compare(a, b string, opts *CompareOptions) ([]DiffEntry, error) {
+	return CompareContext(context.Background(), a, b, opts)
+}
+
+// CompareContext 递归比较两个目录，支持通过 ctx 取消
+//
+// 参数:
+//   - ctx: 上下文，取消后在下一个条目处停止
+//   - a: 路径 A（目录或文件）
+//   - b: 路径 B（目录或文件）
+//   - opts: 比较选项，为 nil 时使用默认选项
+//
+// 返回:
+//   - []DiffEntry: 差异列表，出错时包含出错前发现的差异
+//   - error: 比较失败时返回错误，取消时返回 ctx.Err()
+//
+// 示例:
+//
+//	diffs, err := fs.Compare("release/v1", "release/v2", &fs.CompareOptions{Exclude: []string{".git"}})
+//	for _, d := range diffs {
+//	    if d.Kind.Has(fs.DiffContent) {
+//	        fmt.Println("changed:", d.Path)
+//	    }
+//	}
+func CompareContext(ctx context.Context, a, b string, opts *CompareOptions) ([]DiffEntry, error) {
+	if opts == nil {
+		opts = &CompareOptions{}
+	}
+	algorithm := opts.Algorithm
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	if !hash.IsAlgorithmSupported(algorithm) {
+		return nil, fmt.Errorf("unsupported hash algorithm: %s", algorithm)
+	}
+
+	aAbs, bAbs, err := validateAndResolvePaths(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	// 复用复制的过滤逻辑
+	c := newCopier(ctx, &CopyOptions{
+		Include:  opts.Include,
+		Exclude:  opts.Exclude,
+		Filter:   opts.Filter,
+		MaxDepth: opts.MaxDepth,
+	})
+	if err := c.opts.validate(); err != nil {
+		return nil, err
+	}
+
+	cmp := &comparer{c: c, opts: opts, algorithm: algorithm, visited: make(map[inodeKey]bool)}
+	aInfo, err := cmp.stat(aAbs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get info '%s': %w", aAbs, err)
+	}
+	bInfo, err := cmp.stat(bAbs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get info '%s': %w", bAbs, err)
+	}
+
+	if err := cmp.compareEntry(".", aAbs, bAbs, aInfo, bInfo); err != nil {
+		return cmp.diffs, err
+	}
+	return cmp.diffs, nil
+}
+
+// comparer 单次比较操作的内部状态
+type comparer struct {
+	c         *copier
+	opts      *CompareOptions
+	algorithm string
+	diffs     []DiffEntry
+	visited   map[inodeKey]bool // 当前递归路径上 A 侧目录的 inode，用于检测符号链接循环
+}
+
+// stat 按是否跟随符号链接获取文件信息，悬空的符号链接按链接本身比较
+func (cmp *comparer) stat(path string) (os.FileInfo, error) {
+	if cmp.opts.FollowSymlinks {
+		info, err := os.Stat(path)
+		if err == nil || !os.IsNotExist(err) {
+			return info, err
+		}
+	}
+	return os.Lstat(path)
+}
+
+// compareEntry 比较两侧都存在的条目，目录会递归比较
+//
+// 参数:
+//   - rel: 相对路径（使用 / 分隔）
+//   - aPath: A 侧路径
+//   - bPath: B 侧路径
+//   - aInfo: A 侧文件信息
+//   - bInfo: B 侧文件信息
+//
+// 返回:
+//   - error: 读取或计算校验和失败时返回错误
+func (cmp *comparer) compareEntry(rel, aPath, bPath string, aInfo, bInfo os.FileInfo) error {
+	if aInfo.Mode().Type() != bInfo.Mode().Type() {
+		cmp.add(rel, DiffType, aInfo, bInfo)
+		return nil
+	}
+
+	var kind DiffKind
+	if aInfo.Mode()&^os.ModeType != bInfo.Mode()&^os.ModeType {
+		kind |= DiffMode
+	}
+
+	switch {
+	case aInfo.IsDir():
+		if kind != 0 {
+			cmp.add(rel, kind, aInfo, bInfo)
+		}
+		return cmp.compareDir(rel, aPath, bPath, aInfo)
+
+	case aInfo.Mode()&os.ModeSymlink != 0:
+		aTarget, err := os.Readlink(aPath)
+		if err != nil {
+			return fmt.Errorf("failed to read symlink '%s': %w", aPath, err)
+		}
+		bTarget, err := os.Readlink(bPath)
+		if err != nil {
+			return fmt.Errorf("failed to read symlink '%s': %w", bPath, err)
+		}
+		if aTarget != bTarget {
+			kind |= DiffContent
+		}
+
+	case aInfo.Mode().IsRegular():
+		if !aInfo.ModTime().Equal(bInfo.ModTime()) {
+			kind |= DiffModTime
+		}
+		if aInfo.Size() != bInfo.Size() {
+			kind |= DiffSize | DiffContent
+		} else if kind.Has(DiffModTime) || cmp.opts.Checksum {
+			same, err := cmp.sameContent(aPath, bPath)
+			if err != nil {
+				return err
+			}
+			if !same {
+				kind |= DiffContent
+			}
+		}
+	}
+
+	if kind != 0 {
+		cmp.add(rel, kind, aInfo, bInfo)
+	}
+	return nil
+}
+
+// compareDir 按名称合并两侧目录的条目并逐个比较
+func (cmp *comparer) compareDir(rel, aPath, bPath string, aInfo os.FileInfo) error {
+	// 跟随符号链接时，目录链接可能指向其祖先目录
+	if cmp.opts.FollowSymlinks {
+		if key, _, ok := fileInode(aInfo); ok {
+			if cmp.visited[key] {
+				return fmt.Errorf("symlink loop detected at '%s'", aPath)
+			}
+			cmp.visited[key] = true
+			defer delete(cmp.visited, key)
+		}
+	}
+
+	aNames, err := readDirNames(aPath)
+	if err != nil {
+		return err
+	}
+	bNames, err := readDirNames(bPath)
+	if err != nil {
+		return err
+	}
+
+	i, j := 0, 0
+	for i < len(aNames) || j < len(bNames) {
+		var name string
+		inA, inB := false, false
+		switch {
+		case j >= len(bNames) || (i < len(aNames) && aNames[i] < bNames[j]):
+			name, inA = aNames[i], true
+			i++
+		case i >= len(aNames) || bNames[j] < aNames[i]:
+			name, inB = bNames[j], true
+			j++
+		default:
+			name, inA, inB = aNames[i], true, true
+			i++
+			j++
+		}
+
+		childRel := name
+		if rel != "." {
+			childRel = rel + "/" + name
+		}
+		if err := cmp.compareChild(childRel, filepath.Join(aPath, name), filepath.Join(bPath, name), inA, inB); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareChild 比较目录中的单个条目，先应用过滤条件
+func (cmp *comparer) compareChild(rel, aPath, bPath string, inA, inB bool) error {
+	if err := cmp.c.ctx.Err(); err != nil {
+		return err
+	}
+
+	var aInfo, bInfo os.FileInfo
+	var err error
+	if inA {
+		if aInfo, err = cmp.stat(aPath); err != nil {
+			return fmt.Errorf("failed to get info '%s': %w", aPath, err)
+		}
+	}
+	if inB {
+		if bInfo, err = cmp.stat(bPath); err != nil {
+			return fmt.Errorf("failed to get info '%s': %w", bPath, err)
+		}
+	}
+
+	// 两侧都存在时按 A 侧条目过滤
+	filterPath, filterInfo := aPath, aInfo
+	if !inA {
+		filterPath, filterInfo = bPath, bInfo
+	}
+	if err := cmp.c.filterEntry(filterPath, filepath.FromSlash(rel), fs.FileInfoToDirEntry(filterInfo)); err != nil {
+		if errors.Is(err, errSkipEntry) || errors.Is(err, fs.SkipDir) {
+			return nil
+		}
+		return err
+	}
+
+	switch {
+	case !inB:
+		cmp.add(rel, DiffOnlyInA, aInfo, nil)
+		return nil
+	case !inA:
+		cmp.add(rel, DiffOnlyInB, nil, bInfo)
+		return nil
+	}
+	return cmp.compareEntry(rel, aPath, bPath, aInfo, bInfo)
+}
+
+// sameContent 通过校验和判断两个文件内容是否相同
+func (cmp *comparer) sameContent(aPath, bPath string) (bool, error) {
+	aSum, err := hash.Checksum(aPath, cmp.algorithm)
+	if err != nil {
+		return false, fmt.Errorf("failed to checksum '%s': %w", aPath, err)
+	}
+	bSum, err := hash.Checksum(bPath, cmp.algorithm)
+	if err != nil {
+		return false, fmt.Errorf("failed to checksum '%s': %w", bPath, err)
+	}
+	return aSum == bSum, nil
+}
+
+// add 记录一处差异
+func (cmp *comparer) add(rel string, kind DiffKind, aInfo, bInfo os.FileInfo) {
+	cmp.diffs = append(cmp.diffs, DiffEntry{Path: rel, Kind: kind, A: aInfo, B: bInfo})
+}
+
+// readDirNames 读取目录中的条目名称并排序
+func readDirNames(dir string) ([]string, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open directory '%s': %w", dir, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	names, err := f.Readdirnames(-1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory '%s': %w", dir, err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
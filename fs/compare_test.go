@@ -0,0 +1,232 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// diffMap 将差异列表转换为路径到差异类型的映射
+func diffMap(diffs []DiffEntry) map[string]DiffKind {
+	m := make(map[string]DiffKind, len(diffs))
+	for _, d := range diffs {
+		m[d.Path] = d.Kind
+	}
+	return m
+}
+
+// setupCompareTrees 创建两棵初始内容相同的目录树
+func setupCompareTrees(t *testing.T) (string, string) {
+	t.Helper()
+
+	a := filepath.Join(t.TempDir(), "a")
+	writeSyncFile(t, filepath.Join(a, "same.txt"), "same")
+	writeSyncFile(t, filepath.Join(a, "sub", "file.txt"), "content")
+	writeSyncFile(t, filepath.Join(a, "sub", "deep", "x.txt"), "x")
+	b := filepath.Join(t.TempDir(), "b")
+	if _, err := Sync(a, b, nil); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	return a, b
+}
+
+func TestCompareIdentical(t *testing.T) {
+	a, b := setupCompareTrees(t)
+
+	diffs, err := Compare(a, b, &CompareOptions{Checksum: true})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Expected no differences, got %v", diffs)
+	}
+}
+
+func TestCompareDifferences(t *testing.T) {
+	a, b := setupCompareTrees(t)
+
+	writeSyncFile(t, filepath.Join(a, "only-a.txt"), "a")
+	writeSyncFile(t, filepath.Join(b, "only-b", "inner.txt"), "b")
+	writeSyncFile(t, filepath.Join(b, "sub", "file.txt"), "changed content")
+	writeSyncFile(t, filepath.Join(a, "kind"), "file")
+	writeSyncFile(t, filepath.Join(b, "kind", "f.txt"), "dir")
+
+	// 只改变修改时间：校验和确认内容相同
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(b, "same.txt"), later, later); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+	// 大小和修改时间不变但内容改变
+	xPath := filepath.Join(b, "sub", "deep", "x.txt")
+	info, _ := os.Stat(xPath)
+	writeSyncFile(t, xPath, "y")
+	if err := os.Chtimes(xPath, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	diffs, err := Compare(a, b, nil)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	got := diffMap(diffs)
+	want := map[string]DiffKind{
+		"kind":         DiffType,
+		"only-a.txt":   DiffOnlyInA,
+		"only-b":       DiffOnlyInB,
+		"same.txt":     DiffModTime,
+		"sub/file.txt": DiffSize | DiffModTime | DiffContent,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Differences = %v, want %v", diffs, want)
+	}
+	for path, kind := range want {
+		if path == "sub/file.txt" {
+			// 重写文件后修改时间可能仍在同一时钟刻度内
+			got[path] |= DiffModTime
+		}
+		if got[path] != kind {
+			t.Errorf("%s: kind = %s, want %s", path, got[path], kind)
+		}
+	}
+
+	// 校验和模式能发现大小和修改时间都相同的内容变化
+	diffs, err = Compare(filepath.Join(a, "sub"), filepath.Join(b, "sub"), &CompareOptions{Checksum: true, Include: []string{"x.txt"}})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(diffs) != 1 || diffs[0].Path != "deep/x.txt" || diffs[0].Kind != DiffContent {
+		t.Fatalf("Checksum differences = %v, want [deep/x.txt: content]", diffs)
+	}
+	if diffs[0].A == nil || diffs[0].B == nil {
+		t.Error("Expected file info on both sides")
+	}
+}
+
+func TestCompareModeAndFilters(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Permission bits are not comparable on Windows")
+	}
+	a, b := setupCompareTrees(t)
+
+	if err := os.Chmod(filepath.Join(b, "same.txt"), 0o600); err != nil {
+		t.Fatalf("Chmod failed: %v", err)
+	}
+	if err := os.Chmod(filepath.Join(b, "sub"), 0o700); err != nil {
+		t.Fatalf("Chmod failed: %v", err)
+	}
+	writeSyncFile(t, filepath.Join(b, "sub", "deep", "extra.txt"), "extra")
+
+	diffs, err := Compare(a, b, nil)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	got := diffMap(diffs)
+	if got["same.txt"] != DiffMode || got["sub"] != DiffMode || got["sub/deep/extra.txt"] != DiffOnlyInB {
+		t.Errorf("Unexpected differences: %v", diffs)
+	}
+
+	// 排除的目录整体不比较
+	diffs, err = Compare(a, b, &CompareOptions{Exclude: []string{"sub"}})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(diffs) != 1 || diffs[0].Path != "same.txt" {
+		t.Errorf("Differences with exclude = %v, want [same.txt: mode]", diffs)
+	}
+
+	if _, err := Compare(a, b, &CompareOptions{Exclude: []string{"["}}); err == nil {
+		t.Error("Expected invalid pattern error")
+	}
+	if _, err := Compare(a, b, &CompareOptions{Algorithm: "crc"}); err == nil {
+		t.Error("Expected unsupported algorithm error")
+	}
+}
+
+func TestCompareSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Symlinks require privileges on Windows")
+	}
+	a, b := setupCompareTrees(t)
+
+	if err := os.Symlink("sub/file.txt", filepath.Join(a, "link")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(b, "sub", "file.txt"), filepath.Join(b, "link")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+
+	diffs, err := Compare(a, b, nil)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(diffs) != 1 || diffs[0].Path != "link" || diffs[0].Kind != DiffContent {
+		t.Errorf("Differences = %v, want [link: content]", diffs)
+	}
+
+	// 跟随符号链接时两个链接指向相同内容
+	diffs, err = Compare(a, b, &CompareOptions{FollowSymlinks: true, Checksum: true})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if got := diffMap(diffs); got["link"]&^DiffModTime != 0 {
+		t.Errorf("Differences with FollowSymlinks = %v", diffs)
+	}
+
+	// 悬空的链接按链接本身比较，不中止比较
+	if err := os.Symlink("missing", filepath.Join(a, "dangling")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+	if err := os.Symlink("other", filepath.Join(b, "dangling")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+	writeSyncFile(t, filepath.Join(a, "gone"), "file")
+	if err := os.Symlink("missing", filepath.Join(b, "gone")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+	diffs, err = Compare(a, b, &CompareOptions{FollowSymlinks: true})
+	if err != nil {
+		t.Fatalf("Compare with dangling symlinks failed: %v", err)
+	}
+	if got := diffMap(diffs); got["dangling"] != DiffContent || got["gone"] != DiffType {
+		t.Errorf("Differences with dangling symlinks = %v", diffs)
+	}
+
+	// 指向祖先目录的链接形成循环
+	if err := os.Symlink("..", filepath.Join(a, "sub", "loop")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+	if err := os.Symlink("..", filepath.Join(b, "sub", "loop")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+	if _, err := Compare(a, b, &CompareOptions{FollowSymlinks: true}); err == nil {
+		t.Error("Expected symlink loop error")
+	}
+}
+
+func TestCompareContextCancel(t *testing.T) {
+	a, b := setupCompareTrees(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CompareContext(ctx, a, b, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDiffKindString(t *testing.T) {
+	tests := map[DiffKind]string{
+		0:                                    "none",
+		DiffOnlyInA:                          "only-in-a",
+		DiffSize | DiffModTime | DiffContent: "size|mtime|content",
+		DiffMode | DiffKind(1<<10):           "mode|DiffKind(0x400)",
+	}
+	for kind, want := range tests {
+		if got := kind.String(); got != want {
+			t.Errorf("DiffKind(%d).String() = %q, want %q", uint(kind), got, want)
+		}
+	}
+}
@@ -18,7 +18,8 @@
 //
 // 【特殊类型】
 //   - 符号链接：Linux/macOS 保留链接，Windows 当作普通文件复制
-//   - 特殊文件：设备文件、命名管道等（仅 Unix 系统）
+//   - 特殊文件：设备文件、命名管道通过 mknod/mkfifo 重建（仅 Unix 系统，设备文件需要 root 权限）
+//   - 套接字：目录中的套接字被跳过，直接复制套接字返回错误
 //
 // 移动功能
 //
@@ -45,15 +46,14 @@
 package fs
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
-
-	"gitee.com/MM-Q/go-kit/pool"
 )
 
 // Copy 通用复制函数，自动判断源路径类型并调用相应的复制函数
@@ -121,6 +121,18 @@ func CopyEx(src, dst string, overwrite bool) (err error) {
 // 返回:
 //   - error: 复制失败时返回错误
 func copyExInternal(srcAbs, dstAbs string, overwrite bool) error {
+	return newCopier(context.Background(), &CopyOptions{Overwrite: overwrite}).copy(srcAbs, dstAbs)
+}
+
+// copy 按源路径类型执行复制，接受已验证的绝对路径
+//
+// 参数:
+//   - srcAbs: 已验证的源绝对路径
+//   - dstAbs: 已验证的目标绝对路径（已通过智能路径处理）
+//
+// 返回:
+//   - error: 复制失败时返回错误
+func (c *copier) copy(srcAbs, dstAbs string) error {
 	// 获取源路径信息（使用 Lstat 避免跟随符号链接）
 	srcInfo, localErr := os.Lstat(srcAbs)
 	if localErr != nil {
@@ -128,11 +140,14 @@ func copyExInternal(srcAbs, dstAbs string, overwrite bool) error {
 	}
 
 	// 根据源路径类型调用相应的复制函数
+	if srcInfo.Mode()&os.ModeSocket != 0 {
+		return fmt.Errorf("cannot copy socket '%s'", srcAbs)
+	}
 	if srcInfo.IsDir() {
-		return copyDir(srcAbs, dstAbs, overwrite)
+		return c.copyDir(srcAbs, dstAbs)
 	} else {
 		// 处理所有文件类型（普通文件、符号链接、特殊文件等）
-		return copyFileRouter(srcAbs, dstAbs, srcInfo, overwrite)
+		return c.copyFileRouter(srcAbs, dstAbs, srcInfo)
 	}
 }
 
@@ -278,95 +293,102 @@ func cleanupBackup(backupPath string) {
 //   - srcAbs: 源文件绝对路径
 //   - dstAbs: 目标文件绝对路径
 //   - srcInfo: 源文件信息（包含 Mode、Size 等）
-//   - overwrite: 是否允许覆盖已存在的目标文件
 //
 // 返回:
+//   - CopyMethod: 数据的复制方式
 //   - error: 复制失败时返回错误
-func copyFile(srcAbs, dstAbs string, srcInfo os.FileInfo, overwrite bool) error {
+func (c *copier) copyFile(srcAbs, dstAbs string, srcInfo os.FileInfo) (CopyMethod, error) {
 	// 注意：路径验证已在 CopyEx 入口处统一完成，此处无需重复验证
 
 	// 安全覆盖机制：处理已存在的目标文件
-	backupPath, err := handleBackupAndRestore(dstAbs, overwrite)
+	backupPath, err := handleBackupAndRestore(dstAbs, c.opts.Overwrite)
 	if err != nil {
-		return err
+		return CopyNone, err
 	}
 
+	// 统一清理资源：成功时删除备份，失败（包括取消）时删除临时文件并恢复备份
+	var out *os.File
+	tmp := ""
+	success := false
+	defer func() {
+		if out != nil {
+			_ = out.Close()
+			out = nil // 防止重复关闭
+		}
+		if success {
+			cleanupBackup(backupPath)
+			return
+		}
+		if tmp != "" {
+			_ = os.Remove(tmp) // 清理临时文件
+		}
+		restoreBackup(dstAbs, backupPath)
+	}()
+
 	// 打开源文件
 	in, err := os.Open(srcAbs)
 	if err != nil {
-		return fmt.Errorf("failed to open source file '%s': %w", srcAbs, err)
+		return CopyNone, fmt.Errorf("failed to open source file '%s': %w", srcAbs, err)
 	}
 	defer func() { _ = in.Close() }()
 
 	// 检查是否为普通文件
 	if !srcInfo.Mode().IsRegular() {
-		return fmt.Errorf("source '%s' is not a regular file", srcAbs)
+		return CopyNone, fmt.Errorf("source '%s' is not a regular file", srcAbs)
 	}
 
 	// 确保目标目录存在
 	dstDir := filepath.Dir(dstAbs)
 	if err := os.MkdirAll(dstDir, 0o755); err != nil {
-		return fmt.Errorf("failed to create destination directory '%s': %w", dstDir, err)
+		return CopyNone, fmt.Errorf("failed to create destination directory '%s': %w", dstDir, err)
 	}
 
 	// 创建临时文件（与目标同目录，保证 rename 原子性）
 	// 使用 pid + 纳秒时间戳确保同一进程内并发安全
-	tmp := dstAbs + ".tmp." + fmt.Sprintf("%d.%d", os.Getpid(), time.Now().UnixNano())
-	out, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_EXCL, srcInfo.Mode())
+	tmpPath := dstAbs + ".tmp." + fmt.Sprintf("%d.%d", os.Getpid(), time.Now().UnixNano())
+	out, err = os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, srcInfo.Mode())
 	if err != nil {
-		return fmt.Errorf("failed to create temporary file '%s': %w", tmp, err)
+		return CopyNone, fmt.Errorf("failed to create temporary file '%s': %w", tmpPath, err)
 	}
+	tmp = tmpPath
 
-	// 统一清理资源
-	success := false
-	defer func() {
-		if out != nil {
-			_ = out.Close()
-			out = nil // 防止重复关闭
-		}
-		if !success {
-			_ = os.Remove(tmp) // 清理临时文件
-		}
-	}()
-
-	// 根据文件大小选择处理方式
-	if srcInfo.Size() == 0 {
-		// 空文件：跳过数据复制，直接进行后续操作
-	} else {
-		// 非空文件：使用缓冲区进行数据拷贝
-		bufSize := pool.CalculateBufferSize(srcInfo.Size())
-		buf := pool.GetByteCap(bufSize)
-		defer pool.PutByte(buf)
+	// 复制数据：reflink → 稀疏复制 → 内核复制 → 缓冲区复制，空文件跳过
+	method, err := c.copyData(out, in, srcAbs, srcInfo)
+	if err != nil {
+		return CopyNone, fmt.Errorf("failed to copy data from '%s' to '%s': %w", srcAbs, tmp, err)
+	}
 
-		if _, err := io.CopyBuffer(out, in, buf); err != nil {
-			return fmt.Errorf("failed to copy data from '%s' to '%s': %w", srcAbs, tmp, err)
-		}
+	// 数据复制期间已取消时不再提交（reflink 和内核复制可能一次完成整个文件）
+	if err := c.ctx.Err(); err != nil {
+		return CopyNone, err
+	}
 
-		// 强制刷盘，确保数据持久化（仅对非空文件）
+	// 强制刷盘，确保数据持久化（仅对非空文件）
+	if srcInfo.Size() > 0 {
 		if err := out.Sync(); err != nil {
-			return fmt.Errorf("failed to sync temporary file '%s': %w", tmp, err)
+			return CopyNone, fmt.Errorf("failed to sync temporary file '%s': %w", tmp, err)
 		}
-
 	}
 
 	// 在重命名前关闭文件句柄(Windows要求)
 	if err := out.Close(); err != nil {
-		return fmt.Errorf("failed to close temporary file '%s': %w", tmp, err)
+		out = nil
+		return CopyNone, fmt.Errorf("failed to close temporary file '%s': %w", tmp, err)
 	}
 	out = nil // 标记为已关闭
 
-	// 原子重命名
-	if err := os.Rename(tmp, dstAbs); err != nil {
-		// 复制失败，恢复备份文件
-		restoreBackup(dstAbs, backupPath)
-		return fmt.Errorf("failed to rename temporary file '%s' to '%s': %w", tmp, dstAbs, err)
+	// 在重命名前保留元数据，目标文件出现时即带有完整的属性
+	if err := c.preserveMetadata(srcAbs, tmp, srcInfo); err != nil {
+		return CopyNone, err
 	}
 
-	// 复制成功，删除备份文件
-	cleanupBackup(backupPath)
+	// 原子重命名（失败时由 defer 恢复备份文件）
+	if err := os.Rename(tmp, dstAbs); err != nil {
+		return CopyNone, fmt.Errorf("failed to rename temporary file '%s' to '%s': %w", tmp, dstAbs, err)
+	}
 
 	success = true
-	return nil
+	return method, nil
 }
 
 // copySymlink 复制符号链接
@@ -376,11 +398,10 @@ func copyFile(srcAbs, dstAbs string, srcInfo os.FileInfo, overwrite bool) error
 // 参数:
 //   - srcAbs: 源符号链接绝对路径
 //   - dstAbs: 目标绝对路径
-//   - overwrite: 是否允许覆盖已存在的目标
 //
 // 返回:
 //   - error: 复制失败时返回错误
-func copySymlink(srcAbs, dstAbs string, overwrite bool) error {
+func (c *copier) copySymlink(srcAbs, dstAbs string) error {
 	// Windows 平台：当作普通文件复制
 	// 注意：Windows 符号链接可能指向目录，需要先获取目标信息
 	if runtime.GOOS == "windows" {
@@ -389,12 +410,13 @@ func copySymlink(srcAbs, dstAbs string, overwrite bool) error {
 		if err != nil {
 			return fmt.Errorf("failed to get symlink target info '%s': %w", srcAbs, err)
 		}
-		return copyFile(srcAbs, dstAbs, targetInfo, overwrite)
+		_, err = c.copyFile(srcAbs, dstAbs, targetInfo)
+		return err
 	}
 
 	// 非 Windows 平台：创建符号链接
 	// 安全覆盖机制：处理已存在的目标符号链接
-	backupPath, err := handleBackupAndRestore(dstAbs, overwrite)
+	backupPath, err := handleBackupAndRestore(dstAbs, c.opts.Overwrite)
 	if err != nil {
 		return err
 	}
@@ -419,24 +441,36 @@ func copySymlink(srcAbs, dstAbs string, overwrite bool) error {
 		return fmt.Errorf("failed to create symlink '%s' -> '%s': %w", dstAbs, target, err)
 	}
 
+	// 保留符号链接自身的元数据（不跟随链接）
+	if c.opts.preserves() {
+		srcInfo, err := os.Lstat(srcAbs)
+		if err == nil {
+			err = c.preserveMetadata(srcAbs, dstAbs, srcInfo)
+		}
+		if err != nil {
+			_ = os.Remove(dstAbs)
+			restoreBackup(dstAbs, backupPath)
+			return err
+		}
+	}
+
 	// 创建成功，删除备份
 	cleanupBackup(backupPath)
 	return nil
 }
 
-// copySpecialFile 复制特殊文件（设备文件、命名管道、套接字等）
-// 对于特殊文件，只创建一个具有相同权限模式的空文件
+// copySpecialFile 复制特殊文件（设备文件、命名管道）
+// 使用 mknod/mkfifo 重新创建，设备文件保留主/次设备号（需要 root 权限）
 //
 // 参数:
 //   - srcAbs: 源特殊文件绝对路径
 //   - dstAbs: 目标文件绝对路径
-//   - overwrite: 是否允许覆盖已存在的目标
 //
 // 返回:
 //   - error: 复制失败时返回错误
-func copySpecialFile(srcAbs, dstAbs string, overwrite bool) error {
+func (c *copier) copySpecialFile(srcAbs, dstAbs string) error {
 	// 安全覆盖机制：处理已存在的目标文件
-	backupPath, err := handleBackupAndRestore(dstAbs, overwrite)
+	backupPath, err := handleBackupAndRestore(dstAbs, c.opts.Overwrite)
 	if err != nil {
 		return err
 	}
@@ -455,19 +489,17 @@ func copySpecialFile(srcAbs, dstAbs string, overwrite bool) error {
 		return fmt.Errorf("failed to create destination directory '%s': %w", dstDir, err)
 	}
 
-	// 创建一个空文件，保持相同的权限模式
-	file, err := os.OpenFile(dstAbs, os.O_CREATE|os.O_WRONLY, srcInfo.Mode())
-	if err != nil {
+	// 按源文件类型创建设备文件或命名管道
+	if err := makeSpecialFile(dstAbs, srcInfo); err != nil {
 		restoreBackup(dstAbs, backupPath)
-		return fmt.Errorf("failed to create special file '%s': %w", dstAbs, err)
+		return err
 	}
 
-	// 立即关闭文件
-	if err := file.Close(); err != nil {
-		// 关闭失败，删除创建的文件并恢复备份
+	// 保留元数据
+	if err := c.preserveMetadata(srcAbs, dstAbs, srcInfo); err != nil {
 		_ = os.Remove(dstAbs)
 		restoreBackup(dstAbs, backupPath)
-		return fmt.Errorf("failed to close special file '%s': %w", dstAbs, err)
+		return err
 	}
 
 	// 创建成功，删除备份
@@ -482,24 +514,47 @@ func copySpecialFile(srcAbs, dstAbs string, overwrite bool) error {
 //   - srcAbs: 源文件绝对路径
 //   - dstAbs: 目标文件绝对路径
 //   - srcInfo: 源文件信息（包含 Mode、Size 等）
-//   - overwrite: 是否允许覆盖已存在的目标
 //
 // 返回:
 //   - error: 复制失败时返回错误
-func copyFileRouter(srcAbs, dstAbs string, srcInfo os.FileInfo, overwrite bool) error {
+func (c *copier) copyFileRouter(srcAbs, dstAbs string, srcInfo os.FileInfo) error {
+	// 每个文件开始前检查是否已取消
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
+	// 套接字离开创建它的进程没有意义，跳过并在进度中报告
+	if srcInfo.Mode()&os.ModeSocket != 0 {
+		c.skipFile(srcAbs)
+		return nil
+	}
+	c.startFile(srcAbs)
+
+	var err error
+	method := CopyNone
 	switch {
 	case srcInfo.Mode().IsRegular():
 		// 普通文件
-		return copyFile(srcAbs, dstAbs, srcInfo, overwrite)
+		method, err = c.copyFile(srcAbs, dstAbs, srcInfo)
 
 	case srcInfo.Mode()&os.ModeSymlink != 0:
 		// 符号链接
-		return copySymlink(srcAbs, dstAbs, overwrite)
+		err = c.copySymlink(srcAbs, dstAbs)
 
 	default:
-		// 其他特殊文件（设备文件、命名管道、套接字等）
-		return copySpecialFile(srcAbs, dstAbs, overwrite)
+		// 其他特殊文件（设备文件、命名管道）
+		err = c.copySpecialFile(srcAbs, dstAbs)
+	}
+
+	if err == nil {
+		if c.track {
+			c.mu.Lock()
+			c.copiedFiles = append(c.copiedFiles, srcAbs)
+			c.mu.Unlock()
+		}
+		c.finishFile(method)
 	}
+	return err
 }
 
 // copyDir 内部复制目录逻辑
@@ -508,11 +563,10 @@ func copyFileRouter(srcAbs, dstAbs string, srcInfo os.FileInfo, overwrite bool)
 // 参数:
 //   - srcAbs: 源目录绝对路径
 //   - dstAbs: 目标目录绝对路径
-//   - overwrite: 是否允许覆盖已存在的目标文件
 //
 // 返回:
 //   - error: 复制失败时返回错误
-func copyDir(srcAbs, dstAbs string, overwrite bool) error {
+func (c *copier) copyDir(srcAbs, dstAbs string) error {
 	// 单独调用子目录检查（避免重复基础验证）
 	if err := validatePathRelations(srcAbs, dstAbs, true); err != nil {
 		return err
@@ -530,27 +584,41 @@ func copyDir(srcAbs, dstAbs string, overwrite bool) error {
 	}
 
 	// 安全覆盖机制：处理已存在的目标目录
-	backupPath, err := handleBackupAndRestore(dstAbs, overwrite)
+	backupPath, err := handleBackupAndRestore(dstAbs, c.opts.Overwrite)
 	if err != nil {
 		return err
 	}
 
 	// 创建目标目录，使用合适的权限（至少需要写权限以便后续操作）
-	dirMode := srcInfo.Mode()
-	if dirMode&0o200 == 0 {
-		// 如果源目录没有写权限，临时添加写权限以便复制操作
-		dirMode |= 0o200
-	}
-	if err := os.MkdirAll(dstAbs, dirMode); err != nil {
+	if err := os.MkdirAll(dstAbs, writableDirMode(srcInfo.Mode())); err != nil {
 		restoreBackup(dstAbs, backupPath)
 		return fmt.Errorf("failed to create destination directory '%s': %w", dstAbs, err)
 	}
 
+	// 记录已创建的目录，复制完成后统一恢复权限和元数据
+	dirs := []dirEntry{{src: srcAbs, dst: dstAbs, info: srcInfo}}
+
+	// 硬链接在所有文件复制完成后统一创建，确保链接目标已存在
+	var links []hardlink
+	var tracker linkTracker
+	if c.opts.PreserveHardlinks {
+		tracker = linkTracker{}
+	}
+
+	// 并行模式：遍历协程创建目录，文件交给工作协程复制
+	var queue *copyQueue
+	if c.opts.Workers > 1 {
+		queue = c.newCopyQueue(c.opts.Workers)
+	}
+
 	// 遍历源目录
 	copyErr := filepath.WalkDir(srcAbs, func(path string, entry os.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("failed to access path '%s': %w", path, err)
 		}
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
 
 		// 计算相对路径
 		relPath, err := filepath.Rel(srcAbs, path)
@@ -563,6 +631,14 @@ func copyDir(srcAbs, dstAbs string, overwrite bool) error {
 			return nil
 		}
 
+		// 应用过滤条件，被排除的目录直接剪枝（返回 fs.SkipDir）
+		if err := c.filterEntry(path, relPath, entry); err != nil {
+			if errors.Is(err, errSkipEntry) {
+				return nil
+			}
+			return err
+		}
+
 		// 构建目标路径
 		dstPath := filepath.Join(dstAbs, relPath)
 
@@ -572,9 +648,13 @@ func copyDir(srcAbs, dstAbs string, overwrite bool) error {
 			if err != nil {
 				return fmt.Errorf("failed to get directory info '%s': %w", path, err)
 			}
-			if err := os.MkdirAll(dstPath, info.Mode()); err != nil {
+			if err := os.MkdirAll(dstPath, writableDirMode(info.Mode())); err != nil {
 				return fmt.Errorf("failed to create directory '%s': %w", dstPath, err)
 			}
+			dirs = append(dirs, dirEntry{src: path, dst: dstPath, info: info})
+			if c.track {
+				c.copiedDirs = append(c.copiedDirs, path)
+			}
 			return nil
 		}
 
@@ -584,9 +664,35 @@ func copyDir(srcAbs, dstAbs string, overwrite bool) error {
 		if err != nil {
 			return fmt.Errorf("failed to get file info '%s': %w", path, err)
 		}
-		return copyFileRouter(path, dstPath, info, overwrite)
+		if tracker != nil {
+			if target, ok := tracker.track(info, dstPath); ok {
+				links = append(links, hardlink{src: path, dst: dstPath, target: target})
+				return nil
+			}
+		}
+		if queue != nil {
+			return queue.submit(path, dstPath, info)
+		}
+		return c.copyFileRouter(path, dstPath, info)
 	})
 
+	// 等待所有工作协程结束后再处理结果，合并遍历错误和各文件的复制错误
+	if queue != nil {
+		if errors.Is(copyErr, errCopyAborted) {
+			copyErr = nil
+		}
+		copyErr = errors.Join(copyErr, queue.wait())
+	}
+
+	// 目录内容全部复制后再恢复目录元数据，子目录先于父目录处理，
+	// 避免写入子项时修改父目录的修改时间
+	if copyErr == nil {
+		copyErr = c.createLinks(links)
+	}
+	if copyErr == nil {
+		copyErr = c.finishDirs(dirs)
+	}
+
 	// 处理复制结果
 	if copyErr != nil {
 		// 复制失败，清理已复制的内容并恢复备份
@@ -595,12 +701,47 @@ func copyDir(srcAbs, dstAbs string, overwrite bool) error {
 		return copyErr
 	}
 
-	// 复制成功，恢复目录的原始权限（如果之前临时修改了权限）
-	if srcInfo.Mode() != dirMode {
-		_ = os.Chmod(dstAbs, srcInfo.Mode()) // 忽略权限恢复错误
-	}
-
 	// 复制成功，删除备份目录
 	cleanupBackup(backupPath)
 	return nil
 }
+
+// dirEntry 复制过程中创建的目录
+type dirEntry struct {
+	src  string      // 源目录路径
+	dst  string      // 目标目录路径
+	info os.FileInfo // 源目录信息
+}
+
+// writableDirMode 返回创建目录时使用的权限
+// 源目录缺少所有者读写执行权限时临时补齐，以便复制目录内容，复制完成后再恢复
+//
+// 参数:
+//   - mode: 源目录权限
+//
+// 返回:
+//   - os.FileMode: 创建目录时使用的权限
+func writableDirMode(mode os.FileMode) os.FileMode {
+	return mode | 0o700
+}
+
+// finishDirs 按从深到浅的顺序恢复目录的原始权限和元数据
+//
+// 参数:
+//   - dirs: 复制过程中创建的目录（按遍历顺序，父目录在前）
+//
+// 返回:
+//   - error: 恢复元数据失败时返回错误
+func (c *copier) finishDirs(dirs []dirEntry) error {
+	for i := len(dirs) - 1; i >= 0; i-- {
+		dir := dirs[i]
+		if err := c.preserveMetadata(dir.src, dir.dst, dir.info); err != nil {
+			return err
+		}
+		// 恢复目录的原始权限（如果之前临时修改了权限）
+		if mode := dir.info.Mode(); writableDirMode(mode) != mode {
+			_ = os.Chmod(dir.dst, mode&preservedModeBits) // 忽略权限恢复错误
+		}
+	}
+	return nil
+}
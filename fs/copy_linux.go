@@ -0,0 +1,75 @@
+//go:build linux
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// copyRangeChunk 每次 copy_file_range 调用复制的最大字节数，在两次调用之间检查取消并报告进度
+const copyRangeChunk = 8 << 20
+
+// reflinkFile 使用 FICLONE 将源文件克隆到目标文件，两者共享存储块直到被修改
+//
+// 参数:
+//   - out: 目标文件
+//   - in: 源文件
+//
+// 返回:
+//   - error: 文件系统不支持时返回包装了 ErrReflinkUnsupported 的错误
+func reflinkFile(out, in *os.File) error {
+	err := unix.IoctlFileClone(int(out.Fd()), int(in.Fd()))
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(err, unix.EOPNOTSUPP), errors.Is(err, unix.ENOTTY), errors.Is(err, unix.EXDEV),
+		errors.Is(err, unix.EINVAL), errors.Is(err, unix.ENOSYS), errors.Is(err, unix.EBADF):
+		return fmt.Errorf("%w: %w", ErrReflinkUnsupported, err)
+	}
+	return err
+}
+
+// copyFileRange 使用 copy_file_range 在内核中复制数据，分块调用以便检查取消和报告进度
+//
+// 参数:
+//   - out: 目标文件
+//   - in: 源文件
+//   - size: 需要复制的字节数
+//
+// 返回:
+//   - int64: 已复制的字节数
+//   - error: 内核不支持时返回 errFastCopyUnsupported，文件偏移停在已复制的位置
+func (c *copier) copyFileRange(out, in *os.File, size int64) (int64, error) {
+	var copied int64
+	for copied < size {
+		if err := c.ctx.Err(); err != nil {
+			return copied, err
+		}
+
+		n, err := unix.CopyFileRange(int(in.Fd()), nil, int(out.Fd()), nil, int(min(size-copied, copyRangeChunk)), 0)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			// 与标准库相同：这些错误表示当前文件系统或内核不支持，回退到普通复制
+			switch {
+			case errors.Is(err, unix.ENOSYS), errors.Is(err, unix.EXDEV), errors.Is(err, unix.EINVAL),
+				errors.Is(err, unix.EIO), errors.Is(err, unix.EOPNOTSUPP), errors.Is(err, unix.EPERM):
+				return copied, errFastCopyUnsupported
+			}
+			return copied, fmt.Errorf("copy_file_range failed: %w", err)
+		}
+		if n == 0 {
+			// 源文件在复制过程中被截断，剩余部分交给普通复制处理
+			return copied, errFastCopyUnsupported
+		}
+		copied += int64(n)
+		c.addBytes(int64(n))
+	}
+	return copied, nil
+}
@@ -0,0 +1,151 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gitee.com/MM-Q/go-kit/pool"
+)
+
+// ErrReflinkUnsupported 表示当前平台或文件系统不支持 reflink（写时复制克隆）
+var ErrReflinkUnsupported = errors.New("reflink not supported")
+
+// errFastCopyUnsupported 表示内核复制不可用，需要回退到缓冲区复制（仅内部使用）
+var errFastCopyUnsupported = errors.New("kernel copy not supported")
+
+// ReflinkMode reflink（写时复制克隆）策略
+type ReflinkMode int
+
+const (
+	ReflinkPrefer  ReflinkMode = iota // 优先尝试 reflink，不支持时回退到内核复制或缓冲区复制（默认）
+	ReflinkRequire                    // 必须使用 reflink，不支持时返回 ErrReflinkUnsupported
+	ReflinkDisable                    // 禁用 reflink 和内核复制，始终复制数据到独立的存储块
+)
+
+// CopyMethod 文件数据的复制方式
+type CopyMethod int
+
+const (
+	CopyNone     CopyMethod = iota // 未复制数据（空文件、符号链接、特殊文件）
+	CopyReflink                    // reflink 写时复制克隆（Linux FICLONE，btrfs/XFS 等）
+	CopyKernel                     // 内核内复制（Linux copy_file_range），数据不经过用户空间
+	CopyBuffer                     // 用户空间缓冲区复制
+	CopySparse                     // 稀疏复制：只复制数据区段，在目标中重建空洞
+	CopyHardlink                   // 创建指向已复制文件的硬链接
+)
+
+// copyMethodNames 复制方式与名称的对应关系
+var copyMethodNames = map[CopyMethod]string{
+	CopyNone:     "none",
+	CopyReflink:  "reflink",
+	CopyKernel:   "copy_file_range",
+	CopyBuffer:   "buffer",
+	CopySparse:   "sparse",
+	CopyHardlink: "hardlink",
+}
+
+// String 返回复制方式的名称
+func (m CopyMethod) String() string {
+	if name, ok := copyMethodNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("CopyMethod(%d)", int(m))
+}
+
+// copyData 将源文件的数据写入目标文件，按 reflink → 稀疏复制 → 内核复制 → 缓冲区复制的顺序选择可用方式
+//
+// 参数:
+//   - out: 目标文件（新创建的空文件）
+//   - in: 源文件
+//   - srcAbs: 源文件路径（用于错误信息）
+//   - srcInfo: 源文件信息
+//
+// 返回:
+//   - CopyMethod: 实际使用的复制方式
+//   - error: 复制失败时返回错误
+func (c *copier) copyData(out, in *os.File, srcAbs string, srcInfo os.FileInfo) (CopyMethod, error) {
+	size := srcInfo.Size()
+	if size == 0 {
+		return CopyNone, nil
+	}
+
+	if c.opts.Reflink != ReflinkDisable {
+		err := reflinkFile(out, in)
+		if err == nil {
+			c.addBytes(size)
+			return CopyReflink, nil
+		}
+		if c.opts.Reflink == ReflinkRequire {
+			return CopyNone, fmt.Errorf("reflink required: %w", err)
+		}
+	}
+
+	// 占用空间小于文件大小说明存在空洞，只复制数据区段；文件系统不支持时按普通文件复制
+	if allocatedSize(srcInfo) < size {
+		err := c.copySparse(out, in, size)
+		if err == nil {
+			return CopySparse, nil
+		}
+		if !errors.Is(err, errFastCopyUnsupported) {
+			return CopyNone, err
+		}
+	}
+
+	return c.copyRange(out, in, size)
+}
+
+// copyExtent 复制源文件中的一个数据区段到目标文件的相同位置
+//
+// 参数:
+//   - out: 目标文件
+//   - in: 源文件
+//   - off: 区段起始偏移
+//   - n: 区段长度
+//
+// 返回:
+//   - error: 复制失败时返回错误
+func (c *copier) copyExtent(out, in *os.File, off, n int64) error {
+	if _, err := in.Seek(off, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := out.Seek(off, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := c.copyRange(out, in, n)
+	return err
+}
+
+// copyRange 从两个文件的当前偏移开始复制 n 个字节，优先使用内核复制，不可用时回退到缓冲区复制
+//
+// 参数:
+//   - out: 目标文件
+//   - in: 源文件
+//   - n: 需要复制的字节数
+//
+// 返回:
+//   - CopyMethod: 实际使用的复制方式
+//   - error: 复制失败时返回错误
+func (c *copier) copyRange(out, in *os.File, n int64) (CopyMethod, error) {
+	if c.opts.Reflink != ReflinkDisable {
+		// 内核复制与缓冲区复制共享文件偏移，中途不可用时从当前位置继续
+		copied, err := c.copyFileRange(out, in, n)
+		if err == nil {
+			return CopyKernel, nil
+		}
+		if !errors.Is(err, errFastCopyUnsupported) {
+			return CopyNone, err
+		}
+		n -= copied
+	}
+
+	buf := pool.GetByteCap(pool.CalculateBufferSize(n))
+	defer pool.PutByte(buf)
+
+	// 隐藏目标文件的 ReadFrom，避免标准库再次尝试内核复制
+	if _, err := io.CopyBuffer(struct{ io.Writer }{out}, c.wrapReader(io.LimitReader(in, n)), buf); err != nil {
+		return CopyNone, err
+	}
+	return CopyBuffer, nil
+}
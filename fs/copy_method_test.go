@@ -0,0 +1,104 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCopyMethod(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.bin")
+	content := strings.Repeat("0123456789", 100_000)
+	if err := os.WriteFile(src, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	empty := filepath.Join(dir, "empty.txt")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// copyWith 复制文件并返回进度中报告的复制方式
+	copyWith := func(t *testing.T, src string, mode ReflinkMode) (CopyMethod, error) {
+		t.Helper()
+		var last CopyProgress
+		dst := filepath.Join(t.TempDir(), filepath.Base(src))
+		err := CopyWithOptions(src, dst, &CopyOptions{
+			Reflink:  mode,
+			Progress: func(p CopyProgress) { last = p },
+		})
+		if err == nil {
+			validateFileCopy(t, src, dst)
+			if last.BytesDone != last.BytesTotal {
+				t.Errorf("Bytes = %d/%d", last.BytesDone, last.BytesTotal)
+			}
+		}
+		return last.Method, err
+	}
+
+	t.Run("Prefer", func(t *testing.T) {
+		method, err := copyWith(t, src, ReflinkPrefer)
+		if err != nil {
+			t.Fatalf("Copy failed: %v", err)
+		}
+		want := []CopyMethod{CopyBuffer}
+		if runtime.GOOS == "linux" {
+			want = []CopyMethod{CopyReflink, CopyKernel, CopyBuffer}
+		}
+		if !slices.Contains(want, method) {
+			t.Errorf("Method = %v, want one of %v", method, want)
+		}
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		method, err := copyWith(t, src, ReflinkDisable)
+		if err != nil {
+			t.Fatalf("Copy failed: %v", err)
+		}
+		if method != CopyBuffer {
+			t.Errorf("Method = %v, want %v", method, CopyBuffer)
+		}
+	})
+
+	t.Run("Require", func(t *testing.T) {
+		method, err := copyWith(t, src, ReflinkRequire)
+		if err != nil {
+			if !errors.Is(err, ErrReflinkUnsupported) {
+				t.Fatalf("Expected ErrReflinkUnsupported, got %v", err)
+			}
+			return
+		}
+		if method != CopyReflink {
+			t.Errorf("Method = %v, want %v", method, CopyReflink)
+		}
+	})
+
+	t.Run("Empty file", func(t *testing.T) {
+		method, err := copyWith(t, empty, ReflinkRequire)
+		if err != nil {
+			t.Fatalf("Copy failed: %v", err)
+		}
+		if method != CopyNone {
+			t.Errorf("Method = %v, want %v", method, CopyNone)
+		}
+	})
+}
+
+func TestCopyMethodString(t *testing.T) {
+	tests := map[CopyMethod]string{
+		CopyNone:       "none",
+		CopyReflink:    "reflink",
+		CopyKernel:     "copy_file_range",
+		CopyBuffer:     "buffer",
+		CopyMethod(42): "CopyMethod(42)",
+	}
+	for method, want := range tests {
+		if got := method.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
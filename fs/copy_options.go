@@ -0,0 +1,235 @@
+package fs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CopyOptions 复制选项
+// 零值等价于 CopyEx(src, dst, false) 的行为
+type CopyOptions struct {
+	// Overwrite 是否允许覆盖已存在的目标文件/目录
+	Overwrite bool
+
+	// PreserveTimes 保留访问时间和修改时间（目录在其内容复制完成后再设置）
+	PreserveTimes bool
+
+	// PreserveOwner 保留所有者和所属组（仅 Linux/macOS），非 root 用户无权修改时忽略
+	PreserveOwner bool
+
+	// PreserveXattrs 保留扩展属性（仅 Linux/macOS），目标文件系统不支持或无权设置时忽略
+	PreserveXattrs bool
+
+	// PreserveHardlinks 目录复制时保留硬链接（仅 Unix）
+	// 同一 inode 的多个路径只复制一次，其余路径在目标中创建为硬链接
+	PreserveHardlinks bool
+
+	// Include 文件包含模式，非空时只复制匹配的文件（目录始终遍历并在目标中创建，没有匹配文件的目录成为空目录）
+	// 模式语法见 Match；不含 / 的模式匹配文件名，含 / 的模式匹配相对源目录的完整路径（使用 / 分隔，如 vendor/**）
+	Include []string
+
+	// Exclude 排除模式，匹配规则同 Include，匹配的目录整体跳过，不再遍历其内容
+	Exclude []string
+
+	// Ignore gitignore 语义的忽略规则，被忽略的条目跳过（目录整体跳过）
+	// 匹配器根目录以外的条目不受影响
+	Ignore *IgnoreMatcher
+
+	// Filter 自定义过滤函数，返回 false 时跳过该条目（目录整体跳过）
+	Filter FilterFunc
+
+	// MaxDepth 最大复制深度，1 表示只复制源目录的直接子项，0 表示不限制
+	MaxDepth int
+
+	// MinSize/MaxSize 普通文件的大小限制（字节），0 表示不限制
+	MinSize int64
+	MaxSize int64
+
+	// NewerThan/OlderThan 普通文件的修改时间限制，零值表示不限制
+	NewerThan time.Time
+	OlderThan time.Time
+
+	// Reflink reflink（写时复制克隆）策略，默认优先使用，不支持时自动回退
+	// 仅 Linux 支持 reflink（btrfs/XFS 等），其他平台上 ReflinkRequire 返回 ErrReflinkUnsupported
+	Reflink ReflinkMode
+
+	// Workers 目录复制的并行工作协程数，大于 1 时并行复制文件，否则逐个复制
+	// 适合大量小文件或网络文件系统，目录仍按顺序先于其子项创建
+	Workers int
+
+	// Progress 进度回调，在开始复制文件、写入数据和完成文件时调用
+	// 回调同步执行且不会并发调用（并行复制时由各工作协程串行调用），应尽快返回
+	Progress ProgressFunc
+}
+
+// preservedModeBits 复制时需要保留的权限位
+const preservedModeBits = os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+
+// ArchiveOptions 返回归档式复制选项，行为与 Unix 上的 cp -a 一致
+// 保留时间、所有者、扩展属性、硬链接和完整的权限位（包括 setuid/setgid/sticky）
+//
+// 返回:
+//   - *CopyOptions: 归档式复制选项，可在此基础上继续修改
+func ArchiveOptions() *CopyOptions {
+	return &CopyOptions{
+		PreserveTimes:     true,
+		PreserveOwner:     true,
+		PreserveXattrs:    true,
+		PreserveHardlinks: true,
+	}
+}
+
+// preserves 是否需要保留任何元数据
+func (o *CopyOptions) preserves() bool {
+	return o.PreserveTimes || o.PreserveOwner || o.PreserveXattrs
+}
+
+// copier 单次复制操作的内部状态
+type copier struct {
+	opts     *CopyOptions    // 复制选项
+	ctx      context.Context // 用于取消复制
+	progress CopyProgress    // 当前进度（仅 opts.Progress 非 nil 时维护）
+	mu       sync.Mutex      // 保护 progress 和 copiedFiles（并行复制时）
+
+	track       bool     // 是否记录已复制的源路径（带过滤条件的移动使用）
+	copiedFiles []string // 已复制的源文件（不含目录）
+	copiedDirs  []string // 已复制的源目录
+}
+
+// newCopier 创建复制器，opts 为 nil 时使用默认选项
+//
+// 参数:
+//   - ctx: 用于取消复制的上下文
+//   - opts: 复制选项
+//
+// 返回:
+//   - *copier: 复制器
+func newCopier(ctx context.Context, opts *CopyOptions) *copier {
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+	return &copier{opts: opts, ctx: ctx}
+}
+
+// CopyWithOptions 按选项复制文件或目录
+// 路径处理规则与 CopyEx 相同，额外支持保留时间、所有者和扩展属性等元数据
+//
+// 参数:
+//   - src: 源路径 (支持文件、目录、符号链接、特殊文件)
+//   - dst: 目标路径（支持文件、目录，自动创建父目录）
+//   - opts: 复制选项，为 nil 时等价于 Copy(src, dst)
+//
+// 返回:
+//   - error: 复制失败时返回错误
+//
+// 示例:
+//
+//	// 类似 cp -a，保留所有元数据
+//	err := fs.CopyWithOptions("data", "backup/data", fs.ArchiveOptions())
+//
+//	// 只保留修改时间，允许覆盖
+//	err := fs.CopyWithOptions("a.txt", "b.txt", &fs.CopyOptions{Overwrite: true, PreserveTimes: true})
+func CopyWithOptions(src, dst string, opts *CopyOptions) error {
+	return CopyContext(context.Background(), src, dst, opts)
+}
+
+// CopyContext 按选项复制文件或目录，支持通过 ctx 取消
+// 取消时正在复制的文件和已复制的内容会被清理，被覆盖的目标从备份中恢复
+//
+// 参数:
+//   - ctx: 上下文，取消后复制在下一个文件或数据块处停止
+//   - src: 源路径 (支持文件、目录、符号链接、特殊文件)
+//   - dst: 目标路径（支持文件、目录，自动创建父目录）
+//   - opts: 复制选项，为 nil 时使用默认选项
+//
+// 返回:
+//   - error: 复制失败时返回错误，取消时返回的错误包装了 ctx.Err()
+//
+// 示例:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//	defer cancel()
+//	err := fs.CopyContext(ctx, "images", "/backup/images", &fs.CopyOptions{
+//	    Progress: func(p fs.CopyProgress) {
+//	        fmt.Printf("\r%d/%d files, %d/%d bytes", p.FilesDone, p.FilesTotal, p.BytesDone, p.BytesTotal)
+//	    },
+//	})
+func CopyContext(ctx context.Context, src, dst string, opts *CopyOptions) (err error) {
+	// 捕获 panic 并转换为错误
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("copy operation panicked: %v", r)
+		}
+	}()
+
+	// 验证路径并获取绝对路径
+	srcAbs, dstAbs, err := validateAndResolvePaths(src, dst)
+	if err != nil {
+		return err
+	}
+
+	// 验证路径之间的关系（检查路径不相同）
+	if err := validatePathRelations(srcAbs, dstAbs, false); err != nil {
+		return err
+	}
+
+	// 智能路径处理：如果目标是已存在的目录，自动追加源文件名/目录名
+	dstAbs = resolveDestinationPathAbs(srcAbs, dstAbs)
+
+	c := newCopier(ctx, opts)
+	if err := c.opts.validate(); err != nil {
+		return err
+	}
+	if err := c.scanTotals(srcAbs); err != nil {
+		return err
+	}
+	return c.copy(srcAbs, dstAbs)
+}
+
+// preserveMetadata 按选项将源路径的元数据应用到目标路径
+// 顺序为：所有者 → 权限 → 扩展属性 → 时间，修改所有者会清除 setuid/setgid 位，
+// 因此需要在其后重新设置权限；时间最后设置，避免被其他操作修改
+//
+// 参数:
+//   - srcPath: 源路径
+//   - dstPath: 目标路径
+//   - srcInfo: 源路径信息（Lstat 结果）
+//
+// 返回:
+//   - error: 设置元数据失败时返回错误
+func (c *copier) preserveMetadata(srcPath, dstPath string, srcInfo os.FileInfo) error {
+	if !c.opts.preserves() {
+		return nil
+	}
+	isSymlink := srcInfo.Mode()&os.ModeSymlink != 0
+
+	if c.opts.PreserveOwner {
+		if err := lchown(dstPath, srcInfo); err != nil {
+			return fmt.Errorf("failed to preserve owner of '%s': %w", dstPath, err)
+		}
+	}
+
+	// 符号链接的权限没有意义，其他类型恢复完整权限位（创建时受 umask 影响）
+	if !isSymlink {
+		if err := os.Chmod(dstPath, srcInfo.Mode()&preservedModeBits); err != nil {
+			return fmt.Errorf("failed to preserve mode of '%s': %w", dstPath, err)
+		}
+	}
+
+	if c.opts.PreserveXattrs {
+		if err := copyXattrs(srcPath, dstPath); err != nil {
+			return fmt.Errorf("failed to preserve extended attributes of '%s': %w", dstPath, err)
+		}
+	}
+
+	if c.opts.PreserveTimes {
+		if err := setFileTimes(dstPath, srcInfo); err != nil {
+			return fmt.Errorf("failed to preserve times of '%s': %w", dstPath, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// setupMetadataTree 创建带有固定修改时间和权限的目录树
+func setupMetadataTree(t *testing.T, mtime time.Time) string {
+	t.Helper()
+
+	src := filepath.Join(t.TempDir(), "src")
+	files := map[string]string{
+		"a.txt":         "alpha",
+		"sub/b.txt":     "bravo",
+		"sub/deep/c.sh": "#!/bin/sh\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(src, "sub/deep/c.sh"), 0o750); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+
+	// 目录时间最后设置，避免被创建文件修改
+	for _, name := range []string{"a.txt", "sub/b.txt", "sub/deep/c.sh", "sub/deep", "sub", "."} {
+		if err := os.Chtimes(filepath.Join(src, name), mtime, mtime); err != nil {
+			t.Fatalf("Failed to set times: %v", err)
+		}
+	}
+	return src
+}
+
+func TestCopyWithOptions(t *testing.T) {
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("Preserve times", func(t *testing.T) {
+		src := setupMetadataTree(t, mtime)
+		dst := filepath.Join(t.TempDir(), "dst")
+
+		if err := CopyWithOptions(src, dst, &CopyOptions{PreserveTimes: true}); err != nil {
+			t.Fatalf("CopyWithOptions failed: %v", err)
+		}
+		for _, name := range []string{".", "a.txt", "sub", "sub/deep", "sub/deep/c.sh"} {
+			info, err := os.Stat(filepath.Join(dst, name))
+			if err != nil {
+				t.Fatalf("Stat %s failed: %v", name, err)
+			}
+			if !info.ModTime().Equal(mtime) {
+				t.Errorf("%s mtime = %v, want %v", name, info.ModTime(), mtime)
+			}
+		}
+	})
+
+	t.Run("Archive mode bits", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Unix permission bits are not supported on Windows")
+		}
+		src := setupMetadataTree(t, mtime)
+		dst := filepath.Join(t.TempDir(), "dst")
+
+		if err := CopyWithOptions(src, dst, ArchiveOptions()); err != nil {
+			t.Fatalf("CopyWithOptions failed: %v", err)
+		}
+		info, err := os.Stat(filepath.Join(dst, "sub/deep/c.sh"))
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		if info.Mode().Perm() != 0o750 {
+			t.Errorf("Mode = %v, want %v", info.Mode().Perm(), os.FileMode(0o750))
+		}
+	})
+
+	t.Run("Default options", func(t *testing.T) {
+		src := setupMetadataTree(t, mtime)
+		dst := filepath.Join(t.TempDir(), "dst")
+
+		if err := CopyWithOptions(src, dst, nil); err != nil {
+			t.Fatalf("CopyWithOptions failed: %v", err)
+		}
+		info, err := os.Stat(filepath.Join(dst, "a.txt"))
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		if info.ModTime().Equal(mtime) {
+			t.Error("Default options should not preserve mtime")
+		}
+		srcFile := filepath.Join(src, "a.txt")
+		dstFile := filepath.Join(dst, "a.txt")
+		if err := CopyWithOptions(srcFile, dstFile, nil); err == nil {
+			t.Error("Expected error when destination exists without Overwrite")
+		}
+		if err := CopyWithOptions(srcFile, dstFile, &CopyOptions{Overwrite: true}); err != nil {
+			t.Errorf("Overwrite failed: %v", err)
+		}
+	})
+
+	t.Run("Read-only directory", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Unix permission bits are not supported on Windows")
+		}
+		src := setupMetadataTree(t, mtime)
+		if err := os.Chmod(filepath.Join(src, "sub"), 0o555); err != nil {
+			t.Fatalf("Failed to chmod: %v", err)
+		}
+		t.Cleanup(func() { _ = os.Chmod(filepath.Join(src, "sub"), 0o755) })
+		dst := filepath.Join(t.TempDir(), "dst")
+
+		if err := CopyWithOptions(src, dst, ArchiveOptions()); err != nil {
+			t.Fatalf("CopyWithOptions failed: %v", err)
+		}
+		t.Cleanup(func() { _ = os.Chmod(filepath.Join(dst, "sub"), 0o755) })
+
+		info, err := os.Stat(filepath.Join(dst, "sub"))
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		if info.Mode().Perm() != 0o555 {
+			t.Errorf("Mode = %v, want %v", info.Mode().Perm(), os.FileMode(0o555))
+		}
+		if !info.ModTime().Equal(mtime) {
+			t.Errorf("mtime = %v, want %v", info.ModTime(), mtime)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package fs
+
+import "os"
+
+// reflinkFile 当前平台不支持 FICLONE
+func reflinkFile(out, in *os.File) error {
+	return ErrReflinkUnsupported
+}
+
+// copyFileRange 当前平台不支持 copy_file_range，直接回退到缓冲区复制
+func (c *copier) copyFileRange(out, in *os.File, size int64) (int64, error) {
+	return 0, errFastCopyUnsupported
+}
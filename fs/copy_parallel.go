@@ -0,0 +1,88 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+// errCopyAborted 表示并行复制中已有文件失败，停止继续分发（仅内部使用）
+var errCopyAborted = errors.New("copy aborted")
+
+// copyJob 并行复制的单个文件任务
+type copyJob struct {
+	src  string      // 源路径
+	dst  string      // 目标路径
+	info os.FileInfo // 源文件信息
+}
+
+// copyQueue 目录并行复制的工作队列
+// 目录由遍历协程按顺序创建，文件交给工作协程复制，因此目录总是先于其子项存在
+type copyQueue struct {
+	c    *copier
+	jobs chan copyJob
+	wg   sync.WaitGroup
+
+	mu   sync.Mutex
+	errs []error // 各文件的复制错误
+}
+
+// newCopyQueue 创建并启动工作队列
+//
+// 参数:
+//   - workers: 工作协程数
+//
+// 返回:
+//   - *copyQueue: 工作队列
+func (c *copier) newCopyQueue(workers int) *copyQueue {
+	q := &copyQueue{c: c, jobs: make(chan copyJob, workers*2)}
+	q.wg.Add(workers)
+	for range workers {
+		go q.work()
+	}
+	return q
+}
+
+// work 工作协程主循环，出现错误或已取消后只消费剩余任务，不再复制
+func (q *copyQueue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		if q.failed() || q.c.ctx.Err() != nil {
+			continue
+		}
+		if err := q.c.copyFileRouter(job.src, job.dst, job.info); err != nil {
+			q.mu.Lock()
+			q.errs = append(q.errs, err)
+			q.mu.Unlock()
+		}
+	}
+}
+
+// failed 是否已有文件复制失败
+func (q *copyQueue) failed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.errs) > 0
+}
+
+// submit 提交文件复制任务，队列满时阻塞
+//
+// 返回:
+//   - error: 已有文件复制失败时返回 errCopyAborted，用于停止遍历
+func (q *copyQueue) submit(src, dst string, info os.FileInfo) error {
+	if q.failed() {
+		return errCopyAborted
+	}
+	q.jobs <- copyJob{src: src, dst: dst, info: info}
+	return nil
+}
+
+// wait 关闭队列并等待所有任务结束
+//
+// 返回:
+//   - error: 所有文件复制错误的合并结果（errors.Join），全部成功时为 nil
+func (q *copyQueue) wait() error {
+	close(q.jobs)
+	q.wg.Wait()
+	return errors.Join(q.errs...)
+}
@@ -0,0 +1,132 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// setupManyFilesDir 创建包含大量小文件的多层目录
+func setupManyFilesDir(t *testing.T) string {
+	t.Helper()
+
+	root := filepath.Join(t.TempDir(), "many")
+	for d := range 10 {
+		dir := filepath.Join(root, fmt.Sprintf("d%d", d), "sub")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		for f := range 20 {
+			name := filepath.Join(dir, fmt.Sprintf("f%d.txt", f))
+			if err := os.WriteFile(name, []byte(strings.Repeat("x", d*100+f)), 0o644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+		}
+	}
+	return root
+}
+
+func TestCopyParallel(t *testing.T) {
+	src := setupManyFilesDir(t)
+	dst := filepath.Join(t.TempDir(), "dst")
+
+	var last CopyProgress
+	err := CopyWithOptions(src, dst, &CopyOptions{
+		Workers:       8,
+		PreserveTimes: true,
+		Progress:      func(p CopyProgress) { last = p },
+	})
+	if err != nil {
+		t.Fatalf("CopyWithOptions failed: %v", err)
+	}
+
+	srcFiles, dstFiles := listFiles(t, src), listFiles(t, dst)
+	if !slices.Equal(srcFiles, dstFiles) {
+		t.Fatalf("Copied files = %d, want %d", len(dstFiles), len(srcFiles))
+	}
+	for _, rel := range srcFiles {
+		validateFileCopy(t, filepath.Join(src, rel), filepath.Join(dst, rel))
+	}
+
+	wantBytes, err := GetSize(src)
+	if err != nil {
+		t.Fatalf("GetSize failed: %v", err)
+	}
+	if last.FilesDone != 200 || last.FilesTotal != 200 || last.BytesDone != wantBytes {
+		t.Errorf("Progress = %+v, want 200 files and %d bytes", last, wantBytes)
+	}
+
+	// 目录时间在所有文件写入完成后设置
+	srcInfo, _ := os.Stat(filepath.Join(src, "d3", "sub"))
+	dstInfo, _ := os.Stat(filepath.Join(dst, "d3", "sub"))
+	if !srcInfo.ModTime().Equal(dstInfo.ModTime()) {
+		t.Errorf("Directory mtime = %v, want %v", dstInfo.ModTime(), srcInfo.ModTime())
+	}
+}
+
+func TestCopyParallelFailure(t *testing.T) {
+	src := setupManyFilesDir(t)
+	dst := filepath.Join(t.TempDir(), "dst")
+
+	// 在目标位置预先放置一个同名目录，使该文件的复制失败
+	blocked := filepath.Join("d5", "sub", "f7.txt")
+	err := CopyWithOptions(src, dst, &CopyOptions{
+		Workers: 4,
+		Filter: func(path string, d fs.DirEntry) bool {
+			if strings.HasSuffix(path, blocked) {
+				_ = os.MkdirAll(filepath.Join(dst, blocked), 0o755)
+			}
+			return true
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("Expected already exists error, got %v", err)
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Errorf("Destination was not cleaned up: %v", err)
+	}
+}
+
+func TestCopyParallelCancel(t *testing.T) {
+	src := setupManyFilesDir(t)
+	dst := filepath.Join(t.TempDir(), "dst")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := CopyContext(ctx, src, dst, &CopyOptions{
+		Workers: 4,
+		Progress: func(p CopyProgress) {
+			if p.FilesDone == 20 {
+				cancel()
+			}
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Errorf("Destination was not cleaned up: %v", err)
+	}
+}
+
+func TestMoveContextParallel(t *testing.T) {
+	src := setupManyFilesDir(t)
+	dst := filepath.Join(t.TempDir(), "dst")
+
+	err := MoveContext(context.Background(), src, dst, &CopyOptions{Workers: 4, Exclude: []string{"f1*.txt"}}) // f1、f10-f19
+	if err != nil {
+		t.Fatalf("MoveContext failed: %v", err)
+	}
+	if got := len(listFiles(t, dst)); got != 90 {
+		t.Errorf("Moved files = %d, want 90", got)
+	}
+	if got := len(listFiles(t, src)); got != 110 {
+		t.Errorf("Remaining source files = %d, want 110", got)
+	}
+}
@@ -0,0 +1,352 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"gitee.com/MM-Q/go-kit/utils"
+)
+
+// DiskUsageOptions 磁盘用量分析选项
+type DiskUsageOptions struct {
+	// Include/Exclude/Filter 过滤规则与 CopyOptions 相同，相对路径以根目录为基准
+	// 被排除的目录整体不计入；Include 只作用于文件，没有匹配文件的目录仍计入 Dirs
+	Include []string
+	Exclude []string
+	Filter  FilterFunc
+
+	// Ignore gitignore 语义的忽略规则，被忽略的条目不计入
+	Ignore *IgnoreMatcher
+
+	// OneFileSystem 不进入位于其他文件系统上的目录（类似 du -x），仅 Unix 上有效
+	OneFileSystem bool
+
+	// CountLinks 硬链接的每个路径都计入大小（类似 du -l），默认每个 inode 只计算一次
+	CountLinks bool
+
+	// Workers 并发遍历目录的协程数，<= 0 时使用 runtime.NumCPU()
+	Workers int
+
+	// TopFiles 记录占用空间最大的文件数量，结果保存在根节点的 LargestFiles 中
+	TopFiles int
+}
+
+// DiskUsage 目录的磁盘用量，大小和数量均包含全部子目录
+type DiskUsage struct {
+	Path      string // 目录路径（绝对路径）
+	Size      int64  // 表观大小：文件长度之和，包括目录本身
+	Allocated int64  // 实际占用的磁盘空间（st_blocks*512，稀疏文件小于表观大小；Windows 上等于表观大小）
+	Files     int64  // 文件数量（非目录条目，包括符号链接等特殊文件）
+	Dirs      int64  // 子目录数量（不包括自身）
+
+	Children     []*DiskUsage // 子目录，按名称排序
+	LargestFiles []FileUsage  // 占用空间最大的文件，按占用空间从大到小排序（仅根节点，见 DiskUsageOptions.TopFiles）
+}
+
+// FileUsage 单个文件的磁盘用量
+type FileUsage struct {
+	Path      string // 文件路径（绝对路径）
+	Size      int64  // 表观大小
+	Allocated int64  // 实际占用的磁盘空间
+}
+
+// String 返回 "占用空间  路径" 格式的文本
+func (u *DiskUsage) String() string {
+	return fmt.Sprintf("%10s  %s", utils.FormatBytes(u.Allocated), u.Path)
+}
+
+// String 返回 "占用空间  路径" 格式的文本
+func (f FileUsage) String() string {
+	return fmt.Sprintf("%10s  %s", utils.FormatBytes(f.Allocated), f.Path)
+}
+
+// AnalyzeDiskUsage 分析目录的磁盘用量，类似 du
+// 返回以目录为节点的树，每个节点包含表观大小、实际占用空间和文件数量
+//
+// 参数:
+//   - root: 需要分析的目录（符号链接会被跟随，目录中的符号链接不跟随）
+//   - opts: 分析选项，为 nil 时使用默认选项
+//
+// 返回:
+//   - *DiskUsage: 根目录的用量
+//   - error: 根目录不可访问时返回错误；个别目录无法读取时不中止分析，返回已统计的结果和合并后的错误（errors.Join）
+func AnalyzeDiskUsage(root string, opts *DiskUsageOptions) (*DiskUsage, error) {
+	return AnalyzeDiskUsageContext(context.Background(), root, opts)
+}
+
+// AnalyzeDiskUsageContext 分析目录的磁盘用量，支持通过 ctx 取消
+//
+// 参数:
+//   - ctx: 上下文，取消后所有遍历协程在下一个条目处停止
+//   - root: 需要分析的目录
+//   - opts: 分析选项，为 nil 时使用默认选项
+//
+// 返回:
+//   - *DiskUsage: 根目录的用量，取消时为 nil
+//   - error: 同 AnalyzeDiskUsage，取消时返回 ctx.Err()
+//
+// 示例:
+//
+//	usage, err := fs.AnalyzeDiskUsage("/var", &fs.DiskUsageOptions{OneFileSystem: true, TopFiles: 10})
+//	if usage == nil {
+//	    return err
+//	}
+//	if err != nil {
+//	    log.Println(err) // 部分目录无法读取
+//	}
+//	fmt.Print(usage.Report(10))
+func AnalyzeDiskUsageContext(ctx context.Context, root string, opts *DiskUsageOptions) (*DiskUsage, error) {
+	if opts == nil {
+		opts = &DiskUsageOptions{}
+	}
+	c := newCopier(ctx, &CopyOptions{
+		Include: opts.Include,
+		Exclude: opts.Exclude,
+		Filter:  opts.Filter,
+		Ignore:  opts.Ignore,
+	})
+	if err := c.opts.validate(); err != nil {
+		return nil, err
+	}
+
+	rootAbs, err := filepath.Abs(filepath.Clean(root))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for '%s': %w", root, err)
+	}
+	info, err := os.Stat(rootAbs)
+	if err != nil {
+		return nil, wrapPathError(err, rootAbs, "accessing")
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path '%s' is not a directory", rootAbs)
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	a := &duAnalyzer{
+		c:    c,
+		opts: opts,
+		root: rootAbs,
+		sem:  make(chan struct{}, workers-1),
+		seen: make(map[inodeKey]bool),
+	}
+	if key, _, ok := fileInode(info); ok {
+		a.dev = key.dev
+	}
+
+	usage := &DiskUsage{Path: rootAbs}
+	a.walkDir(usage, info)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	usage.LargestFiles = a.top
+	return usage, errors.Join(a.errs...)
+}
+
+// duAnalyzer 一次磁盘用量分析的状态
+type duAnalyzer struct {
+	c    *copier
+	opts *DiskUsageOptions
+	root string
+	dev  uint64        // 根目录所在的设备
+	sem  chan struct{} // 额外遍历协程的令牌，没有空闲令牌时在当前协程中遍历子目录
+
+	mu   sync.Mutex
+	seen map[inodeKey]bool // 已计算过的多链接文件
+	top  []FileUsage       // 占用空间最大的文件，按 Allocated 从大到小排序
+	errs []error
+}
+
+// walkDir 统计目录的用量，子目录在空闲的协程中并发统计，全部完成后汇总到 node
+//
+// 参数:
+//   - node: 目录节点（已设置 Path）
+//   - info: 目录信息
+func (a *duAnalyzer) walkDir(node *DiskUsage, info os.FileInfo) {
+	node.Size = info.Size()
+	node.Allocated = allocatedSize(info)
+
+	entries, err := os.ReadDir(node.Path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			a.fail(fmt.Errorf("failed to read directory '%s': %w", node.Path, err))
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, entry := range entries {
+		if a.c.ctx.Err() != nil {
+			break
+		}
+		path := filepath.Join(node.Path, entry.Name())
+		rel, err := filepath.Rel(a.root, path)
+		if err != nil {
+			a.fail(fmt.Errorf("failed to get relative path for '%s': %w", path, err))
+			continue
+		}
+		if err := a.c.filterEntry(path, rel, entry); err != nil {
+			if !errors.Is(err, errSkipEntry) && !errors.Is(err, filepath.SkipDir) {
+				a.fail(err)
+			}
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			// 遍历过程中被删除的条目忽略
+			if !os.IsNotExist(err) {
+				a.fail(fmt.Errorf("failed to get file info '%s': %w", path, err))
+			}
+			continue
+		}
+
+		if !entry.IsDir() {
+			a.addFile(node, path, info)
+			continue
+		}
+		if a.opts.OneFileSystem {
+			if key, _, ok := fileInode(info); ok && key.dev != a.dev {
+				continue
+			}
+		}
+		child := &DiskUsage{Path: path}
+		node.Children = append(node.Children, child)
+		select {
+		case a.sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-a.sem }()
+				a.walkDir(child, info)
+			}()
+		default:
+			a.walkDir(child, info)
+		}
+	}
+	wg.Wait()
+
+	for _, child := range node.Children {
+		node.Size += child.Size
+		node.Allocated += child.Allocated
+		node.Files += child.Files
+		node.Dirs += child.Dirs + 1
+	}
+}
+
+// addFile 将文件计入目录节点，多链接文件默认只计算首次遇到的路径
+func (a *duAnalyzer) addFile(node *DiskUsage, path string, info os.FileInfo) {
+	if !a.opts.CountLinks {
+		if key, nlink, ok := fileInode(info); ok && nlink > 1 {
+			a.mu.Lock()
+			seen := a.seen[key]
+			a.seen[key] = true
+			a.mu.Unlock()
+			if seen {
+				return
+			}
+		}
+	}
+
+	f := FileUsage{Path: path, Size: info.Size(), Allocated: allocatedSize(info)}
+	node.Size += f.Size
+	node.Allocated += f.Allocated
+	node.Files++
+
+	if a.opts.TopFiles <= 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	i := sort.Search(len(a.top), func(i int) bool { return usageLess(f.Allocated, f.Path, a.top[i].Allocated, a.top[i].Path) })
+	if i >= a.opts.TopFiles {
+		return
+	}
+	if len(a.top) < a.opts.TopFiles {
+		a.top = append(a.top, FileUsage{})
+	}
+	copy(a.top[i+1:], a.top[i:])
+	a.top[i] = f
+}
+
+// fail 记录遍历中的错误
+func (a *duAnalyzer) fail(err error) {
+	a.mu.Lock()
+	a.errs = append(a.errs, err)
+	a.mu.Unlock()
+}
+
+// usageLess 占用空间从大到小排序，相同时按路径排序
+func usageLess(allocA int64, pathA string, allocB int64, pathB string) bool {
+	if allocA != allocB {
+		return allocA > allocB
+	}
+	return pathA < pathB
+}
+
+// TopDirs 返回占用空间最大的 n 个子目录（不包括自身，包括所有层级）
+//
+// 参数:
+//   - n: 返回的数量，<= 0 时返回全部
+//
+// 返回:
+//   - []*DiskUsage: 子目录，按占用空间从大到小排序，相同时按路径排序
+func (u *DiskUsage) TopDirs(n int) []*DiskUsage {
+	var dirs []*DiskUsage
+	var collect func(*DiskUsage)
+	collect = func(d *DiskUsage) {
+		for _, child := range d.Children {
+			dirs = append(dirs, child)
+			collect(child)
+		}
+	}
+	collect(u)
+
+	sort.Slice(dirs, func(i, j int) bool {
+		return usageLess(dirs[i].Allocated, dirs[i].Path, dirs[j].Allocated, dirs[j].Path)
+	})
+	if n > 0 && len(dirs) > n {
+		dirs = dirs[:n]
+	}
+	return dirs
+}
+
+// Report 返回文本格式的用量报告：总计、占用空间最大的 n 个目录和 LargestFiles 中的前 n 个文件
+//
+// 参数:
+//   - n: 每个列表的条目数，<= 0 时列出全部
+//
+// 返回:
+//   - string: 报告文本，大小使用 utils.FormatBytes 格式化
+func (u *DiskUsage) Report(n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (apparent %s, %d files, %d dirs)\n", u, utils.FormatBytes(u.Size), u.Files, u.Dirs)
+
+	if dirs := u.TopDirs(n); len(dirs) > 0 {
+		b.WriteString("\nLargest directories:\n")
+		for _, d := range dirs {
+			fmt.Fprintf(&b, "%s\n", d)
+		}
+	}
+
+	files := u.LargestFiles
+	if n > 0 && len(files) > n {
+		files = files[:n]
+	}
+	if len(files) > 0 {
+		b.WriteString("\nLargest files:\n")
+		for _, f := range files {
+			fmt.Fprintf(&b, "%s\n", f)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,198 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/go-kit/utils"
+)
+
+// expectedUsage 逐个 Lstat 计算目录的期望用量
+func expectedUsage(t *testing.T, root string) (size, allocated, files, dirs int64) {
+	t.Helper()
+
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		allocated += allocatedSize(info)
+		switch {
+		case path == root:
+		case entry.IsDir():
+			dirs++
+		default:
+			files++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return size, allocated, files, dirs
+}
+
+func TestAnalyzeDiskUsage(t *testing.T) {
+	root := t.TempDir()
+	writeSyncFile(t, filepath.Join(root, "a.txt"), strings.Repeat("a", 100))
+	writeSyncFile(t, filepath.Join(root, "big", "b.bin"), strings.Repeat("b", 64*1024))
+	writeSyncFile(t, filepath.Join(root, "big", "deep", "c.bin"), strings.Repeat("c", 32*1024))
+	writeSyncFile(t, filepath.Join(root, "small", "d.txt"), "d")
+	if err := os.Mkdir(filepath.Join(root, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{1, 4} {
+		usage, err := AnalyzeDiskUsage(root, &DiskUsageOptions{Workers: workers, TopFiles: 2})
+		if err != nil {
+			t.Fatalf("AnalyzeDiskUsage failed: %v", err)
+		}
+
+		size, allocated, files, dirs := expectedUsage(t, root)
+		if usage.Size != size || usage.Allocated != allocated || usage.Files != files || usage.Dirs != dirs {
+			t.Errorf("workers=%d: usage = {%d %d %d %d}, want {%d %d %d %d}", workers,
+				usage.Size, usage.Allocated, usage.Files, usage.Dirs, size, allocated, files, dirs)
+		}
+
+		var names []string
+		for _, child := range usage.Children {
+			names = append(names, filepath.Base(child.Path))
+		}
+		if got := strings.Join(names, ","); got != "big,empty,small" {
+			t.Errorf("children = %s, want big,empty,small", got)
+		}
+		big := usage.Children[0]
+		if size, _, files, dirs := expectedUsage(t, big.Path); big.Size != size || big.Files != files || big.Dirs != dirs {
+			t.Errorf("big = {%d %d %d}, want {%d %d %d}", big.Size, big.Files, big.Dirs, size, files, dirs)
+		}
+
+		if len(usage.LargestFiles) != 2 ||
+			usage.LargestFiles[0].Path != filepath.Join(root, "big", "b.bin") ||
+			usage.LargestFiles[1].Path != filepath.Join(root, "big", "deep", "c.bin") {
+			t.Errorf("LargestFiles = %v", usage.LargestFiles)
+		}
+	}
+
+	if _, err := AnalyzeDiskUsage(filepath.Join(root, "a.txt"), nil); err == nil {
+		t.Error("AnalyzeDiskUsage of file: expected error")
+	}
+	if _, err := AnalyzeDiskUsage(filepath.Join(root, "missing"), nil); err == nil {
+		t.Error("AnalyzeDiskUsage of missing path: expected error")
+	}
+}
+
+func TestAnalyzeDiskUsageFilters(t *testing.T) {
+	root := t.TempDir()
+	writeSyncFile(t, filepath.Join(root, "keep.txt"), "keep")
+	writeSyncFile(t, filepath.Join(root, "skip.log"), "skip")
+	writeSyncFile(t, filepath.Join(root, "node_modules", "x.js"), "x")
+	writeSyncFile(t, filepath.Join(root, "build", "out.bin"), "out")
+
+	ignore, err := NewIgnoreMatcher(root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ignore.AddPatterns("build/"); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := AnalyzeDiskUsage(root, &DiskUsageOptions{
+		Exclude: []string{"node_modules", "*.log"},
+		Ignore:  ignore,
+	})
+	if err != nil {
+		t.Fatalf("AnalyzeDiskUsage failed: %v", err)
+	}
+	if usage.Files != 1 || usage.Dirs != 0 || len(usage.Children) != 0 {
+		t.Errorf("usage = %d files, %d dirs, children %v; want only keep.txt", usage.Files, usage.Dirs, usage.Children)
+	}
+
+	if _, err := AnalyzeDiskUsage(root, &DiskUsageOptions{Exclude: []string{"[z-"}}); err == nil {
+		t.Error("AnalyzeDiskUsage with bad pattern: expected error")
+	}
+}
+
+func TestAnalyzeDiskUsageHardlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hard link dedup requires inode numbers")
+	}
+	root := t.TempDir()
+	file := filepath.Join(root, "a", "data.bin")
+	writeSyncFile(t, file, strings.Repeat("x", 10000))
+	if err := os.MkdirAll(filepath.Join(root, "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(file, filepath.Join(root, "b", "link.bin")); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+
+	usage, err := AnalyzeDiskUsage(root, nil)
+	if err != nil {
+		t.Fatalf("AnalyzeDiskUsage failed: %v", err)
+	}
+	counted, err := AnalyzeDiskUsage(root, &DiskUsageOptions{CountLinks: true})
+	if err != nil {
+		t.Fatalf("AnalyzeDiskUsage failed: %v", err)
+	}
+	if usage.Files != 1 || counted.Files != 2 {
+		t.Errorf("Files = %d (dedup), %d (count links), want 1, 2", usage.Files, counted.Files)
+	}
+	if diff := counted.Size - usage.Size; diff != 10000 {
+		t.Errorf("size difference = %d, want 10000", diff)
+	}
+}
+
+func TestAnalyzeDiskUsageContext(t *testing.T) {
+	root := t.TempDir()
+	writeSyncFile(t, filepath.Join(root, "a.txt"), "a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := AnalyzeDiskUsageContext(ctx, root, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestDiskUsageTopDirs(t *testing.T) {
+	root := t.TempDir()
+	writeSyncFile(t, filepath.Join(root, "small", "s.txt"), "s")
+	writeSyncFile(t, filepath.Join(root, "large", "l.bin"), strings.Repeat("l", 256*1024))
+	writeSyncFile(t, filepath.Join(root, "large", "nested", "n.bin"), strings.Repeat("n", 128*1024))
+
+	usage, err := AnalyzeDiskUsage(root, &DiskUsageOptions{TopFiles: 5})
+	if err != nil {
+		t.Fatalf("AnalyzeDiskUsage failed: %v", err)
+	}
+
+	top := usage.TopDirs(2)
+	if len(top) != 2 || top[0].Path != filepath.Join(root, "large") || top[1].Path != filepath.Join(root, "large", "nested") {
+		t.Fatalf("TopDirs(2) = %v", top)
+	}
+	if all := usage.TopDirs(0); len(all) != 3 {
+		t.Errorf("TopDirs(0) returned %d dirs, want 3", len(all))
+	}
+
+	report := usage.Report(1)
+	for _, want := range []string{
+		utils.FormatBytes(usage.Allocated),
+		"Largest directories:\n" + top[0].String() + "\n\n",
+		"Largest files:\n" + usage.LargestFiles[0].String() + "\n",
+		"3 files, 3 dirs",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "s.txt") {
+		t.Errorf("report lists more than one file:\n%s", report)
+	}
+}
@@ -0,0 +1,371 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gitee.com/MM-Q/go-kit/hash"
+)
+
+// partialHashSize 快速哈希读取的文件头部和尾部字节数
+const partialHashSize = 4096
+
+// DuplicateOptions 重复文件查找选项
+type DuplicateOptions struct {
+	// Include/Exclude/Filter/MaxDepth 过滤规则与 CopyOptions 相同，相对路径以各自的根目录为基准
+	Include  []string
+	Exclude  []string
+	Filter   FilterFunc
+	MaxDepth int
+
+	// MinSize 只查找不小于该大小的文件，空文件始终被忽略
+	MinSize int64
+
+	// Algorithm 完整哈希使用的算法（md5、sha1、sha256、sha512），为空时使用 sha256
+	Algorithm string
+}
+
+// DuplicateGroup 一组内容相同的文件
+type DuplicateGroup struct {
+	Size  int64    // 单个文件的大小
+	Hash  string   // 文件内容的完整哈希
+	Paths []string // 内容相同的文件路径（已排序），每个 inode 只列出一个路径
+
+	algorithm string // 计算 Hash 使用的算法，执行操作前用于重新校验
+}
+
+// Wasted 返回重复文件占用的字节数（除保留的一个文件外）
+func (g DuplicateGroup) Wasted() int64 {
+	return g.Size * int64(len(g.Paths)-1)
+}
+
+// FindDuplicates 在一个或多个目录中查找内容相同的文件
+// 依次按文件大小、文件头尾的快速哈希、完整哈希分组，只有三者都相同的文件才被视为重复
+// 指向同一 inode 的硬链接不被视为重复，多个根目录重叠时同一文件只计算一次
+//
+// 参数:
+//   - roots: 需要查找的目录列表
+//   - opts: 查找选项，为 nil 时使用默认选项
+//
+// 返回:
+//   - []DuplicateGroup: 重复文件组，按浪费的空间从大到小排序
+//   - error: 查找失败时返回错误
+func FindDuplicates(roots []string, opts *DuplicateOptions) ([]DuplicateGroup, error) {
+	return FindDuplicatesContext(context.Background(), roots, opts)
+}
+
+// FindDuplicatesContext 在一个或多个目录中查找内容相同的文件，支持通过 ctx 取消
+//
+// 参数:
+//   - ctx: 上下文，取消后在下一个文件处停止
+//   - roots: 需要查找的目录列表
+//   - opts: 查找选项，为 nil 时使用默认选项
+//
+// 返回:
+//   - []DuplicateGroup: 重复文件组，按浪费的空间从大到小排序
+//   - error: 查找失败时返回错误，取消时返回 ctx.Err()
+//
+// 示例:
+//
+//	groups, err := fs.FindDuplicates([]string{"/data/photos", "/backup/photos"}, &fs.DuplicateOptions{MinSize: 1 << 20})
+//	for _, g := range groups {
+//	    fmt.Printf("%s x%d: %v\n", utils.FormatBytes(g.Wasted()), len(g.Paths), g.Paths)
+//	    // 保留 g.Paths[0]，其余替换为硬链接
+//	    if err := g.Link(); err != nil {
+//	        return err
+//	    }
+//	}
+func FindDuplicatesContext(ctx context.Context, roots []string, opts *DuplicateOptions) ([]DuplicateGroup, error) {
+	if opts == nil {
+		opts = &DuplicateOptions{}
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no directories to search")
+	}
+	algorithm := opts.Algorithm
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	if !hash.IsAlgorithmSupported(algorithm) {
+		return nil, fmt.Errorf("unsupported hash algorithm: %s", algorithm)
+	}
+
+	c := newCopier(ctx, &CopyOptions{
+		Include:  opts.Include,
+		Exclude:  opts.Exclude,
+		Filter:   opts.Filter,
+		MaxDepth: opts.MaxDepth,
+	})
+	if err := c.opts.validate(); err != nil {
+		return nil, err
+	}
+
+	// 第一轮：按大小分组
+	bySize := make(map[int64][]string)
+	seen := make(map[inodeKey]bool)
+	seenPaths := make(map[string]bool)
+	for _, root := range roots {
+		rootAbs, err := filepath.Abs(filepath.Clean(root))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get absolute path for '%s': %w", root, err)
+		}
+		err = filepath.WalkDir(rootAbs, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return fmt.Errorf("failed to access path '%s': %w", path, err)
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if path == rootAbs {
+				return nil
+			}
+			rel, err := filepath.Rel(rootAbs, path)
+			if err != nil {
+				return fmt.Errorf("failed to get relative path for '%s': %w", path, err)
+			}
+			if err := c.filterEntry(path, rel, entry); err != nil {
+				if errors.Is(err, errSkipEntry) {
+					return nil
+				}
+				return err
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return fmt.Errorf("failed to get file info '%s': %w", path, err)
+			}
+			if info.Size() == 0 || info.Size() < opts.MinSize {
+				return nil
+			}
+
+			// 硬链接和重叠的根目录只保留首次遇到的路径
+			if key, _, ok := fileInode(info); ok {
+				if seen[key] {
+					return nil
+				}
+				seen[key] = true
+			} else {
+				if seenPaths[path] {
+					return nil
+				}
+				seenPaths[path] = true
+			}
+			bySize[info.Size()] = append(bySize[info.Size()], path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var groups []DuplicateGroup
+	for size, paths := range bySize {
+		if len(paths) < 2 {
+			continue
+		}
+
+		// 第二轮：按文件头尾的快速哈希分组
+		byPartial, err := groupByHash(ctx, paths, func(path string) (string, error) {
+			return partialHash(path, size, algorithm)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for partial, candidates := range byPartial {
+			// 文件不超过快速哈希的读取范围时，快速哈希已覆盖全部内容
+			if size <= 2*partialHashSize {
+				groups = append(groups, newDuplicateGroup(size, partial, algorithm, candidates))
+				continue
+			}
+
+			// 第三轮：按完整哈希分组
+			byFull, err := groupByHash(ctx, candidates, func(path string) (string, error) {
+				return hash.Checksum(path, algorithm)
+			})
+			if err != nil {
+				return nil, err
+			}
+			for full, dups := range byFull {
+				groups = append(groups, newDuplicateGroup(size, full, algorithm, dups))
+			}
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if wi, wj := groups[i].Wasted(), groups[j].Wasted(); wi != wj {
+			return wi > wj
+		}
+		return groups[i].Paths[0] < groups[j].Paths[0]
+	})
+	return groups, nil
+}
+
+// newDuplicateGroup 创建重复文件组并对路径排序
+func newDuplicateGroup(size int64, sum, algorithm string, paths []string) DuplicateGroup {
+	sort.Strings(paths)
+	return DuplicateGroup{Size: size, Hash: sum, Paths: paths, algorithm: algorithm}
+}
+
+// groupByHash 按哈希值对文件分组，只返回包含两个及以上文件的组
+//
+// 参数:
+//   - ctx: 上下文
+//   - paths: 文件路径
+//   - sum: 计算单个文件哈希的函数
+//
+// 返回:
+//   - map[string][]string: 哈希值到文件路径的映射
+//   - error: 计算哈希失败时返回错误
+func groupByHash(ctx context.Context, paths []string, sum func(string) (string, error)) (map[string][]string, error) {
+	groups := make(map[string][]string)
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		s, err := sum(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash '%s': %w", path, err)
+		}
+		groups[s] = append(groups[s], path)
+	}
+	for s, group := range groups {
+		if len(group) < 2 {
+			delete(groups, s)
+		}
+	}
+	return groups, nil
+}
+
+// partialHash 计算文件头部和尾部各 partialHashSize 字节的哈希
+//
+// 参数:
+//   - path: 文件路径
+//   - size: 文件大小
+//   - algorithm: 哈希算法
+//
+// 返回:
+//   - string: 哈希值（文件不超过 2*partialHashSize 时为完整内容的哈希）
+//   - error: 读取失败时返回错误
+func partialHash(path string, size int64, algorithm string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	var r io.Reader = io.NewSectionReader(f, 0, size)
+	if size > 2*partialHashSize {
+		r = io.MultiReader(
+			io.NewSectionReader(f, 0, partialHashSize),
+			io.NewSectionReader(f, size-partialHashSize, partialHashSize),
+		)
+	}
+	return hash.HashReader(r, algorithm)
+}
+
+// Link 保留 Paths[0]，将其余文件替换为指向它的硬链接
+// 替换前重新校验每个文件的内容，扫描后被修改的文件保持不变并返回错误
+// 替换通过在同一目录创建临时链接再原子重命名完成，不会丢失文件
+//
+// 返回:
+//   - error: 校验或替换失败时返回错误（跨文件系统时创建硬链接失败）
+func (g DuplicateGroup) Link() error {
+	if len(g.Paths) < 2 {
+		return nil
+	}
+	keep := g.Paths[0]
+	keepInfo, err := g.verify(keep)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, path := range g.Paths[1:] {
+		info, err := g.verify(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// 已经是指向保留文件的硬链接（重复执行），rename 到同一 inode 不会生效
+		if os.SameFile(keepInfo, info) {
+			continue
+		}
+		tmp := path + ".tmp." + fmt.Sprintf("%d.%d", os.Getpid(), time.Now().UnixNano())
+		if err := os.Link(keep, tmp); err != nil {
+			errs = append(errs, fmt.Errorf("failed to link '%s' to '%s': %w", path, keep, err))
+			continue
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			_ = os.Remove(tmp)
+			errs = append(errs, fmt.Errorf("failed to replace '%s' with link: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Remove 保留 Paths[0]，删除其余文件
+// 删除前重新校验每个文件的内容，扫描后被修改的文件不会被删除
+//
+// 返回:
+//   - error: 校验或删除失败时返回错误
+func (g DuplicateGroup) Remove() error {
+	if len(g.Paths) < 2 {
+		return nil
+	}
+	if _, err := g.verify(g.Paths[0]); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, path := range g.Paths[1:] {
+		if _, err := g.verify(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove duplicate '%s': %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// verify 确认文件内容仍与组的哈希一致
+//
+// 参数:
+//   - path: 文件路径
+//
+// 返回:
+//   - os.FileInfo: 文件信息
+//   - error: 文件不存在、已被修改或读取失败时返回错误
+func (g DuplicateGroup) verify(path string) (os.FileInfo, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info '%s': %w", path, err)
+	}
+	if !info.Mode().IsRegular() || info.Size() != g.Size {
+		return nil, fmt.Errorf("file '%s' changed since scan", path)
+	}
+
+	algorithm := g.algorithm
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	sum, err := hash.Checksum(path, algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash '%s': %w", path, err)
+	}
+	if sum != g.Hash {
+		return nil, fmt.Errorf("file '%s' changed since scan", path)
+	}
+	return info, nil
+}
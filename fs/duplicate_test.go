@@ -0,0 +1,153 @@
+package fs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"gitee.com/MM-Q/go-kit/hash"
+)
+
+// setupDuplicateTree 创建包含重复文件的目录树
+// 大文件只有中间部分不同，快速哈希相同，需要完整哈希区分
+func setupDuplicateTree(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	big := bytes.Repeat([]byte("0123456789abcdef"), 2048) // 32KB
+	bigOther := slices.Clone(big)
+	bigOther[len(bigOther)/2] = 'X'
+
+	writeSyncFile(t, filepath.Join(root, "a", "small.txt"), "duplicate")
+	writeSyncFile(t, filepath.Join(root, "b", "small.txt"), "duplicate")
+	writeSyncFile(t, filepath.Join(root, "b", "other.txt"), "different")
+	writeSyncFile(t, filepath.Join(root, "a", "big.bin"), string(big))
+	writeSyncFile(t, filepath.Join(root, "b", "big.bin"), string(big))
+	writeSyncFile(t, filepath.Join(root, "c", "big.bin"), string(big))
+	writeSyncFile(t, filepath.Join(root, "c", "near.bin"), string(bigOther))
+	writeSyncFile(t, filepath.Join(root, "empty1"), "")
+	writeSyncFile(t, filepath.Join(root, "empty2"), "")
+	return root
+}
+
+func TestFindDuplicates(t *testing.T) {
+	root := setupDuplicateTree(t)
+
+	groups, err := FindDuplicates([]string{root}, nil)
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %d: %v", len(groups), groups)
+	}
+
+	// 按浪费的空间排序，大文件组在前
+	wantBig := []string{filepath.Join(root, "a", "big.bin"), filepath.Join(root, "b", "big.bin"), filepath.Join(root, "c", "big.bin")}
+	if !slices.Equal(groups[0].Paths, wantBig) {
+		t.Errorf("Big group = %v, want %v", groups[0].Paths, wantBig)
+	}
+	if groups[0].Size != 32768 || groups[0].Wasted() != 65536 {
+		t.Errorf("Size = %d, Wasted = %d", groups[0].Size, groups[0].Wasted())
+	}
+	wantSmall := []string{filepath.Join(root, "a", "small.txt"), filepath.Join(root, "b", "small.txt")}
+	if !slices.Equal(groups[1].Paths, wantSmall) {
+		t.Errorf("Small group = %v, want %v", groups[1].Paths, wantSmall)
+	}
+
+	// 快速哈希覆盖全部内容的小文件与完整哈希一致
+	for _, g := range groups {
+		if sum, _ := hash.Checksum(g.Paths[0], "sha256"); g.Hash != sum {
+			t.Errorf("Hash of %s = %s, want full content hash", g.Paths[0], g.Hash)
+		}
+	}
+
+	// 重叠的根目录不会重复计算同一文件
+	groups, err = FindDuplicates([]string{root, filepath.Join(root, "a")}, &DuplicateOptions{MinSize: 100})
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Paths) != 3 {
+		t.Errorf("Expected one group of 3 files, got %v", groups)
+	}
+
+	if _, err := FindDuplicates(nil, nil); err == nil {
+		t.Error("Expected error for empty roots")
+	}
+	if _, err := FindDuplicates([]string{root}, &DuplicateOptions{Algorithm: "crc"}); err == nil {
+		t.Error("Expected unsupported algorithm error")
+	}
+}
+
+func TestDuplicateGroupRemove(t *testing.T) {
+	root := setupDuplicateTree(t)
+
+	groups, err := FindDuplicates([]string{root}, &DuplicateOptions{Include: []string{"*.bin"}})
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 group, got %v", groups)
+	}
+	g := groups[0]
+
+	// 扫描后被修改的文件不会被删除
+	modified := g.Paths[2]
+	data, _ := os.ReadFile(modified)
+	data[0] = 'Z'
+	if err := os.WriteFile(modified, data, 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := g.Remove(); err == nil {
+		t.Error("Expected error for modified file")
+	}
+	if !Exists(g.Paths[0]) || Exists(g.Paths[1]) || !Exists(modified) {
+		t.Errorf("Unexpected files after Remove: keep=%v dup=%v modified=%v", Exists(g.Paths[0]), Exists(g.Paths[1]), Exists(modified))
+	}
+}
+
+func TestDuplicateGroupLink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Hard link detection requires inode numbers")
+	}
+	root := setupDuplicateTree(t)
+
+	groups, err := FindDuplicates([]string{root}, nil)
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	for _, g := range groups {
+		if err := g.Link(); err != nil {
+			t.Fatalf("Link failed: %v", err)
+		}
+		keep, _ := os.Stat(g.Paths[0])
+		for _, path := range g.Paths[1:] {
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("Stat failed: %v", err)
+			}
+			if !os.SameFile(keep, info) {
+				t.Errorf("%s is not linked to %s", path, g.Paths[0])
+			}
+		}
+		// 重复执行不会出错，也不会留下临时文件
+		if err := g.Link(); err != nil {
+			t.Errorf("Second Link failed: %v", err)
+		}
+	}
+
+	// 硬链接指向同一 inode，不再被视为重复
+	groups, err = FindDuplicates([]string{root}, nil)
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("Expected no duplicates after linking, got %v", groups)
+	}
+	if got := len(listFiles(t, root)); got != 9 {
+		t.Errorf("Expected 9 files after linking, got %d", got)
+	}
+}
@@ -10,7 +10,7 @@ import (
 // 将包含通配符的模式展开为具体路径，返回所有匹配的文件和目录
 //
 // 参数:
-//   - patterns: 文件路径模式列表，支持 *、?、[] 等通配符
+//   - patterns: 文件路径模式列表，支持 *、?、[]、**、{a,b} 等通配符（语法见 Match）
 //
 // 返回值:
 //   - []string: 展开后的路径列表（去重），包含文件和目录
@@ -25,6 +25,7 @@ import (
 //
 //	paths, err := fs.Expand([]string{"*.go"})                    // [main.go utils.go]
 //	paths, err := fs.Expand([]string{"src/*"})                   // [src/main.go src/utils src/pkg]
+//	paths, err := fs.Expand([]string{"src/**/*.{go,mod}"})       // [src/go.mod src/main.go src/pkg/util.go]
 //	paths, err := fs.Expand([]string{"config.yaml"})             // [config.yaml]（原样保留）
 //	paths, err := fs.Expand([]string{"*.notexist"})              // [*.notexist]（无匹配时保留）
 func Expand(patterns []string) ([]string, error) {
@@ -64,7 +65,7 @@ func Expand(patterns []string) ([]string, error) {
 // 与 Expand 类似，但自动过滤掉目录路径
 //
 // 参数:
-//   - patterns: 文件路径模式列表，支持 *、?、[] 等通配符
+//   - patterns: 文件路径模式列表，支持 *、?、[]、**、{a,b} 等通配符（语法见 Match）
 //
 // 返回值:
 //   - []string: 展开后的文件路径列表（去重），只包含文件
@@ -93,7 +94,7 @@ func ExpandFiles(patterns []string) ([]string, error) {
 }
 
 // expandPattern 展开单个模式
-// 内部使用 filepath.Glob 实现
+// 内部使用 glob 实现，支持 **、{a,b}、否定字符类和转义
 //
 // 参数:
 //   - pattern: 文件路径模式
@@ -102,8 +103,7 @@ func ExpandFiles(patterns []string) ([]string, error) {
 //   - []string: 展开后的路径列表
 //   - error: 模式语法错误时返回错误
 func expandPattern(pattern string) ([]string, error) {
-	// 使用 filepath.Glob
-	matches, err := filepath.Glob(pattern)
+	matches, err := glob(pattern)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +135,7 @@ func isDir(path string) bool {
 // 与 Expand 类似，但只接收单个模式，返回所有匹配的路径（文件和目录）
 //
 // 参数:
-//   - pattern: 文件路径模式，支持 *、?、[] 等通配符
+//   - pattern: 文件路径模式，支持 *、?、[]、**、{a,b} 等通配符（语法见 Match）
 //
 // 返回值:
 //   - []string: 展开后的路径列表，包含文件和目录
@@ -0,0 +1,168 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errSkipEntry 表示当前条目被过滤条件排除（仅内部使用）
+var errSkipEntry = errors.New("entry filtered")
+
+// FilterFunc 自定义过滤函数，返回 false 时跳过该条目（目录会被整体跳过）
+// Sync 删除目标中多余的条目时，path 为源目录中对应的路径（可能不存在），d 为目标中的目录项
+//
+// 参数:
+//   - path: 源路径
+//   - d: 目录项
+type FilterFunc func(path string, d fs.DirEntry) bool
+
+// hasFilters 是否设置了任何过滤条件
+func (o *CopyOptions) hasFilters() bool {
+	return len(o.Include) > 0 || len(o.Exclude) > 0 || o.Ignore != nil || o.Filter != nil || o.MaxDepth > 0 ||
+		o.MinSize > 0 || o.MaxSize > 0 || !o.NewerThan.IsZero() || !o.OlderThan.IsZero()
+}
+
+// validate 校验选项，目前只检查通配符模式的语法
+func (o *CopyOptions) validate() error {
+	for _, patterns := range [][]string{o.Include, o.Exclude} {
+		for _, pattern := range patterns {
+			if _, err := compileGlob(pattern); err != nil {
+				return fmt.Errorf("invalid filter pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// filterEntry 判断目录中的条目是否需要复制
+//
+// 参数:
+//   - srcPath: 条目的源路径
+//   - rel: 相对复制根目录的路径
+//   - entry: 目录项
+//
+// 返回:
+//   - error: nil 表示复制；errSkipEntry 表示跳过文件；fs.SkipDir 表示跳过整个目录；其他为获取信息失败
+func (c *copier) filterEntry(srcPath, rel string, entry fs.DirEntry) error {
+	o := c.opts
+	skip := errSkipEntry
+	if entry.IsDir() {
+		skip = fs.SkipDir
+	}
+
+	if o.MaxDepth > 0 && strings.Count(rel, string(filepath.Separator))+1 > o.MaxDepth {
+		return skip
+	}
+	if matchAny(o.Exclude, rel) {
+		return skip
+	}
+	if o.Ignore != nil {
+		ignored, err := o.Ignore.match(srcPath, entry.IsDir())
+		if err != nil {
+			return err
+		}
+		if ignored {
+			return skip
+		}
+	}
+	if o.Filter != nil && !o.Filter(srcPath, entry) {
+		return skip
+	}
+
+	// 以下条件只作用于文件，目录始终遍历
+	if entry.IsDir() {
+		return nil
+	}
+	if len(o.Include) > 0 && !matchAny(o.Include, rel) {
+		return skip
+	}
+	if !entry.Type().IsRegular() || (o.MinSize <= 0 && o.MaxSize <= 0 && o.NewerThan.IsZero() && o.OlderThan.IsZero()) {
+		return nil
+	}
+
+	info, err := entry.Info()
+	if err != nil {
+		return fmt.Errorf("failed to get file info '%s': %w", srcPath, err)
+	}
+	if !withinLimits(info.Size(), info.ModTime(), o) {
+		return skip
+	}
+	return nil
+}
+
+// withinLimits 检查文件大小和修改时间是否满足限制
+func withinLimits(size int64, mtime time.Time, o *CopyOptions) bool {
+	if o.MinSize > 0 && size < o.MinSize {
+		return false
+	}
+	if o.MaxSize > 0 && size > o.MaxSize {
+		return false
+	}
+	if !o.NewerThan.IsZero() && !mtime.After(o.NewerThan) {
+		return false
+	}
+	if !o.OlderThan.IsZero() && !mtime.Before(o.OlderThan) {
+		return false
+	}
+	return true
+}
+
+// matchAny 检查相对路径是否匹配任一模式
+// 不含 / 的模式匹配文件名（任意层级），含 / 的模式匹配完整相对路径
+//
+// 参数:
+//   - patterns: 通配符模式列表
+//   - rel: 相对路径（使用系统路径分隔符）
+//
+// 返回:
+//   - bool: 是否匹配
+func matchAny(patterns []string, rel string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	slashRel := filepath.ToSlash(rel)
+	base := path.Base(slashRel)
+	for _, pattern := range patterns {
+		target := base
+		if strings.Contains(pattern, "/") {
+			target = slashRel
+		}
+		if ok, _ := Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// removeCopiedSources 删除已复制的源条目，用于带过滤条件的移动
+// 只删除实际被复制的文件，随后删除因此变为空的目录，被过滤掉的内容保留在原处
+//
+// 参数:
+//   - srcAbs: 源目录绝对路径
+//   - files: 已复制的源文件路径
+//   - dirs: 已复制的源目录路径
+//
+// 返回:
+//   - error: 删除文件失败时返回错误
+func removeCopiedSources(srcAbs string, files, dirs []string) error {
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove source '%s': %w", file, err)
+		}
+	}
+
+	// 从深到浅删除空目录，非空目录（包含被过滤的内容）保留
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	for _, dir := range dirs {
+		_ = os.Remove(dir)
+	}
+	_ = os.Remove(srcAbs)
+	return nil
+}
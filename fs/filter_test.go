@@ -0,0 +1,191 @@
+package fs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupFilterTree 创建过滤测试的项目目录结构
+func setupFilterTree(t *testing.T) string {
+	t.Helper()
+
+	root := filepath.Join(t.TempDir(), "project")
+	files := map[string]string{
+		"main.go":                   "package main",
+		"go.mod":                    "module demo",
+		"app.log":                   "log line",
+		"big.bin":                   strings.Repeat("x", 4096),
+		".git/HEAD":                 "ref: refs/heads/main",
+		".git/objects/ab/cdef":      "object",
+		"node_modules/pkg/index.js": "module.exports = {}",
+		"pkg/util.go":               "package pkg",
+		"pkg/debug.log":             "debug",
+		"docs/tmp/draft.md":         "draft",
+		"docs/guide.md":             "guide",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	return root
+}
+
+// listFiles 返回目录下所有文件的相对路径（使用 / 分隔，已排序）
+func listFiles(t *testing.T, root string) []string {
+	t.Helper()
+
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			rel, _ := filepath.Rel(root, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to list files: %v", err)
+	}
+	slices.Sort(files)
+	return files
+}
+
+func TestCopyFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *CopyOptions
+		want []string
+	}{
+		{
+			name: "Exclude",
+			opts: &CopyOptions{Exclude: []string{".git", "node_modules", "*.log", "docs/tmp"}},
+			want: []string{"big.bin", "docs/guide.md", "go.mod", "main.go", "pkg/util.go"},
+		},
+		{
+			name: "Include",
+			opts: &CopyOptions{Include: []string{"*.go", "go.mod"}, Exclude: []string{".git"}},
+			want: []string{"go.mod", "main.go", "pkg/util.go"},
+		},
+		{
+			name: "Max depth",
+			opts: &CopyOptions{MaxDepth: 1, Exclude: []string{"*.log"}},
+			want: []string{"big.bin", "go.mod", "main.go"},
+		},
+		{
+			name: "Size limits",
+			opts: &CopyOptions{MinSize: 10, MaxSize: 1024, Exclude: []string{".git", "node_modules"}},
+			want: []string{"go.mod", "main.go", "pkg/util.go"},
+		},
+		{
+			name: "Predicate",
+			opts: &CopyOptions{Filter: func(path string, d fs.DirEntry) bool {
+				return d.IsDir() && d.Name() != ".git" && d.Name() != "node_modules" || strings.HasSuffix(path, ".md")
+			}},
+			want: []string{"docs/guide.md", "docs/tmp/draft.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := setupFilterTree(t)
+			dst := filepath.Join(t.TempDir(), "out")
+			if err := CopyWithOptions(src, dst, tt.opts); err != nil {
+				t.Fatalf("CopyWithOptions failed: %v", err)
+			}
+			want := slices.Sorted(slices.Values(tt.want))
+			if got := listFiles(t, dst); !slices.Equal(got, want) {
+				t.Errorf("Copied files = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCopyFiltersPrune(t *testing.T) {
+	src := setupFilterTree(t)
+
+	var visited []string
+	opts := &CopyOptions{
+		Exclude: []string{".git"},
+		Filter: func(path string, d fs.DirEntry) bool {
+			visited = append(visited, path)
+			return true
+		},
+	}
+	if err := CopyWithOptions(src, filepath.Join(t.TempDir(), "out"), opts); err != nil {
+		t.Fatalf("CopyWithOptions failed: %v", err)
+	}
+	for _, path := range visited {
+		if strings.Contains(path, ".git") {
+			t.Errorf("Excluded directory was walked: %s", path)
+		}
+	}
+}
+
+func TestCopyFiltersTimeAndProgress(t *testing.T) {
+	src := setupFilterTree(t)
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(src, "main.go"), old, old); err != nil {
+		t.Fatalf("Failed to set times: %v", err)
+	}
+
+	var last CopyProgress
+	dst := filepath.Join(t.TempDir(), "out")
+	opts := &CopyOptions{
+		Include:   []string{"*.go"},
+		NewerThan: time.Now().Add(-24 * time.Hour),
+		Progress:  func(p CopyProgress) { last = p },
+	}
+	if err := CopyWithOptions(src, dst, opts); err != nil {
+		t.Fatalf("CopyWithOptions failed: %v", err)
+	}
+	if got := listFiles(t, dst); !slices.Equal(got, []string{"pkg/util.go"}) {
+		t.Errorf("Copied files = %v, want [pkg/util.go]", got)
+	}
+	if last.FilesTotal != 1 || last.FilesDone != 1 || last.BytesTotal != int64(len("package pkg")) {
+		t.Errorf("Progress = %+v, want 1 file of %d bytes", last, len("package pkg"))
+	}
+}
+
+func TestCopyFiltersInvalidPattern(t *testing.T) {
+	src := setupFilterTree(t)
+	err := CopyWithOptions(src, filepath.Join(t.TempDir(), "out"), &CopyOptions{Exclude: []string{"[abc"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid filter pattern") {
+		t.Errorf("Expected invalid pattern error, got %v", err)
+	}
+}
+
+func TestMoveContextFilters(t *testing.T) {
+	src := setupFilterTree(t)
+	dst := filepath.Join(t.TempDir(), "out")
+
+	opts := &CopyOptions{Exclude: []string{".git", "node_modules", "*.log"}}
+	if err := MoveContext(t.Context(), src, dst, opts); err != nil {
+		t.Fatalf("MoveContext failed: %v", err)
+	}
+
+	want := []string{"big.bin", "docs/guide.md", "docs/tmp/draft.md", "go.mod", "main.go", "pkg/util.go"}
+	if got := listFiles(t, dst); !slices.Equal(got, want) {
+		t.Errorf("Moved files = %v, want %v", got, want)
+	}
+
+	// 被过滤的内容保留在源目录中，已移空的目录被删除
+	left := []string{".git/HEAD", ".git/objects/ab/cdef", "app.log", "node_modules/pkg/index.js", "pkg/debug.log"}
+	if got := listFiles(t, src); !slices.Equal(got, left) {
+		t.Errorf("Remaining source files = %v, want %v", got, left)
+	}
+	if _, err := os.Stat(filepath.Join(src, "docs")); !os.IsNotExist(err) {
+		t.Errorf("Empty source directory was not removed: %v", err)
+	}
+}
@@ -91,56 +91,29 @@ func GetExecutablePath() string {
 // 参数:
 //   - dirPath: 要遍历的目录路径
 //   - recursive: 是否递归遍历子目录
+//   - ignore: 忽略规则, 为 nil 时不忽略任何条目
 //
 // 返回:
 //   - []string: 收集到的文件路径切片
 //   - error: 遍历失败时返回错误
-func walkDir(dirPath string, recursive bool) ([]string, error) {
+func walkDir(dirPath string, recursive bool, ignore *IgnoreMatcher) ([]string, error) {
 	// 快速失败：检查路径是否为空
 	if dirPath == "" {
 		return nil, fmt.Errorf("directory path cannot be empty")
 	}
 
-	var files []string
+	opts := &WalkOptions{Types: WalkAll &^ WalkDirs, Sorted: true, Ignore: ignore}
+	if !recursive {
+		opts.MaxDepth = 1
+	}
 
-	// 递归模式
-	if recursive {
-		err := filepath.WalkDir(dirPath, func(path string, d os.DirEntry, err error) error {
-			// 快速失败：遇到错误立即返回
-			if err != nil {
-				return err
-			}
-			// 快速跳过：只处理文件
-			if !d.IsDir() {
-				files = append(files, path)
-			}
-			return nil
-		})
-		// 快速返回错误
+	files := []string{}
+	for entry, err := range Walk(dirPath, opts) {
+		// 快速失败：遇到错误立即返回
 		if err != nil {
 			return nil, fmt.Errorf("failed to walk directory %q: %w", dirPath, err)
 		}
-		return files, nil
-	}
-
-	// 非递归模式
-	entries, err := os.ReadDir(dirPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read directory %q: %w", dirPath, err)
-	}
-
-	// 快速返回：如果目录为空
-	if len(entries) == 0 {
-		return []string{}, nil
-	}
-
-	// 预分配容量以提高性能
-	files = make([]string, 0, len(entries))
-	for _, entry := range entries {
-		// 快速跳过：只处理文件
-		if !entry.IsDir() {
-			files = append(files, filepath.Join(dirPath, entry.Name()))
-		}
+		files = append(files, entry.Path)
 	}
 
 	return files, nil
@@ -150,13 +123,34 @@ func walkDir(dirPath string, recursive bool) ([]string, error) {
 // 用于收集文件或目录中的文件, 支持通配符匹配和递归遍历
 //
 // 参数:
-//   - targetPath: 目标路径, 支持通配符(*?[]{})
+//   - targetPath: 目标路径, 支持通配符(*?[]{}、**, 语法见 Match)
 //   - recursive: 是否递归遍历目录
 //
 // 返回:
 //   - []string: 收集到的文件路径切片
 //   - error: 收集失败时返回错误
 func Collect(targetPath string, recursive bool) ([]string, error) {
+	return CollectEx(targetPath, recursive, nil)
+}
+
+// CollectEx 收集指定路径下的所有文件, 跳过被忽略规则匹配的条目
+// 目录中被忽略的文件和目录(包括其全部内容)不会被收集, 通配符匹配到的被忽略路径也会被跳过;
+// 不含通配符的目标路径本身总是被处理
+//
+// 参数:
+//   - targetPath: 目标路径, 支持通配符(*?[]{}、**, 语法见 Match)
+//   - recursive: 是否递归遍历目录
+//   - ignore: 忽略规则, 为 nil 时与 Collect 相同
+//
+// 返回:
+//   - []string: 收集到的文件路径切片
+//   - error: 收集失败时返回错误
+//
+// 示例:
+//
+//	ignore, _ := fs.NewIgnoreMatcher(".", ".gitignore")
+//	files, err := fs.CollectEx(".", true, ignore)
+func CollectEx(targetPath string, recursive bool, ignore *IgnoreMatcher) ([]string, error) {
 	// 快速失败：检查路径是否为空
 	if targetPath == "" {
 		return nil, fmt.Errorf("target path cannot be empty")
@@ -164,30 +158,31 @@ func Collect(targetPath string, recursive bool) ([]string, error) {
 
 	// 快速路由：根据是否包含通配符选择处理方式
 	if strings.ContainsAny(targetPath, "*?[]{}") {
-		return collectGlobFiles(targetPath, recursive)
+		return collectGlobFiles(targetPath, recursive, ignore)
 	}
 
-	return collectSinglePath(targetPath, recursive)
+	return collectSinglePath(targetPath, recursive, ignore)
 }
 
 // collectGlobFiles 处理包含通配符的路径模式并收集匹配的文件
-// 使用filepath.Glob匹配通配符模式, 然后收集所有匹配路径中的文件
+// 使用glob匹配通配符模式(支持 **、{a,b} 等, 语法见 Match), 然后收集所有匹配路径中的文件
 //
 // 参数:
 //   - pattern: 包含通配符的路径模式 (如 "*.go", "dir/*", "**/*.txt")
 //   - recursive: 当匹配到目录时, 是否递归遍历子目录
+//   - ignore: 忽略规则, 为 nil 时不忽略任何条目
 //
 // 返回:
 //   - []string: 所有匹配文件的路径切片
 //   - error: 模式无效、无匹配文件或处理过程中的错误
-func collectGlobFiles(pattern string, recursive bool) ([]string, error) {
+func collectGlobFiles(pattern string, recursive bool, ignore *IgnoreMatcher) ([]string, error) {
 	// 快速失败：检查模式是否为空
 	if pattern == "" {
 		return nil, fmt.Errorf("glob pattern cannot be empty")
 	}
 
 	// 匹配通配符
-	matchedFiles, err := filepath.Glob(pattern)
+	matchedFiles, err := glob(pattern)
 	if err != nil {
 		return nil, fmt.Errorf("invalid path pattern %q: %w", pattern, err)
 	}
@@ -199,7 +194,22 @@ func collectGlobFiles(pattern string, recursive bool) ([]string, error) {
 
 	var files []string
 	for _, file := range matchedFiles {
-		pathFiles, err := collectSinglePath(file, recursive)
+		// 跳过被忽略的匹配路径, 忽略规则按绝对路径匹配(通配符结果相对于当前工作目录)
+		if ignore != nil {
+			absFile, err := filepath.Abs(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get absolute path: %w", err)
+			}
+			ignored, err := ignore.Match(absFile, isDir(file))
+			if err != nil {
+				return nil, err
+			}
+			if ignored {
+				continue
+			}
+		}
+
+		pathFiles, err := collectSinglePath(file, recursive, ignore)
 		// 快速失败：遇到错误立即返回
 		if err != nil {
 			return nil, err
@@ -216,11 +226,12 @@ func collectGlobFiles(pattern string, recursive bool) ([]string, error) {
 // 参数:
 //   - path: 要处理的具体路径 (不包含通配符)
 //   - recursive: 当路径为目录时, 是否递归遍历子目录
+//   - ignore: 忽略规则, 为 nil 时不忽略任何条目
 //
 // 返回:
 //   - []string: 文件路径切片, 单个文件返回包含该文件的切片, 目录返回其中所有文件
 //   - error: 路径不存在、无权限访问或遍历过程中的错误
-func collectSinglePath(path string, recursive bool) ([]string, error) {
+func collectSinglePath(path string, recursive bool, ignore *IgnoreMatcher) ([]string, error) {
 	// 快速失败：检查路径是否为空
 	if path == "" {
 		return nil, fmt.Errorf("path cannot be empty")
@@ -237,7 +248,7 @@ func collectSinglePath(path string, recursive bool) ([]string, error) {
 	}
 
 	// 如果是目录, 进行遍历
-	return walkDir(path, recursive)
+	return walkDir(path, recursive, ignore)
 }
 
 // wrapPathError 包装路径相关错误, 提供统一的错误处理
@@ -269,6 +280,20 @@ func wrapPathError(err error, path, operation string) error {
 //   - int64: 文件或目录的总大小(字节)
 //   - error: 路径不存在或访问失败时返回错误
 func GetSize(path string) (int64, error) {
+	return GetSizeEx(path, nil)
+}
+
+// GetSizeEx 获取文件或目录的大小, 跳过被忽略规则匹配的条目
+// 目录中被忽略的文件和目录(包括其全部内容)不计入大小, 路径本身总是被计算
+//
+// 参数:
+//   - path: 文件或目录路径
+//   - ignore: 忽略规则, 为 nil 时与 GetSize 相同
+//
+// 返回:
+//   - int64: 文件或目录的总大小(字节)
+//   - error: 路径不存在、访问失败或读取忽略文件失败时返回错误
+func GetSizeEx(path string, ignore *IgnoreMatcher) (int64, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		return 0, wrapPathError(err, path, "accessing")
@@ -284,6 +309,13 @@ func GetSize(path string) (int64, error) {
 		return 0, nil
 	}
 
+	// 忽略规则按绝对路径匹配
+	if ignore != nil {
+		if path, err = filepath.Abs(path); err != nil {
+			return 0, fmt.Errorf("failed to get absolute path: %w", err)
+		}
+	}
+
 	// 如果是目录, 遍历计算总大小
 	var totalSize int64
 	walkDirErr := filepath.WalkDir(path, func(walkPath string, entry os.DirEntry, err error) error {
@@ -295,6 +327,20 @@ func GetSize(path string) (int64, error) {
 			return wrapPathError(err, walkPath, "accessing")
 		}
 
+		// 跳过被忽略的条目, 被忽略的目录整体跳过
+		if ignore != nil && walkPath != path {
+			ignored, err := ignore.match(walkPath, entry.IsDir())
+			if err != nil {
+				return err
+			}
+			if ignored {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
 		// 只计算普通文件的大小
 		if entry.Type().IsRegular() {
 			fileInfo, err := entry.Info()
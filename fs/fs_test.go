@@ -222,7 +222,7 @@ func TestWalkDir(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := walkDir(tt.dirPath, tt.recursive)
+			got, err := walkDir(tt.dirPath, tt.recursive, nil)
 
 			if tt.wantErr {
 				if err == nil {
@@ -0,0 +1,348 @@
+package fs
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// globPattern 编译后的通配符模式：大括号展开后的每个候选模式按 / 拆分为路径段
+type globPattern [][]string
+
+// Match 判断路径是否匹配通配符模式，不访问文件系统
+// 模式和路径都使用 / 作为分隔符，支持以下语法:
+//   - *: 匹配路径段内任意数量的字符（不跨越 /）
+//   - ?: 匹配路径段内的单个字符
+//   - **: 作为完整路径段时匹配零个或多个目录（如 src/**/*.go）
+//   - [abc]、[a-z]: 字符类；[!abc]、[^abc]: 否定字符类
+//   - {a,b}: 多选一，可嵌套，可包含 /（如 {src,test}/**/*.{go,mod}）
+//   - \: 转义下一个字符
+//
+// 参数:
+//   - pattern: 通配符模式
+//   - name: 需要匹配的路径
+//
+// 返回:
+//   - bool: 是否匹配
+//   - error: 模式语法错误时返回 path.ErrBadPattern
+//
+// 示例:
+//
+//	ok, _ := fs.Match("src/**/*.{go,mod}", "src/a/b/main.go") // true
+//	ok, _ := fs.Match("src/**/*.{go,mod}", "src/go.mod")      // true
+//	ok, _ := fs.Match("*.[!o]", "main.c")                     // true
+func Match(pattern, name string) (bool, error) {
+	g, err := compileGlob(pattern)
+	if err != nil {
+		return false, err
+	}
+	return g.match(name), nil
+}
+
+// compileGlob 展开大括号并校验每个路径段的语法
+//
+// 参数:
+//   - pattern: 通配符模式（使用 / 分隔）
+//
+// 返回:
+//   - globPattern: 编译后的模式
+//   - error: 模式语法错误时返回 path.ErrBadPattern
+func compileGlob(pattern string) (globPattern, error) {
+	alts, err := expandBraces(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	g := make(globPattern, 0, len(alts))
+	for _, alt := range alts {
+		segs := strings.Split(negateClasses(alt), "/")
+		for _, seg := range segs {
+			if _, err := path.Match(seg, ""); err != nil {
+				return nil, err
+			}
+		}
+		g = append(g, segs)
+	}
+	return g, nil
+}
+
+// match 判断路径是否匹配任一候选模式
+func (g globPattern) match(name string) bool {
+	nameSegs := strings.Split(name, "/")
+	for _, segs := range g {
+		if matchSegments(segs, nameSegs) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments 逐段匹配路径，** 段匹配零个或多个路径段
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// 连续的 ** 等价于一个
+			for len(pattern) > 1 && pattern[1] == "**" {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// expandBraces 展开模式中的 {a,b} 多选结构（支持嵌套），转义的大括号和字符类中的大括号保持原样
+//
+// 参数:
+//   - pattern: 通配符模式
+//
+// 返回:
+//   - []string: 展开后的模式列表
+//   - error: 大括号不配对时返回 path.ErrBadPattern
+func expandBraces(pattern string) ([]string, error) {
+	// 查找第一个顶层 {
+	start := -1
+	inClass := false
+	for i := 0; i < len(pattern) && start < 0; i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+		case c == '{':
+			start = i
+		}
+	}
+	if start < 0 {
+		return []string{pattern}, nil
+	}
+
+	// 查找与之配对的 }，记录顶层逗号的位置
+	depth := 0
+	end := -1
+	commas := []int{}
+	inClass = false
+	for i := start + 1; i < len(pattern) && end < 0; i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+		case c == '{':
+			depth++
+		case c == '}':
+			if depth == 0 {
+				end = i
+			}
+			depth--
+		case c == ',' && depth == 0:
+			commas = append(commas, i)
+		}
+	}
+	if end < 0 {
+		return nil, path.ErrBadPattern
+	}
+
+	prefix, suffix := pattern[:start], pattern[end+1:]
+	bounds := append(append([]int{start}, commas...), end)
+	var result []string
+	for i := 0; i < len(bounds)-1; i++ {
+		// 递归展开候选项中嵌套的大括号以及后缀中的其他大括号
+		alts, err := expandBraces(prefix + pattern[bounds[i]+1:bounds[i+1]] + suffix)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, alts...)
+	}
+	return result, nil
+}
+
+// negateClasses 将字符类开头的 ! 转换为 path.Match 支持的 ^
+func negateClasses(pattern string) string {
+	if !strings.Contains(pattern, "[!") {
+		return pattern
+	}
+
+	b := []byte(pattern)
+	inClass := false
+	for i := 0; i < len(b); i++ {
+		switch c := b[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+			if i+1 < len(b) && b[i+1] == '!' {
+				b[i+1] = '^'
+				i++
+			}
+		}
+	}
+	return string(b)
+}
+
+// hasGlobMeta 路径段是否包含通配符或转义字符
+func hasGlobMeta(seg string) bool {
+	return strings.ContainsAny(seg, `*?[\`)
+}
+
+// unescapeGlob 去除路径段中的转义字符
+func unescapeGlob(seg string) string {
+	if !strings.Contains(seg, `\`) {
+		return seg
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(seg); i++ {
+		if seg[i] == '\\' && i+1 < len(seg) {
+			i++
+		}
+		sb.WriteByte(seg[i])
+	}
+	return sb.String()
+}
+
+// glob 返回文件系统中匹配模式的所有路径（按名称排序、去重），支持与 Match 相同的语法
+// ** 不会进入符号链接指向的目录，避免循环
+// Windows 上 \ 被视为路径分隔符而不是转义字符
+//
+// 参数:
+//   - pattern: 通配符模式（使用系统路径分隔符或 /）
+//
+// 返回:
+//   - []string: 匹配的路径，没有匹配时为空
+//   - error: 模式语法错误时返回 path.ErrBadPattern
+func glob(pattern string) ([]string, error) {
+	g, err := compileGlob(filepath.ToSlash(pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var matches []string
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			matches = append(matches, p)
+		}
+	}
+
+	for _, segs := range g {
+		// 不含通配符的前导路径段直接作为起始目录
+		i := 0
+		for i < len(segs) && !hasGlobMeta(segs[i]) {
+			segs[i] = unescapeGlob(segs[i])
+			i++
+		}
+		base := strings.Join(segs[:i], "/")
+		if base == "" && len(segs) > 1 && i > 0 {
+			base = "/"
+		}
+		base = filepath.FromSlash(base)
+		if base != "" && filepath.VolumeName(base) == base {
+			// Windows 盘符需要加上分隔符才表示根目录
+			base += string(filepath.Separator)
+		}
+
+		if i == len(segs) {
+			if _, err := os.Lstat(base); err == nil {
+				add(base)
+			}
+			continue
+		}
+		globSegments(base, segs[i:], add)
+	}
+
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// globSegments 从目录 dir 开始逐段匹配剩余的模式，无法读取的目录被忽略（与 filepath.Glob 一致）
+//
+// 参数:
+//   - dir: 当前目录，空字符串表示当前工作目录
+//   - segs: 剩余的模式路径段
+//   - add: 记录匹配路径的函数
+func globSegments(dir string, segs []string, add func(string)) {
+	if len(segs) == 0 {
+		add(dir)
+		return
+	}
+
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+	join := func(name string) string {
+		if dir == "" {
+			return name
+		}
+		return filepath.Join(dir, name)
+	}
+
+	seg := segs[0]
+	if seg == "**" {
+		// 匹配零个目录
+		if dir != "" || len(segs) > 1 {
+			globSegments(dir, segs[1:], add)
+		}
+		entries, err := os.ReadDir(readDir)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				globSegments(join(entry.Name()), segs, add)
+			} else if len(segs) == 1 {
+				add(join(entry.Name()))
+			}
+		}
+		return
+	}
+
+	if !hasGlobMeta(seg) {
+		p := join(seg)
+		if _, err := os.Lstat(p); err == nil && (len(segs) == 1 || isDir(p)) {
+			globSegments(p, segs[1:], add)
+		}
+		return
+	}
+
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if ok, _ := path.Match(seg, entry.Name()); !ok {
+			continue
+		}
+		p := join(entry.Name())
+		if len(segs) > 1 && !isDir(p) {
+			continue
+		}
+		globSegments(p, segs[1:], add)
+	}
+}
@@ -0,0 +1,131 @@
+package fs
+
+import (
+	"errors"
+	"path"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "src/main.go", false},
+		{"src/*.go", "src/main.go", true},
+		{"src/**/*.go", "src/main.go", true},
+		{"src/**/*.go", "src/a/b/main.go", true},
+		{"src/**/*.go", "test/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**", "a/b/c", true},
+		{"src/**", "src", true},
+		{"src/**", "src/a/b", true},
+		{"a/**/**/b", "a/b", true},
+		{"a**b", "a/x/b", false},
+		{"a**b", "axxb", true},
+		{"*.{go,mod}", "go.mod", true},
+		{"*.{go,mod}", "go.sum", false},
+		{"{src,test}/**/*.go", "test/x/y_test.go", true},
+		{"{a,b{c,d}}.txt", "bd.txt", true},
+		{"{a,b{c,d}}.txt", "b.txt", false},
+		{"file.{}", "file.", true},
+		{"*.[!o]", "main.c", true},
+		{"*.[!o]", "main.o", false},
+		{"*.[^o]", "main.o", false},
+		{"[a-c]?.txt", "bx.txt", true},
+		{`\*.txt`, "*.txt", true},
+		{`\*.txt`, "a.txt", false},
+		{`\{a,b\}`, "{a,b}", true},
+		{"[{]*", "{x", true},
+	}
+
+	for _, tt := range tests {
+		got, err := Match(tt.pattern, tt.name)
+		if err != nil {
+			t.Errorf("Match(%q, %q) error: %v", tt.pattern, tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+
+	for _, pattern := range []string{"[", "a/[b", "{a,b", "*.{go,[}"} {
+		if _, err := Match(pattern, "a"); !errors.Is(err, path.ErrBadPattern) {
+			t.Errorf("Match(%q) error = %v, want ErrBadPattern", pattern, err)
+		}
+	}
+}
+
+func TestExpandDoublestar(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{"go.mod", "main.go", "README.md", "pkg/util.go", "pkg/deep/x.go", "pkg/deep/x.o", "test/a_test.go"} {
+		writeSyncFile(t, filepath.Join(root, file), "x")
+	}
+	rel := func(paths []string) []string {
+		for i, p := range paths {
+			r, _ := filepath.Rel(root, p)
+			paths[i] = filepath.ToSlash(r)
+		}
+		return paths
+	}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"**/*.{go,mod}", []string{"go.mod", "main.go", "pkg/deep/x.go", "pkg/util.go", "test/a_test.go"}},
+		{"pkg/**", []string{"pkg", "pkg/deep", "pkg/deep/x.go", "pkg/deep/x.o", "pkg/util.go"}},
+		{"{pkg,test}/*.go", []string{"pkg/util.go", "test/a_test.go"}},
+		{"pkg/deep/x.[!g]*", []string{"pkg/deep/x.o"}},
+	}
+	for _, tt := range tests {
+		got, err := ExpandPattern(filepath.Join(root, tt.pattern))
+		if err != nil {
+			t.Fatalf("ExpandPattern(%q) failed: %v", tt.pattern, err)
+		}
+		if got := rel(got); !slices.Equal(got, tt.want) {
+			t.Errorf("ExpandPattern(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+
+	files, err := ExpandFiles([]string{filepath.Join(root, "pkg", "**")})
+	if err != nil {
+		t.Fatalf("ExpandFiles failed: %v", err)
+	}
+	if got, want := rel(files), []string{"pkg/deep/x.go", "pkg/deep/x.o", "pkg/util.go"}; !slices.Equal(got, want) {
+		t.Errorf("ExpandFiles = %v, want %v", got, want)
+	}
+
+	collected, err := Collect(filepath.Join(root, "**", "*.{go,mod}"), false)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(collected) != 5 {
+		t.Errorf("Collect found %d files, want 5: %v", len(collected), collected)
+	}
+
+	if _, err := Expand([]string{filepath.Join(root, "{a,b")}); err == nil {
+		t.Error("Expected error for unbalanced brace")
+	}
+}
+
+func TestCopyFilterDoublestar(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	for _, file := range []string{"a.go", "vendor/x/b.go", "pkg/c.go", "pkg/c_test.go"} {
+		writeSyncFile(t, filepath.Join(src, file), "x")
+	}
+	dst := filepath.Join(t.TempDir(), "dst")
+
+	opts := &CopyOptions{Exclude: []string{"vendor/**", "**/*_test.go"}}
+	if err := CopyContext(t.Context(), src, dst, opts); err != nil {
+		t.Fatalf("CopyContext failed: %v", err)
+	}
+	if got, want := listFiles(t, dst), []string{"a.go", "pkg/c.go"}; !slices.Equal(got, want) {
+		t.Errorf("Copied files = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+)
+
+// inodeKey 文件在文件系统中的唯一标识（设备号 + inode 号）
+type inodeKey struct {
+	dev uint64
+	ino uint64
+}
+
+// hardlink 复制完成后需要在目标中重建的硬链接
+type hardlink struct {
+	src    string // 源路径
+	dst    string // 需要创建的链接路径
+	target string // 链接指向的已复制文件
+}
+
+// linkTracker 记录目录复制中遇到的多链接文件，只在遍历协程中使用
+type linkTracker map[inodeKey]string
+
+// track 记录条目的首个目标路径
+//
+// 参数:
+//   - info: 源文件信息
+//   - dst: 目标路径
+//
+// 返回:
+//   - string: 同一 inode 此前已复制到的目标路径
+//   - bool: 是否应作为硬链接创建（此前已见过该 inode）
+func (t linkTracker) track(info os.FileInfo, dst string) (string, bool) {
+	if info.IsDir() {
+		return "", false
+	}
+	key, nlink, ok := fileInode(info)
+	if !ok || nlink < 2 {
+		return "", false
+	}
+	if target, seen := t[key]; seen {
+		return target, true
+	}
+	t[key] = dst
+	return "", false
+}
+
+// createLinks 在所有文件复制完成后重建硬链接
+//
+// 参数:
+//   - links: 需要创建的硬链接
+//
+// 返回:
+//   - error: 创建失败或已取消时返回错误
+func (c *copier) createLinks(links []hardlink) error {
+	for _, link := range links {
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+		c.startFile(link.src)
+		if err := os.Link(link.target, link.dst); err != nil {
+			return fmt.Errorf("failed to create hard link '%s' -> '%s': %w", link.dst, link.target, err)
+		}
+		if c.track {
+			c.copiedFiles = append(c.copiedFiles, link.src)
+		}
+		c.finishFile(CopyHardlink)
+	}
+	return nil
+}
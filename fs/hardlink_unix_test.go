@@ -0,0 +1,98 @@
+//go:build linux || darwin
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupHardlinkTree 创建包含硬链接的目录：a.txt、b.txt、sub/c.txt 指向同一 inode
+func setupHardlinkTree(t *testing.T) string {
+	t.Helper()
+
+	root := filepath.Join(t.TempDir(), "cache")
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), []byte("shared content"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "d.txt"), []byte("single"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	for _, name := range []string{"b.txt", filepath.Join("sub", "c.txt")} {
+		if err := os.Link(filepath.Join(root, "a.txt"), filepath.Join(root, name)); err != nil {
+			t.Fatalf("Failed to create hard link: %v", err)
+		}
+	}
+	return root
+}
+
+// sameFile 检查两个路径是否指向同一文件
+func sameFile(t *testing.T, a, b string) bool {
+	t.Helper()
+
+	ai, err := os.Stat(a)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	return os.SameFile(ai, bi)
+}
+
+func TestCopyHardlinks(t *testing.T) {
+	for _, workers := range []int{0, 4} {
+		t.Run(map[int]string{0: "Sequential", 4: "Parallel"}[workers], func(t *testing.T) {
+			src := setupHardlinkTree(t)
+			dst := filepath.Join(t.TempDir(), "dst")
+
+			var last CopyProgress
+			links := 0
+			opts := ArchiveOptions()
+			opts.Workers = workers
+			opts.Progress = func(p CopyProgress) {
+				// Method 在文件完成（FilesDone 增加）时更新
+				if p.FilesDone > last.FilesDone && p.Method == CopyHardlink {
+					links++
+				}
+				last = p
+			}
+			if err := CopyWithOptions(src, dst, opts); err != nil {
+				t.Fatalf("CopyWithOptions failed: %v", err)
+			}
+
+			a := filepath.Join(dst, "a.txt")
+			for _, name := range []string{"b.txt", filepath.Join("sub", "c.txt")} {
+				if !sameFile(t, a, filepath.Join(dst, name)) {
+					t.Errorf("%s is not a hard link to a.txt", name)
+				}
+				validateFileCopy(t, filepath.Join(src, name), filepath.Join(dst, name))
+			}
+			if sameFile(t, a, filepath.Join(dst, "d.txt")) {
+				t.Error("d.txt should be an independent file")
+			}
+			if links != 2 {
+				t.Errorf("Hard links reported = %d, want 2", links)
+			}
+			if last.FilesDone != 4 || last.FilesTotal != 4 || last.BytesDone != last.BytesTotal {
+				t.Errorf("Progress = %+v, want 4/4 files and all bytes", last)
+			}
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		src := setupHardlinkTree(t)
+		dst := filepath.Join(t.TempDir(), "dst")
+		if err := CopyWithOptions(src, dst, nil); err != nil {
+			t.Fatalf("CopyWithOptions failed: %v", err)
+		}
+		if sameFile(t, filepath.Join(dst, "a.txt"), filepath.Join(dst, "b.txt")) {
+			t.Error("Hard links preserved without PreserveHardlinks")
+		}
+	})
+}
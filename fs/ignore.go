@@ -0,0 +1,346 @@
+package fs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// IgnoreMatcher gitignore 语义的忽略规则匹配器
+// 支持否定规则（!）、锚定规则（含 /）、仅目录规则（以 / 结尾）和 **，
+// 以及各级子目录中只作用于所在子树的嵌套忽略文件（按需加载并缓存）
+//
+// 规则优先级从低到高：AddPatterns/AddFile 添加的规则 → 根目录的忽略文件 → 子目录的忽略文件，
+// 同一来源中后出现的规则优先，最后一条匹配的规则决定是否忽略
+//
+// 添加完规则后可以在多个协程中并发使用
+type IgnoreMatcher struct {
+	root     string       // 根目录绝对路径
+	fileName string       // 嵌套忽略文件名，为空时不加载
+	extra    []ignoreRule // AddPatterns/AddFile 添加的规则（相对根目录）
+
+	mu     sync.Mutex
+	loaded map[string][]ignoreRule // 目录相对路径（/ 分隔，根目录为 ""）到该目录忽略文件规则的缓存
+}
+
+// ignoreRule 单条忽略规则
+type ignoreRule struct {
+	pattern globPattern // 编译后的模式（相对 base）
+	negate  bool        // 以 ! 开头，重新包含之前被忽略的条目
+	dirOnly bool        // 以 / 结尾，只匹配目录
+	base    string      // 规则所在忽略文件的目录（相对根目录，/ 分隔，根目录为 ""）
+}
+
+// NewIgnoreMatcher 创建忽略规则匹配器
+//
+// 参数:
+//   - root: 根目录，规则中的锚定路径相对于该目录
+//   - fileName: 各级目录中的忽略文件名（如 ".gitignore"），为空时只使用 AddPatterns/AddFile 添加的规则
+//
+// 返回:
+//   - *IgnoreMatcher: 匹配器
+//   - error: 根目录不存在或不是目录时返回错误
+//
+// 示例:
+//
+//	ignore, err := fs.NewIgnoreMatcher("project", ".gitignore")
+//	if err != nil {
+//	    return err
+//	}
+//	_ = ignore.AddPatterns(".git/")
+//	err = fs.CopyWithOptions("project", "dist", &fs.CopyOptions{Ignore: ignore})
+func NewIgnoreMatcher(root, fileName string) (*IgnoreMatcher, error) {
+	if root == "" {
+		return nil, fmt.Errorf("ignore root cannot be empty")
+	}
+	rootAbs, err := filepath.Abs(filepath.Clean(root))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for '%s': %w", root, err)
+	}
+	if !isDir(rootAbs) {
+		return nil, fmt.Errorf("ignore root '%s' is not a directory", rootAbs)
+	}
+
+	return &IgnoreMatcher{
+		root:     rootAbs,
+		fileName: fileName,
+		loaded:   make(map[string][]ignoreRule),
+	}, nil
+}
+
+// AddPatterns 添加相对根目录的忽略规则，语法与忽略文件的每一行相同
+//
+// 参数:
+//   - patterns: 忽略规则
+//
+// 返回:
+//   - error: 规则语法错误时返回错误
+func (m *IgnoreMatcher) AddPatterns(patterns ...string) error {
+	for _, line := range patterns {
+		rule, ok, err := parseIgnoreLine(line, "")
+		if err != nil {
+			return err
+		}
+		if ok {
+			m.extra = append(m.extra, rule)
+		}
+	}
+	return nil
+}
+
+// AddFile 读取忽略文件（如 .dockerignore），其中的规则相对根目录
+//
+// 参数:
+//   - path: 忽略文件路径
+//
+// 返回:
+//   - error: 读取失败或规则语法错误时返回错误
+func (m *IgnoreMatcher) AddFile(path string) error {
+	rules, err := readIgnoreFile(path, "")
+	if err != nil {
+		return err
+	}
+	m.extra = append(m.extra, rules...)
+	return nil
+}
+
+// Match 判断路径是否被忽略，父目录被忽略时其中的条目也被忽略（不能通过否定规则重新包含）
+//
+// 参数:
+//   - path: 绝对路径或相对根目录的路径，根目录以外的路径总是返回 false
+//   - isDir: 是否为目录（仅目录规则只匹配目录）
+//
+// 返回:
+//   - bool: 是否被忽略
+//   - error: 读取嵌套忽略文件失败时返回错误
+func (m *IgnoreMatcher) Match(path string, isDir bool) (bool, error) {
+	rel, ok := m.rel(path)
+	if !ok || rel == "" {
+		return false, nil
+	}
+
+	for i := 0; i < len(rel); i++ {
+		if rel[i] != '/' {
+			continue
+		}
+		ignored, err := m.matchRel(rel[:i], true)
+		if err != nil || ignored {
+			return ignored, err
+		}
+	}
+	return m.matchRel(rel, isDir)
+}
+
+// match 判断遍历中的条目是否被忽略，不检查父目录（遍历时被忽略的目录已被剪枝）
+//
+// 参数:
+//   - path: 绝对路径
+//   - isDir: 是否为目录
+//
+// 返回:
+//   - bool: 是否被忽略
+//   - error: 读取嵌套忽略文件失败时返回错误
+func (m *IgnoreMatcher) match(path string, isDir bool) (bool, error) {
+	rel, ok := m.rel(path)
+	if !ok || rel == "" {
+		return false, nil
+	}
+	return m.matchRel(rel, isDir)
+}
+
+// rel 返回相对根目录的路径（/ 分隔）
+func (m *IgnoreMatcher) rel(path string) (string, bool) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.root, path)
+	}
+	rel, err := filepath.Rel(m.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	if rel == "." {
+		return "", true
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// matchRel 按优先级依次应用规则，返回最后一条匹配规则的结果
+func (m *IgnoreMatcher) matchRel(rel string, isDir bool) (bool, error) {
+	ignored := false
+	apply := func(rules []ignoreRule) {
+		for _, r := range rules {
+			if r.match(rel, isDir) {
+				ignored = !r.negate
+			}
+		}
+	}
+
+	apply(m.extra)
+	if m.fileName == "" {
+		return ignored, nil
+	}
+
+	// 从根目录到父目录逐级应用忽略文件
+	rules, err := m.dirRules("")
+	if err != nil {
+		return false, err
+	}
+	apply(rules)
+	for i := 0; i < len(rel); i++ {
+		if rel[i] != '/' {
+			continue
+		}
+		rules, err := m.dirRules(rel[:i])
+		if err != nil {
+			return false, err
+		}
+		apply(rules)
+	}
+	return ignored, nil
+}
+
+// dirRules 返回目录中忽略文件的规则，首次访问时加载
+func (m *IgnoreMatcher) dirRules(dir string) ([]ignoreRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if rules, ok := m.loaded[dir]; ok {
+		return rules, nil
+	}
+	rules, err := readIgnoreFile(filepath.Join(m.root, filepath.FromSlash(dir), m.fileName), dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	m.loaded[dir] = rules
+	return rules, nil
+}
+
+// match 判断规则是否匹配相对根目录的路径
+func (r *ignoreRule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = rel[len(r.base)+1:]
+	}
+	return r.pattern.match(rel)
+}
+
+// readIgnoreFile 读取忽略文件中的规则
+//
+// 参数:
+//   - path: 忽略文件路径
+//   - base: 规则的基准目录（相对根目录）
+//
+// 返回:
+//   - []ignoreRule: 规则列表
+//   - error: 读取失败或规则语法错误时返回错误（文件不存在时错误满足 os.IsNotExist）
+func readIgnoreFile(path, base string) ([]ignoreRule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to open ignore file '%s': %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		rule, ok, err := parseIgnoreLine(scanner.Text(), base)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		if ok {
+			rules = append(rules, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ignore file '%s': %w", path, err)
+	}
+	return rules, nil
+}
+
+// parseIgnoreLine 解析忽略文件中的一行
+//
+// 参数:
+//   - line: 规则文本
+//   - base: 规则的基准目录
+//
+// 返回:
+//   - ignoreRule: 解析后的规则
+//   - bool: 是否为有效规则（空行和注释返回 false）
+//   - error: 规则语法错误时返回错误
+func parseIgnoreLine(line, base string) (ignoreRule, bool, error) {
+	line = strings.TrimSuffix(line, "\r")
+	// 去除未转义的行尾空格
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if line == "" || line[0] == '#' {
+		return ignoreRule{}, false, nil
+	}
+
+	rule := ignoreRule{base: base}
+	if line[0] == '!' {
+		rule.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false, nil
+	}
+
+	// 不含 / 的规则匹配任意层级的名称，含 / 的规则相对忽略文件所在目录
+	if strings.Contains(line, "/") {
+		line = strings.TrimPrefix(line, "/")
+	} else {
+		line = "**/" + line
+	}
+	// foo/** 只匹配 foo 中的内容，不匹配 foo 本身
+	if strings.HasSuffix(line, "/**") {
+		line += "/*"
+	}
+
+	// gitignore 不支持大括号，按字面匹配
+	pattern, err := compileGlob(escapeBraces(line))
+	if err != nil {
+		return ignoreRule{}, false, fmt.Errorf("invalid ignore pattern %q: %w", line, err)
+	}
+	rule.pattern = pattern
+	return rule, true, nil
+}
+
+// escapeBraces 转义模式中未转义的大括号
+func escapeBraces(pattern string) string {
+	if !strings.ContainsAny(pattern, "{}") {
+		return pattern
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '\\':
+			sb.WriteByte(c)
+			if i+1 < len(pattern) {
+				i++
+				sb.WriteByte(pattern[i])
+			}
+		case '{', '}':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
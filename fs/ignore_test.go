@@ -0,0 +1,226 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// setupIgnoreTree 创建带有嵌套 .gitignore 的测试目录
+func setupIgnoreTree(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	writeSyncFile(t, filepath.Join(root, ".gitignore"), "# comment\n*.log\n!keep.log\n/build/\ntmp/\ncache/**\n!cache/keep.txt\n")
+	writeSyncFile(t, filepath.Join(root, "main.go"), "package main")
+	writeSyncFile(t, filepath.Join(root, "app.log"), "log")
+	writeSyncFile(t, filepath.Join(root, "keep.log"), "keep")
+	writeSyncFile(t, filepath.Join(root, "build", "out.bin"), "bin")
+	writeSyncFile(t, filepath.Join(root, "cache", "a.txt"), "a")
+	writeSyncFile(t, filepath.Join(root, "cache", "keep.txt"), "keep")
+	writeSyncFile(t, filepath.Join(root, "src", "build", "gen.txt"), "generated")
+	writeSyncFile(t, filepath.Join(root, "src", "tmp"), "tmp file")
+	writeSyncFile(t, filepath.Join(root, "src", ".gitignore"), "*.go\n!main.go\n")
+	writeSyncFile(t, filepath.Join(root, "src", "main.go"), "package main")
+	writeSyncFile(t, filepath.Join(root, "src", "util.go"), "package src")
+	writeSyncFile(t, filepath.Join(root, "docs", "tmp", "draft.md"), "draft")
+	return root
+}
+
+func TestIgnoreMatcher(t *testing.T) {
+	root := setupIgnoreTree(t)
+	m, err := NewIgnoreMatcher(root, ".gitignore")
+	if err != nil {
+		t.Fatalf("NewIgnoreMatcher failed: %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"main.go", false, false},
+		{"app.log", false, true},
+		{"keep.log", false, false},         // 否定规则
+		{"build", true, true},              // 锚定的仅目录规则
+		{"build/out.bin", false, true},     // 父目录被忽略
+		{"src/build", true, false},         // 锚定规则不匹配子目录
+		{"src/tmp", false, false},          // 仅目录规则不匹配文件
+		{"docs/tmp", true, true},           // 不含 / 的规则匹配任意层级
+		{"docs/tmp/draft.md", false, true}, // 父目录被忽略
+		{"cache", true, false},             // cache/** 不匹配目录本身
+		{"cache/a.txt", false, true},
+		{"cache/keep.txt", false, false},
+		{"src/util.go", false, true},   // 嵌套忽略文件
+		{"src/main.go", false, false},  // 嵌套忽略文件中的否定规则
+		{"main2.go", false, false},     // 嵌套忽略文件不作用于父目录
+		{"src/sub/x.log", false, true}, // 根目录规则作用于子目录
+		{"", true, false},
+		{filepath.Join(root, "app.log"), false, true},
+		{filepath.Join(filepath.Dir(root), "app.log"), false, false}, // 根目录以外
+	}
+	for _, tt := range tests {
+		got, err := m.Match(tt.path, tt.isDir)
+		if err != nil {
+			t.Fatalf("Match(%q) failed: %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestIgnoreMatcherAddPatterns(t *testing.T) {
+	root := t.TempDir()
+	writeSyncFile(t, filepath.Join(root, ".dockerignore"), "node_modules\n*.md\n!README.md\n")
+	writeSyncFile(t, filepath.Join(root, ".gitignore"), "!notes.md\n")
+
+	m, err := NewIgnoreMatcher(root, ".gitignore")
+	if err != nil {
+		t.Fatalf("NewIgnoreMatcher failed: %v", err)
+	}
+	if err := m.AddFile(filepath.Join(root, ".dockerignore")); err != nil {
+		t.Fatalf("AddFile failed: %v", err)
+	}
+	if err := m.AddPatterns(".git/", `\#literal`, "trailing\\ "); err != nil {
+		t.Fatalf("AddPatterns failed: %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"node_modules", true, true},
+		{"a/node_modules", false, true},
+		{"CHANGELOG.md", false, true},
+		{"README.md", false, false},
+		{"notes.md", false, false}, // 忽略文件的规则优先于添加的规则
+		{".git", true, true},
+		{".git", false, false},
+		{"#literal", false, true},
+		{"trailing ", false, true},
+	}
+	for _, tt := range tests {
+		got, err := m.Match(tt.path, tt.isDir)
+		if err != nil {
+			t.Fatalf("Match(%q) failed: %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	if err := m.AddPatterns("[z-"); err == nil {
+		t.Error("AddPatterns with bad pattern: expected error")
+	}
+	if _, err := NewIgnoreMatcher(filepath.Join(root, "missing"), ""); err == nil {
+		t.Error("NewIgnoreMatcher with missing root: expected error")
+	}
+}
+
+func TestIgnoreIntegration(t *testing.T) {
+	root := setupIgnoreTree(t)
+	m, err := NewIgnoreMatcher(root, ".gitignore")
+	if err != nil {
+		t.Fatalf("NewIgnoreMatcher failed: %v", err)
+	}
+	want := []string{
+		".gitignore", "cache/keep.txt", "keep.log", "main.go",
+		"src/.gitignore", "src/build/gen.txt", "src/main.go", "src/tmp",
+	}
+
+	t.Run("Walk", func(t *testing.T) {
+		got := collectWalk(t, root, &WalkOptions{Types: WalkFiles, Sorted: true, Ignore: m})
+		if !slices.Equal(got, want) {
+			t.Errorf("Walk = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("CollectEx", func(t *testing.T) {
+		files, err := CollectEx(root, true, m)
+		if err != nil {
+			t.Fatalf("CollectEx failed: %v", err)
+		}
+		var got []string
+		for _, f := range files {
+			rel, _ := filepath.Rel(root, f)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("CollectEx = %v, want %v", got, want)
+		}
+
+		// 通配符匹配的路径同样应用忽略规则
+		files, err = CollectEx(filepath.Join(root, "*.log"), false, m)
+		if err != nil {
+			t.Fatalf("CollectEx with glob failed: %v", err)
+		}
+		if len(files) != 1 || filepath.Base(files[0]) != "keep.log" {
+			t.Errorf("CollectEx with glob = %v, want [keep.log]", files)
+		}
+
+		// 从根目录以外的工作目录使用相对通配符时，锚定规则仍按根目录匹配
+		t.Chdir(filepath.Dir(root))
+		files, err = CollectEx(filepath.Join(filepath.Base(root), "*"), true, m)
+		if err != nil {
+			t.Fatalf("CollectEx with relative glob failed: %v", err)
+		}
+		got = got[:0]
+		for _, f := range files {
+			got = append(got, filepath.ToSlash(strings.TrimPrefix(f, filepath.Base(root)+string(filepath.Separator))))
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("CollectEx with relative glob = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("CopyWithOptions", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "dst")
+		if err := CopyWithOptions(root, dst, &CopyOptions{Ignore: m}); err != nil {
+			t.Fatalf("CopyWithOptions failed: %v", err)
+		}
+		got := collectWalk(t, dst, &WalkOptions{Types: WalkFiles, Sorted: true})
+		if !slices.Equal(got, want) {
+			t.Errorf("copied files = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("GetSizeEx", func(t *testing.T) {
+		var wantSize int64
+		for _, rel := range want {
+			info, err := os.Stat(filepath.Join(root, rel))
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantSize += info.Size()
+		}
+		size, err := GetSizeEx(root, m)
+		if err != nil {
+			t.Fatalf("GetSizeEx failed: %v", err)
+		}
+		if size != wantSize {
+			t.Errorf("GetSizeEx = %d, want %d", size, wantSize)
+		}
+	})
+
+	t.Run("SyncDelete", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "dst")
+		writeSyncFile(t, filepath.Join(dst, "build", "old.bin"), "old")
+		writeSyncFile(t, filepath.Join(dst, "stale.txt"), "stale")
+		if _, err := Sync(root, dst, &SyncOptions{Delete: true, CopyOptions: CopyOptions{Ignore: m}}); err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+		// 被忽略的目录受保护，不会被删除
+		if !Exists(filepath.Join(dst, "build", "old.bin")) {
+			t.Error("ignored build/old.bin was deleted")
+		}
+		if Exists(filepath.Join(dst, "stale.txt")) {
+			t.Error("stale.txt was not deleted")
+		}
+	})
+}
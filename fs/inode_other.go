@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package fs
+
+import "os"
+
+// fileInode 其他平台上 os.FileInfo 不包含 inode 号，不支持识别硬链接
+func fileInode(info os.FileInfo) (inodeKey, uint64, bool) {
+	return inodeKey{}, 0, false
+}
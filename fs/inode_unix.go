@@ -0,0 +1,25 @@
+//go:build unix
+
+package fs
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileInode 返回文件的设备号和 inode 号以及硬链接数
+//
+// 参数:
+//   - info: 文件信息（Lstat 结果）
+//
+// 返回:
+//   - inodeKey: 设备号和 inode 号
+//   - uint64: 硬链接数
+//   - bool: 是否获取成功
+func fileInode(info os.FileInfo) (inodeKey, uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return inodeKey{}, 0, false
+	}
+	return inodeKey{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}, uint64(stat.Nlink), true
+}
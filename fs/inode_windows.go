@@ -0,0 +1,10 @@
+//go:build windows
+
+package fs
+
+import "os"
+
+// fileInode Windows 上 os.FileInfo 不包含文件索引号，不支持识别硬链接
+func fileInode(info os.FileInfo) (inodeKey, uint64, bool) {
+	return inodeKey{}, 0, false
+}
@@ -0,0 +1,350 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrLocked 表示锁已被其他持有者占用
+var ErrLocked = errors.New("file is locked")
+
+// ErrLockUnsupported 表示当前平台不支持文件锁
+var ErrLockUnsupported = errors.New("file locking not supported")
+
+// 等待锁时的轮询间隔范围
+const (
+	lockPollMin = 10 * time.Millisecond
+	lockPollMax = 500 * time.Millisecond
+)
+
+// LockMode 文件锁模式
+type LockMode int
+
+const (
+	LockExclusive LockMode = iota // 排他锁（写锁），同一时刻只有一个持有者
+	LockShared                    // 共享锁（读锁），可以有多个持有者，与排他锁互斥
+)
+
+// lockModeNames 锁模式名称
+var lockModeNames = map[LockMode]string{
+	LockExclusive: "exclusive",
+	LockShared:    "shared",
+}
+
+// String 返回锁模式名称
+func (m LockMode) String() string {
+	if name, ok := lockModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("LockMode(%d)", int(m))
+}
+
+// FileLock 基于 flock 的建议性文件锁（仅 Unix，AIX、Windows 等其他平台返回 ErrLockUnsupported）
+// 锁属于打开的文件句柄：进程退出时由内核自动释放，同一进程内的不同 FileLock 之间同样互斥
+// 锁文件不存在时自动创建，加锁后会确认锁文件未被删除或替换
+// 单个 FileLock 同一时刻只能持有一把锁，需要在多个协程中并发加锁时各自创建 FileLock
+type FileLock struct {
+	path string
+
+	mu   sync.Mutex
+	f    *os.File // 持有锁的文件句柄，未加锁时为 nil
+	mode LockMode
+}
+
+// NewFileLock 创建文件锁，此时不会打开或创建锁文件
+//
+// 参数:
+//   - path: 锁文件路径，父目录必须存在
+//
+// 返回:
+//   - *FileLock: 文件锁
+//
+// 示例:
+//
+//	lock := fs.NewFileLock("/var/run/app/state.lock")
+//	if err := lock.Lock(fs.LockExclusive); err != nil {
+//	    return err
+//	}
+//	defer lock.Unlock()
+func NewFileLock(path string) *FileLock {
+	return &FileLock{path: path}
+}
+
+// Path 返回锁文件路径
+func (l *FileLock) Path() string {
+	return l.path
+}
+
+// Lock 加锁，锁被占用时阻塞等待
+//
+// 参数:
+//   - mode: 锁模式
+//
+// 返回:
+//   - error: 已持有锁、打开锁文件失败或加锁失败时返回错误
+func (l *FileLock) Lock(mode LockMode) error {
+	_, err := l.lock(mode, true)
+	return err
+}
+
+// TryLock 尝试加锁，不等待
+//
+// 参数:
+//   - mode: 锁模式
+//
+// 返回:
+//   - bool: 是否加锁成功，锁被占用时返回 false
+//   - error: 已持有锁、打开锁文件失败或加锁失败时返回错误
+func (l *FileLock) TryLock(mode LockMode) (bool, error) {
+	return l.lock(mode, false)
+}
+
+// LockContext 加锁，锁被占用时轮询等待直到成功或 ctx 结束
+//
+// 参数:
+//   - ctx: 上下文，可通过 context.WithTimeout 设置等待超时
+//   - mode: 锁模式
+//
+// 返回:
+//   - error: 加锁失败时返回错误，ctx 结束时返回包装了 ErrLocked 和 ctx.Err() 的错误
+//
+// 示例:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	if err := lock.LockContext(ctx, fs.LockShared); err != nil {
+//	    return err // errors.Is(err, fs.ErrLocked) && errors.Is(err, context.DeadlineExceeded)
+//	}
+func (l *FileLock) LockContext(ctx context.Context, mode LockMode) error {
+	wait := lockPollMin
+	for {
+		ok, err := l.TryLock(mode)
+		if err != nil || ok {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: '%s': %w", ErrLocked, l.path, ctx.Err())
+		case <-timer.C:
+		}
+		wait = min(wait*2, lockPollMax)
+	}
+}
+
+// lock 打开锁文件并加锁，加锁后确认路径仍指向同一文件（锁文件可能在等待期间被持有者删除）
+//
+// 参数:
+//   - mode: 锁模式
+//   - block: 锁被占用时是否阻塞等待
+//
+// 返回:
+//   - bool: 是否加锁成功
+//   - error: 加锁失败时返回错误
+func (l *FileLock) lock(mode LockMode, block bool) (bool, error) {
+	if _, ok := lockModeNames[mode]; !ok {
+		return false, fmt.Errorf("invalid lock mode: %v", mode)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f != nil {
+		return false, fmt.Errorf("lock '%s' is already held (%v)", l.path, l.mode)
+	}
+
+	for {
+		f, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0o644)
+		if err != nil {
+			return false, fmt.Errorf("failed to open lock file '%s': %w", l.path, err)
+		}
+		ok, err := flock(f, mode, block)
+		if err != nil || !ok {
+			_ = f.Close()
+			if err != nil {
+				return false, fmt.Errorf("failed to lock '%s': %w", l.path, err)
+			}
+			return false, nil
+		}
+
+		fileInfo, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return false, fmt.Errorf("failed to get lock file info '%s': %w", l.path, err)
+		}
+		pathInfo, err := os.Stat(l.path)
+		if err == nil && os.SameFile(fileInfo, pathInfo) {
+			l.f, l.mode = f, mode
+			return true, nil
+		}
+		_ = f.Close()
+		if err != nil && !os.IsNotExist(err) {
+			return false, fmt.Errorf("failed to get lock file info '%s': %w", l.path, err)
+		}
+		// 锁文件已被删除或替换，重新打开
+	}
+}
+
+// Unlock 释放锁，未持有锁时不做任何操作
+//
+// 返回:
+//   - error: 释放失败时返回错误
+func (l *FileLock) Unlock() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.unlock()
+}
+
+// unlock 释放锁并关闭文件句柄，调用方需持有 l.mu
+func (l *FileLock) unlock() error {
+	if l.f == nil {
+		return nil
+	}
+	f := l.f
+	l.f = nil
+
+	err := funlock(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unlock '%s': %w", l.path, err)
+	}
+	return nil
+}
+
+// PIDLock 记录持有者进程号的排他锁文件，用于保证同一时刻只有一个进程实例运行
+// 持有者崩溃后锁由内核释放，遗留的锁文件被视为过期并由下一个进程接管
+type PIDLock struct {
+	lock *FileLock
+}
+
+// AcquirePIDLock 获取 PID 锁并将当前进程号写入锁文件，不等待
+//
+// 参数:
+//   - path: 锁文件路径
+//
+// 返回:
+//   - *PIDLock: PID 锁
+//   - error: 锁被其他进程持有时返回包装了 ErrLocked 的错误（包含持有者进程号），其他失败返回相应错误
+//
+// 示例:
+//
+//	lock, err := fs.AcquirePIDLock("/var/run/app.pid")
+//	if errors.Is(err, fs.ErrLocked) {
+//	    return fmt.Errorf("another instance is running: %w", err)
+//	}
+//	defer lock.Release()
+func AcquirePIDLock(path string) (*PIDLock, error) {
+	l := NewFileLock(path)
+	ok, err := l.TryLock(LockExclusive)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if pid, _, err := LockHolder(path); err == nil && pid > 0 {
+			return nil, fmt.Errorf("%w: '%s' is held by process %d", ErrLocked, path, pid)
+		}
+		return nil, fmt.Errorf("%w: '%s'", ErrLocked, path)
+	}
+	return newPIDLock(l)
+}
+
+// AcquirePIDLockContext 获取 PID 锁，锁被占用时轮询等待直到成功或 ctx 结束
+//
+// 参数:
+//   - ctx: 上下文
+//   - path: 锁文件路径
+//
+// 返回:
+//   - *PIDLock: PID 锁
+//   - error: 获取失败时返回错误，ctx 结束时返回包装了 ErrLocked 和 ctx.Err() 的错误
+func AcquirePIDLockContext(ctx context.Context, path string) (*PIDLock, error) {
+	l := NewFileLock(path)
+	if err := l.LockContext(ctx, LockExclusive); err != nil {
+		return nil, err
+	}
+	return newPIDLock(l)
+}
+
+// newPIDLock 将当前进程号写入已加锁的锁文件（覆盖过期的内容）
+func newPIDLock(l *FileLock) (*PIDLock, error) {
+	pid := []byte(strconv.Itoa(os.Getpid()) + "\n")
+	err := l.f.Truncate(0)
+	if err == nil {
+		_, err = l.f.WriteAt(pid, 0)
+	}
+	if err == nil {
+		err = l.f.Sync()
+	}
+	if err != nil {
+		_ = l.Unlock()
+		return nil, fmt.Errorf("failed to write pid to lock file '%s': %w", l.path, err)
+	}
+	return &PIDLock{lock: l}, nil
+}
+
+// Path 返回锁文件路径
+func (p *PIDLock) Path() string {
+	return p.lock.path
+}
+
+// Release 删除锁文件并释放锁，重复调用不做任何操作
+// 删除在释放锁之前完成，等待中的进程加锁后会发现文件已被删除并重新创建
+//
+// 返回:
+//   - error: 删除锁文件或释放锁失败时返回错误
+func (p *PIDLock) Release() error {
+	l := p.lock
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+
+	var errs []error
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		errs = append(errs, fmt.Errorf("failed to remove lock file '%s': %w", l.path, err))
+	}
+	errs = append(errs, l.unlock())
+	return errors.Join(errs...)
+}
+
+// LockHolder 读取 PID 锁文件中记录的进程号，并检测锁是否仍被持有
+// 锁文件存在但未被持有（持有者已退出）时为过期锁，可以安全地被接管或删除
+//
+// 参数:
+//   - path: 锁文件路径
+//
+// 返回:
+//   - int: 记录的进程号，文件为空或内容无效时为 0
+//   - bool: 锁是否仍被持有
+//   - error: 锁文件不存在或读取失败时返回错误
+func LockHolder(path string) (int, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read lock file '%s': %w", path, err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid < 0 {
+		pid = 0
+	}
+
+	// 能获取共享锁说明没有进程持有排他锁
+	probe := NewFileLock(path)
+	ok, err := probe.TryLock(LockShared)
+	if err != nil {
+		return pid, false, err
+	}
+	if ok {
+		_ = probe.Unlock()
+	}
+	return pid, !ok, nil
+}
@@ -0,0 +1,15 @@
+//go:build (!unix || aix) && !windows
+
+package fs
+
+import "os"
+
+// flock 当前平台不支持 flock，文件锁不可用
+func flock(f *os.File, mode LockMode, block bool) (bool, error) {
+	return false, ErrLockUnsupported
+}
+
+// funlock 当前平台不支持 flock，文件锁不可用
+func funlock(f *os.File) error {
+	return ErrLockUnsupported
+}
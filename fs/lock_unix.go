@@ -0,0 +1,49 @@
+//go:build unix && !aix
+
+package fs
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// flock 对文件加 flock 锁
+//
+// 参数:
+//   - f: 锁文件
+//   - mode: 锁模式
+//   - block: 锁被占用时是否阻塞等待
+//
+// 返回:
+//   - bool: 是否加锁成功，非阻塞模式下锁被占用时返回 false
+//   - error: 加锁失败时返回错误
+func flock(f *os.File, mode LockMode, block bool) (bool, error) {
+	how := unix.LOCK_EX
+	if mode == LockShared {
+		how = unix.LOCK_SH
+	}
+	if !block {
+		how |= unix.LOCK_NB
+	}
+
+	for {
+		err := unix.Flock(int(f.Fd()), how)
+		switch {
+		case err == nil:
+			return true, nil
+		case errors.Is(err, unix.EINTR):
+			continue
+		case errors.Is(err, unix.EWOULDBLOCK):
+			return false, nil
+		default:
+			return false, err
+		}
+	}
+}
+
+// funlock 释放文件上的 flock 锁
+func funlock(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_UN)
+}
@@ -0,0 +1,133 @@
+//go:build linux || darwin
+
+package fs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.lock")
+
+	a, b := NewFileLock(path), NewFileLock(path)
+	if err := a.Lock(LockShared); err != nil {
+		t.Fatalf("Lock(shared) failed: %v", err)
+	}
+	if err := a.Lock(LockShared); err == nil {
+		t.Error("Lock while held: expected error")
+	}
+
+	// 共享锁之间兼容，与排他锁互斥
+	if ok, err := b.TryLock(LockShared); err != nil || !ok {
+		t.Fatalf("TryLock(shared) = %v, %v, want true", ok, err)
+	}
+	if err := b.Unlock(); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if ok, err := b.TryLock(LockExclusive); err != nil || ok {
+		t.Fatalf("TryLock(exclusive) = %v, %v, want false", ok, err)
+	}
+
+	// 持有者释放后等待者获得锁
+	done := make(chan error, 1)
+	go func() { done <- b.Lock(LockExclusive) }()
+	time.Sleep(50 * time.Millisecond)
+	if err := a.Unlock(); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("blocking Lock failed: %v", err)
+	}
+	if ok, err := a.TryLock(LockShared); err != nil || ok {
+		t.Errorf("TryLock(shared) while exclusive held = %v, %v, want false", ok, err)
+	}
+	if err := b.Unlock(); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if err := b.Unlock(); err != nil {
+		t.Errorf("Unlock when not held: %v", err)
+	}
+}
+
+func TestFileLockContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.lock")
+
+	holder := NewFileLock(path)
+	if err := holder.Lock(LockExclusive); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	waiter := NewFileLock(path)
+	err := waiter.LockContext(ctx, LockShared)
+	if !errors.Is(err, ErrLocked) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("LockContext err = %v, want ErrLocked and DeadlineExceeded", err)
+	}
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = holder.Unlock()
+	}()
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel2()
+	if err := waiter.LockContext(ctx2, LockShared); err != nil {
+		t.Fatalf("LockContext failed: %v", err)
+	}
+	_ = waiter.Unlock()
+}
+
+func TestPIDLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.pid")
+
+	lock, err := AcquirePIDLock(path)
+	if err != nil {
+		t.Fatalf("AcquirePIDLock failed: %v", err)
+	}
+	pid, held, err := LockHolder(path)
+	if err != nil {
+		t.Fatalf("LockHolder failed: %v", err)
+	}
+	if pid != os.Getpid() || !held {
+		t.Errorf("LockHolder = %d, %v, want %d, true", pid, held, os.Getpid())
+	}
+
+	_, err = AcquirePIDLock(path)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("second AcquirePIDLock err = %v, want ErrLocked", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if Exists(path) {
+		t.Error("lock file not removed after Release")
+	}
+	if err := lock.Release(); err != nil {
+		t.Errorf("second Release: %v", err)
+	}
+
+	// 崩溃进程遗留的锁文件：记录的进程号存在但锁未被持有
+	writeSyncFile(t, path, strconv.Itoa(1<<22)+"\n")
+	pid, held, err = LockHolder(path)
+	if err != nil {
+		t.Fatalf("LockHolder failed: %v", err)
+	}
+	if pid != 1<<22 || held {
+		t.Errorf("LockHolder on stale file = %d, %v, want %d, false", pid, held, 1<<22)
+	}
+	lock, err = AcquirePIDLockContext(context.Background(), path)
+	if err != nil {
+		t.Fatalf("AcquirePIDLockContext on stale file failed: %v", err)
+	}
+	defer func() { _ = lock.Release() }()
+	if pid, _, _ := LockHolder(path); pid != os.Getpid() {
+		t.Errorf("pid after takeover = %d, want %d", pid, os.Getpid())
+	}
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package fs
+
+import "os"
+
+// flock Windows 暂不支持文件锁
+func flock(f *os.File, mode LockMode, block bool) (bool, error) {
+	return false, ErrLockUnsupported
+}
+
+// funlock Windows 暂不支持文件锁
+func funlock(f *os.File) error {
+	return ErrLockUnsupported
+}
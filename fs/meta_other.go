@@ -0,0 +1,31 @@
+//go:build !linux && !darwin && !windows
+
+package fs
+
+import "os"
+
+// lchown 其他平台暂不支持保留所有者，直接返回
+func lchown(path string, info os.FileInfo) error {
+	return nil
+}
+
+// copyXattrs 其他平台暂不支持扩展属性，直接返回
+func copyXattrs(src, dst string) error {
+	return nil
+}
+
+// setFileTimes 将源文件的修改时间应用到目标路径（访问时间与修改时间相同）
+// 不支持设置符号链接本身的时间，符号链接直接返回
+//
+// 参数:
+//   - path: 目标路径
+//   - info: 源文件信息
+//
+// 返回:
+//   - error: 设置失败时返回错误
+func setFileTimes(path string, info os.FileInfo) error {
+	if info.Mode()&os.ModeSymlink != 0 {
+		return nil
+	}
+	return os.Chtimes(path, fileAtime(info), info.ModTime())
+}
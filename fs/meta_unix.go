@@ -0,0 +1,135 @@
+//go:build linux || darwin
+
+package fs
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// lchown 将源文件的所有者和所属组应用到目标路径（不跟随符号链接）
+// 非 root 用户通常无权修改所有者，此时忽略错误（与 cp -a 一致）
+//
+// 参数:
+//   - path: 目标路径
+//   - info: 源文件信息
+//
+// 返回:
+//   - error: 修改失败时返回错误
+func lchown(path string, info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	err := os.Lchown(path, int(stat.Uid), int(stat.Gid))
+	if err != nil && errors.Is(err, os.ErrPermission) && os.Geteuid() != 0 {
+		return nil
+	}
+	return err
+}
+
+// copyXattrs 复制源路径的所有扩展属性到目标路径（不跟随符号链接）
+// 源或目标文件系统不支持扩展属性、或无权设置某个属性（如 security.* 命名空间）时跳过
+//
+// 参数:
+//   - src: 源路径
+//   - dst: 目标路径
+//
+// 返回:
+//   - error: 读取或设置扩展属性失败时返回错误
+func copyXattrs(src, dst string) error {
+	names, err := listXattrs(src)
+	if err != nil {
+		if isXattrUnsupported(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, name := range names {
+		value, err := getXattr(src, name)
+		if err != nil {
+			if isXattrUnsupported(err) || errors.Is(err, unix.ENODATA) {
+				continue
+			}
+			return err
+		}
+		if err := unix.Lsetxattr(dst, name, value, 0); err != nil {
+			if isXattrUnsupported(err) || errors.Is(err, unix.EPERM) || errors.Is(err, unix.EACCES) {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// listXattrs 列出路径上的所有扩展属性名称
+func listXattrs(path string) ([]string, error) {
+	for {
+		size, err := unix.Llistxattr(path, nil)
+		if err != nil || size == 0 {
+			return nil, err
+		}
+		buf := make([]byte, size)
+		n, err := unix.Llistxattr(path, buf)
+		if errors.Is(err, unix.ERANGE) {
+			continue // 属性列表在两次调用之间变大，重试
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var names []string
+		for _, name := range strings.Split(string(buf[:n]), "\x00") {
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+		return names, nil
+	}
+}
+
+// getXattr 读取路径上指定扩展属性的值
+func getXattr(path, name string) ([]byte, error) {
+	for {
+		size, err := unix.Lgetxattr(path, name, nil)
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size)
+		n, err := unix.Lgetxattr(path, name, buf)
+		if errors.Is(err, unix.ERANGE) {
+			continue // 属性值在两次调用之间变大，重试
+		}
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	}
+}
+
+// isXattrUnsupported 判断错误是否表示文件系统不支持扩展属性
+func isXattrUnsupported(err error) bool {
+	return errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EOPNOTSUPP)
+}
+
+// setFileTimes 将源文件的访问时间和修改时间应用到目标路径（不跟随符号链接）
+//
+// 参数:
+//   - path: 目标路径
+//   - info: 源文件信息
+//
+// 返回:
+//   - error: 设置失败时返回错误
+func setFileTimes(path string, info os.FileInfo) error {
+	times := []unix.Timespec{
+		unix.NsecToTimespec(fileAtime(info).UnixNano()),
+		unix.NsecToTimespec(info.ModTime().UnixNano()),
+	}
+	return unix.UtimesNanoAt(unix.AT_FDCWD, path, times, unix.AT_SYMLINK_NOFOLLOW)
+}
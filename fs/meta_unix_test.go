@@ -0,0 +1,83 @@
+//go:build linux || darwin
+
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestCopyWithOptionsXattrs(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src.txt")
+	if err := os.WriteFile(src, []byte("data"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := unix.Lsetxattr(src, "user.gokit.test", []byte("value"), 0); err != nil {
+		if errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EOPNOTSUPP) {
+			t.Skip("Filesystem does not support user xattrs")
+		}
+		t.Fatalf("Failed to set xattr: %v", err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "dst.txt")
+	if err := CopyWithOptions(src, dst, &CopyOptions{PreserveXattrs: true}); err != nil {
+		t.Fatalf("CopyWithOptions failed: %v", err)
+	}
+
+	value, err := getXattr(dst, "user.gokit.test")
+	if err != nil || string(value) != "value" {
+		t.Errorf("xattr = %q, %v; want %q", value, err, "value")
+	}
+}
+
+func TestCopyWithOptionsOwnerAndSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target.txt")
+	if err := os.WriteFile(target, []byte("data"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink("target.txt", link); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	// 只修改链接本身的时间
+	mtime := time.Date(2019, 5, 6, 7, 8, 9, 0, time.UTC)
+	ts := []unix.Timespec{unix.NsecToTimespec(mtime.UnixNano()), unix.NsecToTimespec(mtime.UnixNano())}
+	if err := unix.UtimesNanoAt(unix.AT_FDCWD, link, ts, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		t.Fatalf("Failed to set symlink times: %v", err)
+	}
+	if os.Geteuid() == 0 {
+		if err := os.Lchown(link, 1234, 5678); err != nil {
+			t.Fatalf("Failed to chown symlink: %v", err)
+		}
+	}
+
+	dst := filepath.Join(t.TempDir(), "link")
+	if err := CopyWithOptions(link, dst, ArchiveOptions()); err != nil {
+		t.Fatalf("CopyWithOptions failed: %v", err)
+	}
+
+	info, err := os.Lstat(dst)
+	if err != nil {
+		t.Fatalf("Lstat failed: %v", err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Fatal("Destination is not a symlink")
+	}
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("Symlink mtime = %v, want %v", info.ModTime(), mtime)
+	}
+	if os.Geteuid() == 0 {
+		stat := info.Sys().(*syscall.Stat_t)
+		if stat.Uid != 1234 || stat.Gid != 5678 {
+			t.Errorf("Owner = %d:%d, want 1234:5678", stat.Uid, stat.Gid)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package fs
+
+import "os"
+
+// lchown Windows 不支持 Unix 所有者，直接返回
+func lchown(path string, info os.FileInfo) error {
+	return nil
+}
+
+// copyXattrs Windows 不支持扩展属性，直接返回
+func copyXattrs(src, dst string) error {
+	return nil
+}
+
+// setFileTimes 将源文件的访问时间和修改时间应用到目标路径
+// Windows 上符号链接按普通文件复制，不单独设置链接本身的时间
+//
+// 参数:
+//   - path: 目标路径
+//   - info: 源文件信息
+//
+// 返回:
+//   - error: 设置失败时返回错误
+func setFileTimes(path string, info os.FileInfo) error {
+	if info.Mode()&os.ModeSymlink != 0 {
+		return nil
+	}
+	return os.Chtimes(path, fileAtime(info), info.ModTime())
+}
@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"context"
 	"fmt"
 	"os"
 )
@@ -39,7 +40,26 @@ func Move(src, dst string) error {
 // 移动策略:
 //  1. 优先使用 os.Rename（原子操作，同文件系统内高效）
 //  2. rename 失败时降级使用 CopyEx + os.RemoveAll（支持跨文件系统）
-func MoveEx(src, dst string, overwrite bool) (err error) {
+func MoveEx(src, dst string, overwrite bool) error {
+	return MoveContext(context.Background(), src, dst, &CopyOptions{Overwrite: overwrite})
+}
+
+// MoveContext 按选项移动文件或目录，支持通过 ctx 取消和进度回调
+// 移动规则与 MoveEx 相同，opts 用于降级为复制+删除时的复制过程
+//
+// 参数:
+//   - ctx: 上下文，取消后复制在下一个文件或数据块处停止，源路径保持不变
+//   - src: 源路径 (支持文件、目录、符号链接、特殊文件)
+//   - dst: 目标路径（支持文件、目录，自动创建父目录）
+//   - opts: 复制选项（Overwrite 控制是否覆盖），为 nil 时使用默认选项
+//
+// 返回:
+//   - error: 移动失败时返回错误，取消时返回的错误包装了 ctx.Err()
+//
+// 注意:
+//   - os.Rename 成功时不复制数据，不会触发进度回调
+//   - 源目录设置了过滤条件时不使用 os.Rename，只移动匹配的内容，被过滤的内容保留在源目录中
+func MoveContext(ctx context.Context, src, dst string, opts *CopyOptions) (err error) {
 	// 捕获 panic 并转换为错误
 	defer func() {
 		if r := recover(); r != nil {
@@ -62,17 +82,53 @@ func MoveEx(src, dst string, overwrite bool) (err error) {
 	// 智能路径处理：如果目标是已存在的目录，自动追加源文件名/目录名
 	dstAbs = resolveDestinationPathAbs(srcAbs, dstAbs)
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c := newCopier(ctx, opts)
+	if err := c.opts.validate(); err != nil {
+		return err
+	}
+
+	return c.move(srcAbs, dstAbs)
+}
+
+// move 执行移动，接受已验证的绝对路径
+// 优先使用 os.Rename，失败时降级为复制+删除；设置了过滤条件的目录只移动匹配的内容
+//
+// 参数:
+//   - srcAbs: 已验证的源绝对路径
+//   - dstAbs: 已验证的目标绝对路径（已通过智能路径处理）
+//
+// 返回:
+//   - error: 移动失败时返回错误
+func (c *copier) move(srcAbs, dstAbs string) error {
+	// 设置了过滤条件的目录只能逐项移动：复制匹配的内容后删除对应的源条目
+	if c.opts.hasFilters() && isDir(srcAbs) {
+		c.track = true
+		if err := c.scanTotals(srcAbs); err != nil {
+			return fmt.Errorf("failed to copy '%s' to '%s': %w", srcAbs, dstAbs, err)
+		}
+		if err := c.copy(srcAbs, dstAbs); err != nil {
+			return fmt.Errorf("failed to copy '%s' to '%s': %w", srcAbs, dstAbs, err)
+		}
+		return removeCopiedSources(srcAbs, c.copiedFiles, c.copiedDirs)
+	}
+
 	// 策略1：优先尝试 os.Rename（同文件系统内）
 	// os.Rename 是原子操作，且只改变文件系统的元数据，不复制数据
-	renameErr := tryRename(srcAbs, dstAbs, overwrite)
+	renameErr := tryRename(srcAbs, dstAbs, c.opts.Overwrite)
 	if renameErr == nil {
 		// rename 成功，直接返回
 		return nil
 	}
 
 	// 策略2：rename 失败，降级使用复制+删除（跨文件系统场景）
-	// 先执行复制操作（使用内部函数避免重复验证）
-	if err := copyExInternal(srcAbs, dstAbs, overwrite); err != nil {
+	// 先执行复制操作（使用内部函数避免重复验证），复制失败或取消时源路径保持不变
+	if err := c.scanTotals(srcAbs); err != nil {
+		return fmt.Errorf("failed to copy '%s' to '%s': %w", srcAbs, dstAbs, err)
+	}
+	if err := c.copy(srcAbs, dstAbs); err != nil {
 		return fmt.Errorf("failed to copy '%s' to '%s': %w", srcAbs, dstAbs, err)
 	}
 
@@ -86,6 +142,7 @@ func MoveEx(src, dst string, overwrite bool) (err error) {
 
 // tryRename 尝试使用 os.Rename 移动文件/目录
 // 适用于同文件系统内的快速移动（原子操作）
+// 已存在的目标先备份，rename 成功后删除备份，失败时恢复
 //
 // 参数:
 //   - srcAbs: 源绝对路径
@@ -95,23 +152,18 @@ func MoveEx(src, dst string, overwrite bool) (err error) {
 // 返回:
 //   - error: rename 失败时返回错误，成功返回 nil
 func tryRename(srcAbs, dstAbs string, overwrite bool) error {
-	// 检查目标是否存在
-	_, err := os.Lstat(dstAbs)
-	if err == nil {
-		// 目标存在
-		if !overwrite {
-			return fmt.Errorf("destination '%s' already exists", dstAbs)
-		}
-		// 允许覆盖，删除目标
-		if err := os.RemoveAll(dstAbs); err != nil {
-			return fmt.Errorf("failed to remove destination '%s' for overwrite: %w", dstAbs, err)
-		}
+	// 安全覆盖机制：处理已存在的目标
+	backupPath, err := handleBackupAndRestore(dstAbs, overwrite)
+	if err != nil {
+		return err
 	}
 
 	// 尝试 rename
 	if err := os.Rename(srcAbs, dstAbs); err != nil {
+		restoreBackup(dstAbs, backupPath)
 		return fmt.Errorf("rename failed: %w", err)
 	}
 
+	cleanupBackup(backupPath)
 	return nil
 }
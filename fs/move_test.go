@@ -728,6 +728,30 @@ func TestMoveCrossFilesystem(t *testing.T) {
 	validateFileMove(t, srcFile, dstFile, expectedContent)
 }
 
+// TestTryRenameRestore 测试 rename 失败时恢复被覆盖的目标
+func TestTryRenameRestore(t *testing.T) {
+	testDir := t.TempDir()
+	srcDir := filepath.Join(testDir, "src")
+	dstFile := filepath.Join(srcDir, "dst.txt")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dstFile, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 不能将目录移动到其自身内部，rename 必定失败
+	if err := tryRename(srcDir, dstFile, true); err == nil {
+		t.Fatal("tryRename into itself: expected error")
+	}
+	if content, err := os.ReadFile(dstFile); err != nil || string(content) != "keep" {
+		t.Errorf("destination not restored: %q, %v", content, err)
+	}
+	if entries, _ := os.ReadDir(srcDir); len(entries) != 1 {
+		t.Errorf("backup left behind: %v", entries)
+	}
+}
+
 // TestMovePermission 测试权限保留
 func TestMovePermission(t *testing.T) {
 	if runtime.GOOS == "windows" {
@@ -0,0 +1,410 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ErrPlanStale 表示执行计划时文件系统已与制定计划时不同
+var ErrPlanStale = errors.New("plan is stale")
+
+// OpKind 计划中的操作类型
+type OpKind int
+
+const (
+	OpCreateDir    OpKind = iota // 创建目录
+	OpCopyFile                   // 复制普通文件
+	OpOverwrite                  // 替换已存在的目标（先备份，成功后删除备份，失败时恢复）
+	OpSymlink                    // 创建符号链接
+	OpSpecial                    // 重建设备文件或命名管道
+	OpHardlink                   // 创建指向已复制文件的硬链接
+	OpSkip                       // 跳过（套接字）
+	OpRename                     // 使用 os.Rename 移动（同文件系统）
+	OpRemoveSource               // 删除源路径（目录仅在为空时删除）
+)
+
+// opKindNames 操作类型与名称的对应关系
+var opKindNames = map[OpKind]string{
+	OpCreateDir:    "mkdir",
+	OpCopyFile:     "copy",
+	OpOverwrite:    "overwrite",
+	OpSymlink:      "symlink",
+	OpSpecial:      "mknod",
+	OpHardlink:     "link",
+	OpSkip:         "skip",
+	OpRename:       "rename",
+	OpRemoveSource: "remove",
+}
+
+// String 返回操作类型的名称
+func (k OpKind) String() string {
+	if name, ok := opKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("OpKind(%d)", int(k))
+}
+
+// PlanOp 计划中的单个操作
+type PlanOp struct {
+	Kind   OpKind // 操作类型
+	Src    string // 源路径（OpCreateDir、OpOverwrite 可能为空）
+	Dst    string // 目标路径（OpRemoveSource 为空）
+	Target string // 符号链接内容或硬链接指向的目标路径
+	Size   int64  // 需要复制的字节数（仅 OpCopyFile）
+}
+
+// String 返回操作的可读描述
+func (op PlanOp) String() string {
+	switch op.Kind {
+	case OpCreateDir, OpOverwrite:
+		return fmt.Sprintf("%-9s %s", op.Kind, op.Dst)
+	case OpCopyFile:
+		return fmt.Sprintf("%-9s %s -> %s (%d bytes)", op.Kind, op.Src, op.Dst, op.Size)
+	case OpSymlink, OpHardlink:
+		return fmt.Sprintf("%-9s %s -> %s", op.Kind, op.Dst, op.Target)
+	case OpSkip, OpRemoveSource:
+		return fmt.Sprintf("%-9s %s", op.Kind, op.Src)
+	default:
+		return fmt.Sprintf("%-9s %s -> %s", op.Kind, op.Src, op.Dst)
+	}
+}
+
+// Plan 复制或移动的执行计划
+// 由 PlanCopy/PlanMove 生成，制定计划时只读取文件系统信息，不做任何修改
+type Plan struct {
+	Move  bool     // 是否为移动操作
+	Src   string   // 源绝对路径
+	Dst   string   // 目标绝对路径（已按智能路径规则追加源文件名/目录名）
+	Ops   []PlanOp // 按执行顺序排列的操作
+	Files int      // 需要处理的文件数（不含目录）
+	Bytes int64    // 需要复制的字节数
+
+	opts CopyOptions // 制定计划时使用的选项
+}
+
+// String 返回计划的可读描述，每行一个操作
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, op := range p.Ops {
+		b.WriteString(op.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// PlanCopy 制定复制计划，不修改文件系统
+// 路径处理、覆盖检查和过滤规则与 CopyContext 相同，返回的计划可通过 Execute 执行
+//
+// 参数:
+//   - src: 源路径
+//   - dst: 目标路径
+//   - opts: 复制选项，为 nil 时使用默认选项
+//
+// 返回:
+//   - *Plan: 执行计划
+//   - error: 路径无效、目标已存在（未允许覆盖）等复制必然失败的情况返回错误
+func PlanCopy(src, dst string, opts *CopyOptions) (*Plan, error) {
+	return newPlan(src, dst, opts, false)
+}
+
+// PlanMove 制定移动计划，不修改文件系统
+// 路径处理规则与 MoveContext 相同；源和目标位于同一文件系统时计划为 os.Rename，
+// 否则计划为复制+删除源路径
+//
+// 参数:
+//   - src: 源路径
+//   - dst: 目标路径
+//   - opts: 复制选项（Overwrite 控制是否覆盖），为 nil 时使用默认选项
+//
+// 返回:
+//   - *Plan: 执行计划
+//   - error: 路径无效、目标已存在（未允许覆盖）等移动必然失败的情况返回错误
+//
+// 示例:
+//
+//	plan, err := fs.PlanMove("data", "/mnt/backup", &fs.CopyOptions{Overwrite: true})
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Print(plan) // 向用户展示将要执行的操作
+//	if confirmed {
+//	    err = plan.Execute(ctx)
+//	}
+func PlanMove(src, dst string, opts *CopyOptions) (*Plan, error) {
+	return newPlan(src, dst, opts, true)
+}
+
+// newPlan 验证路径并制定计划
+func newPlan(src, dst string, opts *CopyOptions, move bool) (*Plan, error) {
+	srcAbs, dstAbs, err := validateAndResolvePaths(src, dst)
+	if err != nil {
+		return nil, err
+	}
+	// 与 MoveContext 一致，移动时始终检查子目录
+	if err := validatePathRelations(srcAbs, dstAbs, move); err != nil {
+		return nil, err
+	}
+	dstAbs = resolveDestinationPathAbs(srcAbs, dstAbs)
+
+	c := newCopier(context.Background(), opts)
+	if err := c.opts.validate(); err != nil {
+		return nil, err
+	}
+	return c.plan(srcAbs, dstAbs, move)
+}
+
+// plan 根据已解析的绝对路径制定计划
+func (c *copier) plan(srcAbs, dstAbs string, move bool) (*Plan, error) {
+	p := &Plan{Move: move, Src: srcAbs, Dst: dstAbs, opts: *c.opts}
+	if !move {
+		return p, c.planCopy(p, srcAbs, dstAbs)
+	}
+
+	// 与 move 相同：设置了过滤条件的目录逐项移动，只删除已复制的源条目
+	if c.opts.hasFilters() && isDir(srcAbs) {
+		if err := c.planCopy(p, srcAbs, dstAbs); err != nil {
+			return nil, err
+		}
+		p.addRemovals(srcAbs)
+		return p, nil
+	}
+
+	if _, err := os.Lstat(srcAbs); err != nil {
+		return nil, fmt.Errorf("failed to get source info '%s': %w", srcAbs, err)
+	}
+	if sameFilesystem(srcAbs, dstAbs) {
+		if _, err := os.Lstat(dstAbs); err == nil {
+			if !c.opts.Overwrite {
+				return nil, fmt.Errorf("destination '%s' already exists", dstAbs)
+			}
+			p.add(PlanOp{Kind: OpOverwrite, Dst: dstAbs})
+		}
+		p.add(PlanOp{Kind: OpRename, Src: srcAbs, Dst: dstAbs})
+		return p, nil
+	}
+
+	if err := c.planCopy(p, srcAbs, dstAbs); err != nil {
+		return nil, err
+	}
+	p.add(PlanOp{Kind: OpRemoveSource, Src: srcAbs})
+	return p, nil
+}
+
+// planCopy 按复制流程生成操作：目录先于其内容创建，硬链接在最后创建
+func (c *copier) planCopy(p *Plan, srcAbs, dstAbs string) error {
+	srcInfo, err := os.Lstat(srcAbs)
+	if err != nil {
+		return fmt.Errorf("failed to get source info '%s': %w", srcAbs, err)
+	}
+	if srcInfo.Mode()&os.ModeSocket != 0 {
+		return fmt.Errorf("cannot copy socket '%s'", srcAbs)
+	}
+
+	if srcInfo.IsDir() {
+		if err := validatePathRelations(srcAbs, dstAbs, true); err != nil {
+			return err
+		}
+	}
+	if err := p.planOverwrite(dstAbs, c.opts.Overwrite); err != nil {
+		return err
+	}
+	if !srcInfo.IsDir() {
+		return p.planFile(srcAbs, dstAbs, srcInfo)
+	}
+
+	p.add(PlanOp{Kind: OpCreateDir, Src: srcAbs, Dst: dstAbs})
+
+	var links []PlanOp
+	var tracker linkTracker
+	if c.opts.PreserveHardlinks {
+		tracker = linkTracker{}
+	}
+
+	err = filepath.WalkDir(srcAbs, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to access path '%s': %w", path, err)
+		}
+		relPath, err := filepath.Rel(srcAbs, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path for '%s': %w", path, err)
+		}
+		if relPath == "." {
+			return nil
+		}
+		if err := c.filterEntry(path, relPath, entry); err != nil {
+			if errors.Is(err, errSkipEntry) {
+				return nil
+			}
+			return err
+		}
+
+		dstPath := filepath.Join(dstAbs, relPath)
+		if entry.IsDir() {
+			p.add(PlanOp{Kind: OpCreateDir, Src: path, Dst: dstPath})
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to get file info '%s': %w", path, err)
+		}
+		if tracker != nil {
+			if target, ok := tracker.track(info, dstPath); ok {
+				links = append(links, PlanOp{Kind: OpHardlink, Src: path, Dst: dstPath, Target: target})
+				return nil
+			}
+		}
+		return p.planFile(path, dstPath, info)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		p.add(link)
+	}
+	return nil
+}
+
+// planOverwrite 检查目标是否已存在，允许覆盖时记录替换操作
+func (p *Plan) planOverwrite(dstAbs string, overwrite bool) error {
+	if _, err := os.Lstat(dstAbs); err != nil {
+		return nil
+	}
+	if !overwrite {
+		return fmt.Errorf("destination '%s' already exists", dstAbs)
+	}
+	p.add(PlanOp{Kind: OpOverwrite, Dst: dstAbs})
+	return nil
+}
+
+// planFile 按文件类型生成单个文件的操作
+func (p *Plan) planFile(srcAbs, dstAbs string, info os.FileInfo) error {
+	mode := info.Mode()
+	switch {
+	case mode.IsRegular():
+		p.add(PlanOp{Kind: OpCopyFile, Src: srcAbs, Dst: dstAbs, Size: info.Size()})
+
+	case mode&os.ModeSymlink != 0:
+		// Windows 上符号链接按其指向的文件复制
+		if runtime.GOOS == "windows" {
+			target, err := os.Stat(srcAbs)
+			if err != nil {
+				return fmt.Errorf("failed to get symlink target info '%s': %w", srcAbs, err)
+			}
+			p.add(PlanOp{Kind: OpCopyFile, Src: srcAbs, Dst: dstAbs, Size: target.Size()})
+			return nil
+		}
+		target, err := os.Readlink(srcAbs)
+		if err != nil {
+			return fmt.Errorf("failed to read symlink '%s': %w", srcAbs, err)
+		}
+		p.add(PlanOp{Kind: OpSymlink, Src: srcAbs, Dst: dstAbs, Target: target})
+
+	case mode&os.ModeSocket != 0:
+		p.add(PlanOp{Kind: OpSkip, Src: srcAbs})
+
+	default:
+		p.add(PlanOp{Kind: OpSpecial, Src: srcAbs, Dst: dstAbs})
+	}
+	return nil
+}
+
+// add 追加操作并更新统计
+func (p *Plan) add(op PlanOp) {
+	p.Ops = append(p.Ops, op)
+	switch op.Kind {
+	case OpCopyFile, OpSymlink, OpSpecial, OpHardlink:
+		p.Files++
+		p.Bytes += op.Size
+	}
+}
+
+// addRemovals 为带过滤条件的移动追加删除源条目的操作，顺序与 removeCopiedSources 相同
+func (p *Plan) addRemovals(srcAbs string) {
+	var dirs []string
+	for _, op := range slices.Clone(p.Ops) {
+		switch op.Kind {
+		case OpCopyFile, OpSymlink, OpSpecial, OpHardlink:
+			p.add(PlanOp{Kind: OpRemoveSource, Src: op.Src})
+		case OpCreateDir:
+			if op.Src != srcAbs {
+				dirs = append(dirs, op.Src)
+			}
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	for _, dir := range dirs {
+		p.add(PlanOp{Kind: OpRemoveSource, Src: dir})
+	}
+	p.add(PlanOp{Kind: OpRemoveSource, Src: srcAbs})
+}
+
+// Execute 执行计划
+// 执行前重新制定计划并与当前计划比较，文件系统在此期间发生变化时返回 ErrPlanStale，不做任何修改
+//
+// 参数:
+//   - ctx: 上下文，取消行为与 CopyContext/MoveContext 相同
+//
+// 返回:
+//   - error: 计划已过期或执行失败时返回错误
+//
+// 注意:
+//   - 计划为 os.Rename 的移动在执行时 rename 失败（如跨挂载点）会自动降级为复制+删除
+func (p *Plan) Execute(ctx context.Context) error {
+	c := newCopier(ctx, &p.opts)
+	current, err := c.plan(p.Src, p.Dst, p.Move)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPlanStale, err)
+	}
+	if !slices.Equal(current.Ops, p.Ops) {
+		return fmt.Errorf("%w: filesystem changed since planning", ErrPlanStale)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if p.Move {
+		return c.move(p.Src, p.Dst)
+	}
+	if err := c.scanTotals(p.Src); err != nil {
+		return err
+	}
+	return c.copy(p.Src, p.Dst)
+}
+
+// sameFilesystem 判断源路径和目标路径是否位于同一文件系统
+// 目标不存在时使用其最近的已存在上级目录；无法获取设备号时比较卷名
+func sameFilesystem(srcAbs, dstAbs string) bool {
+	srcInfo, err := os.Lstat(srcAbs)
+	if err != nil {
+		return false
+	}
+
+	dir := dstAbs
+	var dstInfo os.FileInfo
+	for {
+		if dstInfo, err = os.Lstat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+
+	srcKey, _, srcOK := fileInode(srcInfo)
+	dstKey, _, dstOK := fileInode(dstInfo)
+	if srcOK && dstOK {
+		return srcKey.dev == dstKey.dev
+	}
+	return strings.EqualFold(filepath.VolumeName(srcAbs), filepath.VolumeName(dstAbs))
+}
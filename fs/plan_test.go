@@ -0,0 +1,207 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// opKinds 返回计划中的操作类型列表
+func opKinds(p *Plan) []OpKind {
+	kinds := make([]OpKind, 0, len(p.Ops))
+	for _, op := range p.Ops {
+		kinds = append(kinds, op.Kind)
+	}
+	return kinds
+}
+
+func TestPlanCopy(t *testing.T) {
+	t.Run("File into existing directory", func(t *testing.T) {
+		dir := t.TempDir()
+		src := filepath.Join(dir, "a.txt")
+		if err := os.WriteFile(src, []byte("hello"), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		target := filepath.Join(dir, "out")
+		if err := os.Mkdir(target, 0o755); err != nil {
+			t.Fatalf("Mkdir failed: %v", err)
+		}
+
+		plan, err := PlanCopy(src, target, nil)
+		if err != nil {
+			t.Fatalf("PlanCopy failed: %v", err)
+		}
+		want := PlanOp{Kind: OpCopyFile, Src: src, Dst: filepath.Join(target, "a.txt"), Size: 5}
+		if len(plan.Ops) != 1 || plan.Ops[0] != want {
+			t.Errorf("Ops = %v, want [%v]", plan.Ops, want)
+		}
+		if plan.Files != 1 || plan.Bytes != 5 {
+			t.Errorf("Files/Bytes = %d/%d, want 1/5", plan.Files, plan.Bytes)
+		}
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		src := setupCopyTestDir(t)
+		dst := filepath.Join(t.TempDir(), "dst")
+
+		plan, err := PlanCopy(src, dst, nil)
+		if err != nil {
+			t.Fatalf("PlanCopy failed: %v", err)
+		}
+		if _, err := os.Lstat(dst); !os.IsNotExist(err) {
+			t.Fatalf("Planning modified the filesystem: %v", err)
+		}
+
+		// 每个操作的父目录都在其之前创建
+		created := map[string]bool{filepath.Dir(dst): true}
+		for _, op := range plan.Ops {
+			if !created[filepath.Dir(op.Dst)] {
+				t.Errorf("%v: parent directory not created before", op)
+			}
+			if op.Kind == OpCreateDir {
+				created[op.Dst] = true
+			}
+		}
+		if plan.Ops[0].Kind != OpCreateDir || plan.Ops[0].Dst != dst {
+			t.Errorf("First op = %v, want mkdir %s", plan.Ops[0], dst)
+		}
+
+		if err := plan.Execute(context.Background()); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		validateDirCopy(t, src, dst)
+	})
+
+	t.Run("Overwrite", func(t *testing.T) {
+		dir := t.TempDir()
+		src := filepath.Join(dir, "a.txt")
+		dst := filepath.Join(dir, "b.txt")
+		for _, path := range []string{src, dst} {
+			if err := os.WriteFile(path, []byte(path), 0o644); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+		}
+
+		if _, err := PlanCopy(src, dst, nil); err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("Expected already exists error, got %v", err)
+		}
+		plan, err := PlanCopy(src, dst, &CopyOptions{Overwrite: true})
+		if err != nil {
+			t.Fatalf("PlanCopy failed: %v", err)
+		}
+		if kinds := opKinds(plan); len(kinds) != 2 || kinds[0] != OpOverwrite || kinds[1] != OpCopyFile {
+			t.Errorf("Ops = %v, want [overwrite copy]", kinds)
+		}
+	})
+
+	t.Run("Stale plan", func(t *testing.T) {
+		src := setupCopyTestDir(t)
+		dst := filepath.Join(t.TempDir(), "dst")
+
+		plan, err := PlanCopy(src, dst, nil)
+		if err != nil {
+			t.Fatalf("PlanCopy failed: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(src, "late.txt"), []byte("late"), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		if err := plan.Execute(context.Background()); !errors.Is(err, ErrPlanStale) {
+			t.Fatalf("Expected ErrPlanStale, got %v", err)
+		}
+		if _, err := os.Lstat(dst); !os.IsNotExist(err) {
+			t.Errorf("Stale plan modified the filesystem: %v", err)
+		}
+	})
+
+	t.Run("Symlink", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Symlinks are copied as files on Windows")
+		}
+		dir := t.TempDir()
+		src := filepath.Join(dir, "link")
+		if err := os.Symlink("target.txt", src); err != nil {
+			t.Fatalf("Symlink failed: %v", err)
+		}
+		plan, err := PlanCopy(src, filepath.Join(dir, "link2"), nil)
+		if err != nil {
+			t.Fatalf("PlanCopy failed: %v", err)
+		}
+		if len(plan.Ops) != 1 || plan.Ops[0].Kind != OpSymlink || plan.Ops[0].Target != "target.txt" {
+			t.Errorf("Ops = %v, want [symlink -> target.txt]", plan.Ops)
+		}
+	})
+}
+
+func TestPlanMove(t *testing.T) {
+	t.Run("Rename", func(t *testing.T) {
+		src := setupCopyTestDir(t)
+		dst := filepath.Join(t.TempDir(), "dst")
+
+		plan, err := PlanMove(src, dst, nil)
+		if err != nil {
+			t.Fatalf("PlanMove failed: %v", err)
+		}
+		want := PlanOp{Kind: OpRename, Src: src, Dst: dst}
+		if len(plan.Ops) != 1 || plan.Ops[0] != want {
+			t.Fatalf("Ops = %v, want [%v]", plan.Ops, want)
+		}
+		if !strings.HasPrefix(plan.String(), "rename") {
+			t.Errorf("String() = %q", plan.String())
+		}
+
+		if err := plan.Execute(context.Background()); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if _, err := os.Lstat(src); !os.IsNotExist(err) {
+			t.Errorf("Source still exists: %v", err)
+		}
+	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		src := setupFilterTree(t)
+		dst := filepath.Join(t.TempDir(), "dst")
+
+		plan, err := PlanMove(src, dst, &CopyOptions{Exclude: []string{".git", "node_modules", "docs"}})
+		if err != nil {
+			t.Fatalf("PlanMove failed: %v", err)
+		}
+		var copies, removals int
+		for _, op := range plan.Ops {
+			switch op.Kind {
+			case OpCopyFile:
+				copies++
+			case OpRemoveSource:
+				removals++
+			case OpRename:
+				t.Errorf("Filtered move must not rename: %v", op)
+			}
+		}
+		// 6 个文件 + pkg 目录 + 根目录
+		if copies != 6 || removals != 8 {
+			t.Errorf("Copies/removals = %d/%d, want 6/8\n%s", copies, removals, plan)
+		}
+		if last := plan.Ops[len(plan.Ops)-1]; last.Kind != OpRemoveSource || last.Src != src {
+			t.Errorf("Last op = %v, want remove %s", last, src)
+		}
+
+		if err := plan.Execute(context.Background()); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if got := len(listFiles(t, dst)); got != 6 {
+			t.Errorf("Moved files = %d, want 6", got)
+		}
+	})
+}
+
+func TestOpKindString(t *testing.T) {
+	if got := OpRemoveSource.String(); got != "remove" {
+		t.Errorf("String() = %q, want remove", got)
+	}
+	if got := OpKind(99).String(); got != "OpKind(99)" {
+		t.Errorf("String() = %q, want OpKind(99)", got)
+	}
+}
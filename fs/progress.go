@@ -0,0 +1,164 @@
+package fs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+)
+
+// CopyProgress 复制进度
+type CopyProgress struct {
+	BytesDone  int64      // 已复制的字节数
+	BytesTotal int64      // 需要复制的总字节数（普通文件大小之和）
+	FilesDone  int        // 已完成的文件数（不含目录）
+	FilesTotal int        // 需要复制的文件总数（不含目录）
+	Current    string     // 当前正在复制的源路径
+	Method     CopyMethod // 最近完成的文件使用的复制方式
+	Skipped    int        // 跳过的套接字数量（不计入 FilesTotal）
+}
+
+// ProgressFunc 复制进度回调函数
+type ProgressFunc func(p CopyProgress)
+
+// scanTotals 统计需要复制的文件数和字节数（仅设置了进度回调时执行）
+//
+// 参数:
+//   - srcAbs: 源绝对路径
+//
+// 返回:
+//   - error: 遍历失败或已取消时返回错误
+func (c *copier) scanTotals(srcAbs string) error {
+	if c.opts.Progress == nil {
+		return nil
+	}
+
+	// 保留硬链接时同一 inode 的数据只复制一次
+	var seen map[inodeKey]bool
+	if c.opts.PreserveHardlinks {
+		seen = map[inodeKey]bool{}
+	}
+
+	return filepath.WalkDir(srcAbs, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+
+		// 与复制过程使用相同的过滤条件，根路径本身总是被复制
+		if path != srcAbs {
+			rel, err := filepath.Rel(srcAbs, path)
+			if err != nil {
+				return err
+			}
+			if err := c.filterEntry(path, rel, entry); err != nil {
+				if errors.Is(err, errSkipEntry) {
+					return nil
+				}
+				return err
+			}
+		}
+		if entry.IsDir() || entry.Type()&fs.ModeSocket != 0 {
+			return nil
+		}
+
+		c.progress.FilesTotal++
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			if seen != nil {
+				if key, nlink, ok := fileInode(info); ok && nlink > 1 {
+					if seen[key] {
+						return nil
+					}
+					seen[key] = true
+				}
+			}
+			c.progress.BytesTotal += info.Size()
+		}
+		return nil
+	})
+}
+
+// startFile 记录开始复制的文件并报告进度
+func (c *copier) startFile(path string) {
+	if c.opts.Progress == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.Current = path
+	c.opts.Progress(c.progress)
+}
+
+// finishFile 记录文件复制完成并报告进度
+func (c *copier) finishFile(method CopyMethod) {
+	if c.opts.Progress == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.FilesDone++
+	c.progress.Method = method
+	c.opts.Progress(c.progress)
+}
+
+// skipFile 记录跳过的文件并报告进度
+func (c *copier) skipFile(path string) {
+	if c.opts.Progress == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.Skipped++
+	c.progress.Current = path
+	c.opts.Progress(c.progress)
+}
+
+// addBytes 累加已复制的字节数并报告进度
+func (c *copier) addBytes(n int64) {
+	if c.opts.Progress == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.BytesDone += n
+	c.opts.Progress(c.progress)
+}
+
+// wrapReader 在需要进度报告或支持取消时包装数据源
+// 两者都不需要时直接返回原始数据源，避免额外的包装开销
+//
+// 参数:
+//   - r: 源数据
+//
+// 返回:
+//   - io.Reader: 数据源
+func (c *copier) wrapReader(r io.Reader) io.Reader {
+	if c.opts.Progress == nil && c.ctx.Done() == nil {
+		return r
+	}
+	return &progressReader{r: r, c: c}
+}
+
+// progressReader 每次读取前检查取消状态、读取后报告进度的数据源
+type progressReader struct {
+	r io.Reader
+	c *copier
+}
+
+// Read 读取数据
+func (r *progressReader) Read(p []byte) (int, error) {
+	if err := r.c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.c.addBytes(int64(n))
+	}
+	return n, err
+}
@@ -0,0 +1,131 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCopyContextProgress(t *testing.T) {
+	src := setupCopyTestDir(t)
+	dst := filepath.Join(t.TempDir(), "dst")
+
+	var updates []CopyProgress
+	err := CopyContext(context.Background(), src, dst, &CopyOptions{
+		Progress: func(p CopyProgress) { updates = append(updates, p) },
+	})
+	if err != nil {
+		t.Fatalf("CopyContext failed: %v", err)
+	}
+	if len(updates) == 0 {
+		t.Fatal("Progress callback was never called")
+	}
+
+	wantBytes, err := GetSize(src)
+	if err != nil {
+		t.Fatalf("GetSize failed: %v", err)
+	}
+	last := updates[len(updates)-1]
+	if last.FilesTotal != 8 || last.FilesDone != last.FilesTotal {
+		t.Errorf("Files = %d/%d, want 8/8", last.FilesDone, last.FilesTotal)
+	}
+	if last.BytesTotal != wantBytes || last.BytesDone != wantBytes {
+		t.Errorf("Bytes = %d/%d, want %d/%d", last.BytesDone, last.BytesTotal, wantBytes, wantBytes)
+	}
+
+	for i := 1; i < len(updates); i++ {
+		if updates[i].BytesDone < updates[i-1].BytesDone || updates[i].FilesDone < updates[i-1].FilesDone {
+			t.Fatalf("Progress went backwards: %+v -> %+v", updates[i-1], updates[i])
+		}
+	}
+	if !strings.HasPrefix(last.Current, src) {
+		t.Errorf("Current = %q, want path under %q", last.Current, src)
+	}
+}
+
+func TestCopyContextCancel(t *testing.T) {
+	t.Run("Directory", func(t *testing.T) {
+		src := setupCopyTestDir(t)
+		dst := filepath.Join(t.TempDir(), "dst")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := CopyContext(ctx, src, dst, &CopyOptions{
+			Progress: func(p CopyProgress) {
+				if p.FilesDone == 2 {
+					cancel()
+				}
+			},
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+		if _, err := os.Stat(dst); !os.IsNotExist(err) {
+			t.Errorf("Partial destination was not removed: %v", err)
+		}
+	})
+
+	t.Run("Overwrite restores backup", func(t *testing.T) {
+		dir := t.TempDir()
+		src := filepath.Join(dir, "large.bin")
+		dst := filepath.Join(dir, "existing.bin")
+		if err := os.WriteFile(src, make([]byte, 4<<20), 0o644); err != nil {
+			t.Fatalf("Failed to write source: %v", err)
+		}
+		if err := os.WriteFile(dst, []byte("original"), 0o644); err != nil {
+			t.Fatalf("Failed to write destination: %v", err)
+		}
+
+		// 写入第一个数据块后取消
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := CopyContext(ctx, src, dst, &CopyOptions{
+			Overwrite: true,
+			Progress: func(p CopyProgress) {
+				if p.BytesDone > 0 {
+					cancel()
+				}
+			},
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+
+		data, err := os.ReadFile(dst)
+		if err != nil || string(data) != "original" {
+			t.Errorf("Destination = %q, %v; want original content", data, err)
+		}
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 2 {
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			t.Errorf("Leftover temporary files: %v", names)
+		}
+	})
+}
+
+func TestMoveContext(t *testing.T) {
+	src := setupCopyTestDir(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := MoveContext(ctx, src, filepath.Join(t.TempDir(), "moved"), nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(src, "file1.txt")); err != nil {
+		t.Errorf("Source was modified after cancel: %v", err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "moved")
+	if err := MoveContext(context.Background(), src, dst, nil); err != nil {
+		t.Fatalf("MoveContext failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "nestedDir/subdir/file1.txt")); err != nil {
+		t.Errorf("Moved file missing: %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !windows
+
+package fs
+
+import "os"
+
+// allocatedSize 其他平台上返回文件大小
+func allocatedSize(info os.FileInfo) int64 {
+	return info.Size()
+}
+
+// copySparse 其他平台上不使用 SEEK_DATA/SEEK_HOLE，回退到普通复制
+func (c *copier) copySparse(out, in *os.File, size int64) error {
+	return errFastCopyUnsupported
+}
@@ -0,0 +1,68 @@
+//go:build linux || darwin
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// allocatedSize 返回文件实际占用的磁盘空间（st_blocks × 512），无法获取时返回文件大小
+func allocatedSize(info os.FileInfo) int64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return stat.Blocks * 512
+	}
+	return info.Size()
+}
+
+// copySparse 只复制源文件的数据区段，跳过空洞，最后截断到源文件大小以重建末尾空洞
+// 使用 SEEK_DATA/SEEK_HOLE 查找数据区段，目标文件中未写入的区域自然成为空洞
+//
+// 参数:
+//   - out: 目标文件（新创建的空文件）
+//   - in: 源文件
+//   - size: 源文件大小
+//
+// 返回:
+//   - error: 文件系统不支持 SEEK_DATA 时返回 errFastCopyUnsupported（此时尚未写入任何数据）
+func (c *copier) copySparse(out, in *os.File, size int64) error {
+	var off int64
+	for off < size {
+		data, err := in.Seek(off, unix.SEEK_DATA)
+		if errors.Is(err, unix.ENXIO) {
+			break // 剩余部分全部是空洞
+		}
+		if err != nil {
+			if off == 0 {
+				return errFastCopyUnsupported
+			}
+			return fmt.Errorf("failed to seek data: %w", err)
+		}
+		if data >= size {
+			break
+		}
+		hole, err := in.Seek(data, unix.SEEK_HOLE)
+		if err != nil {
+			return fmt.Errorf("failed to seek hole: %w", err)
+		}
+		hole = min(hole, size)
+
+		// 跳过的空洞计入进度
+		c.addBytes(data - off)
+		if err := c.copyExtent(out, in, data, hole-data); err != nil {
+			return err
+		}
+		off = hole
+	}
+	c.addBytes(size - off)
+
+	// 截断到源文件大小，重建末尾的空洞并保持文件大小一致
+	if err := out.Truncate(size); err != nil {
+		return fmt.Errorf("failed to set file size: %w", err)
+	}
+	return nil
+}
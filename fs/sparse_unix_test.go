@@ -0,0 +1,93 @@
+//go:build linux || darwin
+
+package fs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// createSparseFile 创建带空洞的文件：在指定偏移写入数据，其余部分为空洞
+func createSparseFile(t *testing.T, path string, size int64, chunks map[int64][]byte) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := f.Truncate(size); err != nil {
+		t.Fatalf("Failed to truncate file: %v", err)
+	}
+	for off, data := range chunks {
+		if _, err := f.WriteAt(data, off); err != nil {
+			t.Fatalf("Failed to write chunk: %v", err)
+		}
+	}
+}
+
+// allocatedOf 返回路径实际占用的磁盘空间
+func allocatedOf(t *testing.T, path string) int64 {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat file: %v", err)
+	}
+	return allocatedSize(info)
+}
+
+func TestCopySparse(t *testing.T) {
+	const size = 16 << 20
+	data := bytes.Repeat([]byte("sparse"), 20_000)
+
+	tests := []struct {
+		name    string
+		chunks  map[int64][]byte
+		reflink ReflinkMode
+	}{
+		{"Middle data", map[int64][]byte{4 << 20: data}, ReflinkPrefer},
+		{"Leading and trailing data", map[int64][]byte{0: data, size - int64(len(data)): data}, ReflinkPrefer},
+		{"Buffer extents", map[int64][]byte{0: data, 8 << 20: data}, ReflinkDisable},
+		{"All hole", nil, ReflinkPrefer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "disk.img")
+			createSparseFile(t, src, size, tt.chunks)
+			if allocatedOf(t, src) >= size {
+				t.Skip("Filesystem does not support sparse files")
+			}
+
+			var last CopyProgress
+			opts := &CopyOptions{Reflink: tt.reflink, Progress: func(p CopyProgress) { last = p }}
+			dst := filepath.Join(dir, "copy.img")
+			if err := CopyWithOptions(src, dst, opts); err != nil {
+				t.Fatalf("CopyWithOptions failed: %v", err)
+			}
+
+			want, _ := os.ReadFile(src)
+			got, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatalf("Failed to read copy: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatal("Copied content does not match source")
+			}
+			if allocated := allocatedOf(t, dst); allocated >= size {
+				t.Errorf("Copy is dense: allocated %d bytes of %d", allocated, size)
+			}
+			if last.Method != CopySparse && last.Method != CopyReflink {
+				t.Errorf("Method = %v, want %v", last.Method, CopySparse)
+			}
+			if last.BytesDone != size {
+				t.Errorf("BytesDone = %d, want %d", last.BytesDone, size)
+			}
+		})
+	}
+}
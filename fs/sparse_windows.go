@@ -0,0 +1,15 @@
+//go:build windows
+
+package fs
+
+import "os"
+
+// allocatedSize Windows 上返回文件大小
+func allocatedSize(info os.FileInfo) int64 {
+	return info.Size()
+}
+
+// copySparse Windows 上不支持 SEEK_DATA/SEEK_HOLE，回退到普通复制
+func (c *copier) copySparse(out, in *os.File, size int64) error {
+	return errFastCopyUnsupported
+}
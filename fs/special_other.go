@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package fs
+
+import (
+	"fmt"
+	"os"
+)
+
+// makeSpecialFile 其他平台暂不支持创建设备文件和命名管道
+func makeSpecialFile(path string, info os.FileInfo) error {
+	return fmt.Errorf("cannot create special file '%s': unsupported on this platform", path)
+}
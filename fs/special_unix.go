@@ -0,0 +1,53 @@
+//go:build linux || darwin
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// makeSpecialFile 按源文件类型在目标路径创建设备文件或命名管道
+// 设备文件保留源文件的主/次设备号，创建需要 root 权限（CAP_MKNOD）
+//
+// 参数:
+//   - path: 目标路径
+//   - info: 源文件信息（Lstat 结果）
+//
+// 返回:
+//   - error: 创建失败时返回错误
+func makeSpecialFile(path string, info os.FileInfo) error {
+	mode := info.Mode()
+	perm := uint32(mode.Perm())
+
+	switch {
+	case mode&os.ModeNamedPipe != 0:
+		if err := unix.Mkfifo(path, perm); err != nil {
+			return fmt.Errorf("failed to create named pipe '%s': %w", path, err)
+		}
+		return nil
+
+	case mode&os.ModeDevice != 0:
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			return fmt.Errorf("failed to get device number of '%s'", path)
+		}
+		kind := uint32(unix.S_IFBLK)
+		if mode&os.ModeCharDevice != 0 {
+			kind = unix.S_IFCHR
+		}
+		if err := unix.Mknod(path, kind|perm, int(stat.Rdev)); err != nil {
+			if errors.Is(err, unix.EPERM) {
+				return fmt.Errorf("creating device file '%s' requires root privileges (CAP_MKNOD): %w", path, err)
+			}
+			return fmt.Errorf("failed to create device file '%s': %w", path, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported file type %s for '%s'", mode.Type(), path)
+}
@@ -0,0 +1,103 @@
+//go:build linux || darwin
+
+package fs
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestCopySpecialFiles(t *testing.T) {
+	t.Run("Named pipe", func(t *testing.T) {
+		dir := t.TempDir()
+		src := filepath.Join(dir, "fifo")
+		if err := unix.Mkfifo(src, 0o640); err != nil {
+			t.Fatalf("Mkfifo failed: %v", err)
+		}
+		dst := filepath.Join(dir, "fifo.copy")
+		if err := CopyWithOptions(src, dst, ArchiveOptions()); err != nil {
+			t.Fatalf("CopyWithOptions failed: %v", err)
+		}
+
+		info, err := os.Lstat(dst)
+		if err != nil {
+			t.Fatalf("Lstat failed: %v", err)
+		}
+		if info.Mode().Type() != os.ModeNamedPipe {
+			t.Errorf("Type = %v, want named pipe", info.Mode().Type())
+		}
+		if info.Mode().Perm() != 0o640 {
+			t.Errorf("Perm = %v, want 0640", info.Mode().Perm())
+		}
+	})
+
+	t.Run("Character device", func(t *testing.T) {
+		dir := t.TempDir()
+		src := filepath.Join(dir, "null")
+		// 与 /dev/null 相同的设备号 (1, 3)
+		if err := unix.Mknod(src, unix.S_IFCHR|0o666, int(unix.Mkdev(1, 3))); err != nil {
+			t.Skipf("Mknod not permitted: %v", err)
+		}
+		dst := filepath.Join(dir, "null.copy")
+		if err := Copy(src, dst); err != nil {
+			t.Fatalf("Copy failed: %v", err)
+		}
+
+		srcInfo, _ := os.Lstat(src)
+		dstInfo, err := os.Lstat(dst)
+		if err != nil {
+			t.Fatalf("Lstat failed: %v", err)
+		}
+		if dstInfo.Mode().Type() != srcInfo.Mode().Type() {
+			t.Errorf("Type = %v, want %v", dstInfo.Mode().Type(), srcInfo.Mode().Type())
+		}
+		srcRdev := srcInfo.Sys().(*syscall.Stat_t).Rdev
+		if dstRdev := dstInfo.Sys().(*syscall.Stat_t).Rdev; dstRdev != srcRdev {
+			t.Errorf("Rdev = %d, want %d", dstRdev, srcRdev)
+		}
+	})
+
+	t.Run("Socket", func(t *testing.T) {
+		dir := t.TempDir()
+		src := filepath.Join(dir, "tree")
+		if err := os.MkdirAll(src, 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(src, "data.txt"), []byte("data"), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		sock := filepath.Join(src, "s.sock")
+		ln, err := net.Listen("unix", sock)
+		if err != nil {
+			t.Skipf("Unix sockets not available: %v", err)
+		}
+		defer func() { _ = ln.Close() }()
+
+		// 目录中的套接字被跳过，并在进度中报告
+		var last CopyProgress
+		dst := filepath.Join(dir, "copy")
+		err = CopyWithOptions(src, dst, &CopyOptions{Progress: func(p CopyProgress) { last = p }})
+		if err != nil {
+			t.Fatalf("CopyWithOptions failed: %v", err)
+		}
+		if _, err := os.Lstat(filepath.Join(dst, "s.sock")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Socket was copied: %v", err)
+		}
+		if last.Skipped != 1 || last.FilesDone != 1 || last.FilesTotal != 1 {
+			t.Errorf("Progress = %+v, want 1 file done and 1 skipped", last)
+		}
+
+		// 直接复制套接字返回错误
+		err = Copy(sock, filepath.Join(dir, "s.copy"))
+		if err == nil || !strings.Contains(err.Error(), "cannot copy socket") {
+			t.Errorf("Expected socket error, got %v", err)
+		}
+	})
+}
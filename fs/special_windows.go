@@ -0,0 +1,13 @@
+//go:build windows
+
+package fs
+
+import (
+	"fmt"
+	"os"
+)
+
+// makeSpecialFile Windows 不支持设备文件和命名管道
+func makeSpecialFile(path string, info os.FileInfo) error {
+	return fmt.Errorf("cannot create special file '%s': unsupported on Windows", path)
+}
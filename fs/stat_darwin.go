@@ -0,0 +1,17 @@
+//go:build darwin
+
+package fs
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileAtime 返回文件的访问时间，无法获取时返回修改时间
+func fileAtime(info os.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec)
+	}
+	return info.ModTime()
+}
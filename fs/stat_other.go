@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package fs
+
+import (
+	"os"
+	"time"
+)
+
+// fileAtime 其他平台上无法统一获取访问时间，返回修改时间
+func fileAtime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
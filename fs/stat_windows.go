@@ -0,0 +1,17 @@
+//go:build windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileAtime 返回文件的访问时间，无法获取时返回修改时间
+func fileAtime(info os.FileInfo) time.Time {
+	if data, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		return time.Unix(0, data.LastAccessTime.Nanoseconds())
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,398 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"gitee.com/MM-Q/go-kit/hash"
+)
+
+// SyncOptions 同步选项
+type SyncOptions struct {
+	// CopyOptions 复制新增和变化文件时使用的选项（过滤条件、元数据保留、进度、并行等）
+	// Overwrite 被忽略（变化的文件总是被替换），PreserveTimes 总是开启，以便下次同步时按修改时间比较
+	CopyOptions
+
+	// Delete 删除目标中源目录不存在的条目（被过滤规则排除的条目不会被删除）
+	Delete bool
+
+	// Checksum 大小相同时按校验和比较内容，而不是比较修改时间
+	Checksum bool
+
+	// Algorithm 校验和算法（md5、sha1、sha256、sha512），为空时使用 sha256
+	Algorithm string
+
+	// DryRun 只计算需要执行的操作，不修改文件系统
+	DryRun bool
+}
+
+// SyncResult 同步结果
+type SyncResult struct {
+	Added     []string // 新增的文件（相对路径，使用 / 分隔）
+	Changed   []string // 内容变化而重新复制的文件
+	Deleted   []string // 删除的目标条目（删除目录时只列出目录本身）
+	Unchanged int      // 未变化的文件数
+	Bytes     int64    // 复制的字节数
+}
+
+// syncCopy 需要复制的文件
+type syncCopy struct {
+	src  string
+	dst  string
+	info os.FileInfo
+}
+
+// Sync 将目标目录同步为与源目录一致（类似 rsync -a）
+// 只复制新增或变化的文件，默认按大小和修改时间判断是否变化
+//
+// 参数:
+//   - src: 源目录
+//   - dst: 目标目录（不存在时创建，与 Copy 不同，不会追加源目录名）
+//   - opts: 同步选项，为 nil 时使用默认选项
+//
+// 返回:
+//   - *SyncResult: 同步结果，出错时包含出错前已确定的操作
+//   - error: 同步失败时返回错误
+func Sync(src, dst string, opts *SyncOptions) (*SyncResult, error) {
+	return SyncContext(context.Background(), src, dst, opts)
+}
+
+// SyncContext 将目标目录同步为与源目录一致，支持通过 ctx 取消
+// 每个文件通过临时文件 + 原子重命名写入，中断的同步不会留下截断的文件
+//
+// 参数:
+//   - ctx: 上下文，取消后在下一个文件或数据块处停止
+//   - src: 源目录
+//   - dst: 目标目录（不存在时创建）
+//   - opts: 同步选项，为 nil 时使用默认选项
+//
+// 返回:
+//   - *SyncResult: 同步结果
+//   - error: 同步失败时返回错误，取消时返回的错误包装了 ctx.Err()
+//
+// 示例:
+//
+//	result, err := fs.Sync("site", "/var/www/site", &fs.SyncOptions{
+//	    Delete:      true,
+//	    CopyOptions: fs.CopyOptions{Exclude: []string{".git"}},
+//	})
+//	fmt.Printf("+%d ~%d -%d\n", len(result.Added), len(result.Changed), len(result.Deleted))
+func SyncContext(ctx context.Context, src, dst string, opts *SyncOptions) (result *SyncResult, err error) {
+	// 捕获 panic 并转换为错误
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("sync operation panicked: %v", r)
+		}
+	}()
+
+	if opts == nil {
+		opts = &SyncOptions{}
+	}
+	algorithm := opts.Algorithm
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	if opts.Checksum && !hash.IsAlgorithmSupported(algorithm) {
+		return nil, fmt.Errorf("unsupported hash algorithm: %s", algorithm)
+	}
+
+	srcAbs, dstAbs, err := validateAndResolvePaths(src, dst)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePathRelations(srcAbs, dstAbs, true); err != nil {
+		return nil, err
+	}
+	if !isDir(srcAbs) {
+		return nil, fmt.Errorf("sync source '%s' is not a directory", srcAbs)
+	}
+	if info, err := os.Lstat(dstAbs); err == nil && !info.IsDir() {
+		return nil, fmt.Errorf("sync destination '%s' is not a directory", dstAbs)
+	}
+
+	copyOpts := opts.CopyOptions
+	copyOpts.Overwrite = true
+	copyOpts.PreserveTimes = true
+	copyOpts.PreserveHardlinks = false
+	c := newCopier(ctx, &copyOpts)
+	if err := c.opts.validate(); err != nil {
+		return nil, err
+	}
+
+	s := &syncer{c: c, opts: opts, algorithm: algorithm, srcAbs: srcAbs, dstAbs: dstAbs, result: &SyncResult{}}
+	if err := s.scan(); err != nil {
+		return s.result, err
+	}
+	if opts.DryRun {
+		return s.result, nil
+	}
+	return s.result, s.apply()
+}
+
+// syncer 单次同步操作的内部状态
+type syncer struct {
+	c         *copier
+	opts      *SyncOptions
+	algorithm string // 校验和算法
+	srcAbs    string
+	dstAbs    string
+	result    *SyncResult
+
+	dirs    []dirEntry // 源目录中的所有目录（父目录在前）
+	copies  []syncCopy // 需要复制的文件
+	touches []syncCopy // 内容相同但修改时间不同的文件（校验和模式）
+	deletes []string   // 需要删除的目标路径
+}
+
+// scan 比较源目录和目标目录，确定需要执行的操作
+func (s *syncer) scan() error {
+	srcInfo, err := os.Stat(s.srcAbs)
+	if err != nil {
+		return fmt.Errorf("failed to get source directory info '%s': %w", s.srcAbs, err)
+	}
+	s.dirs = append(s.dirs, dirEntry{src: s.srcAbs, dst: s.dstAbs, info: srcInfo})
+
+	err = filepath.WalkDir(s.srcAbs, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to access path '%s': %w", path, err)
+		}
+		if err := s.c.ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.srcAbs, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path for '%s': %w", path, err)
+		}
+		if rel == "." {
+			return nil
+		}
+		if err := s.c.filterEntry(path, rel, entry); err != nil {
+			if errors.Is(err, errSkipEntry) {
+				return nil
+			}
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to get file info '%s': %w", path, err)
+		}
+		dstPath := filepath.Join(s.dstAbs, rel)
+		if entry.IsDir() {
+			s.dirs = append(s.dirs, dirEntry{src: path, dst: dstPath, info: info})
+			return nil
+		}
+		if info.Mode()&os.ModeSocket != 0 {
+			return nil
+		}
+		return s.compare(path, dstPath, filepath.ToSlash(rel), info)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.opts.Delete {
+		return s.scanExtraneous()
+	}
+	return nil
+}
+
+// compare 比较单个源文件与目标，记录新增、变化或未变化
+func (s *syncer) compare(srcPath, dstPath, rel string, srcInfo os.FileInfo) error {
+	dstInfo, err := os.Lstat(dstPath)
+	if err != nil {
+		// 父目录位置上是文件时返回 ENOTDIR，该文件会在应用阶段被目录替换
+		if !os.IsNotExist(err) && !errors.Is(err, syscall.ENOTDIR) {
+			return fmt.Errorf("failed to get destination info '%s': %w", dstPath, err)
+		}
+		s.result.Added = append(s.result.Added, rel)
+		s.addCopy(srcPath, dstPath, srcInfo)
+		return nil
+	}
+
+	same, err := s.sameFile(srcPath, dstPath, srcInfo, dstInfo)
+	if err != nil {
+		return err
+	}
+	if !same {
+		s.result.Changed = append(s.result.Changed, rel)
+		s.addCopy(srcPath, dstPath, srcInfo)
+		return nil
+	}
+
+	s.result.Unchanged++
+	if srcInfo.Mode().IsRegular() && !srcInfo.ModTime().Equal(dstInfo.ModTime()) {
+		s.touches = append(s.touches, syncCopy{src: srcPath, dst: dstPath, info: srcInfo})
+	}
+	return nil
+}
+
+// sameFile 判断目标是否与源文件一致
+func (s *syncer) sameFile(srcPath, dstPath string, srcInfo, dstInfo os.FileInfo) (bool, error) {
+	if srcInfo.Mode().Type() != dstInfo.Mode().Type() {
+		return false, nil
+	}
+
+	switch {
+	case srcInfo.Mode()&os.ModeSymlink != 0:
+		srcTarget, err := os.Readlink(srcPath)
+		if err != nil {
+			return false, fmt.Errorf("failed to read symlink '%s': %w", srcPath, err)
+		}
+		dstTarget, err := os.Readlink(dstPath)
+		return err == nil && srcTarget == dstTarget, nil
+
+	case !srcInfo.Mode().IsRegular():
+		// 设备文件和命名管道只比较类型
+		return true, nil
+	}
+
+	if srcInfo.Size() != dstInfo.Size() {
+		return false, nil
+	}
+	if !s.opts.Checksum {
+		return srcInfo.ModTime().Equal(dstInfo.ModTime()), nil
+	}
+
+	srcSum, err := hash.Checksum(srcPath, s.algorithm)
+	if err != nil {
+		return false, fmt.Errorf("failed to checksum '%s': %w", srcPath, err)
+	}
+	dstSum, err := hash.Checksum(dstPath, s.algorithm)
+	if err != nil {
+		return false, fmt.Errorf("failed to checksum '%s': %w", dstPath, err)
+	}
+	return srcSum == dstSum, nil
+}
+
+// addCopy 记录需要复制的文件
+func (s *syncer) addCopy(srcPath, dstPath string, info os.FileInfo) {
+	s.copies = append(s.copies, syncCopy{src: srcPath, dst: dstPath, info: info})
+	if info.Mode().IsRegular() {
+		s.result.Bytes += info.Size()
+	}
+}
+
+// scanExtraneous 查找目标中源目录不存在的条目
+func (s *syncer) scanExtraneous() error {
+	if !isDir(s.dstAbs) {
+		return nil
+	}
+
+	return filepath.WalkDir(s.dstAbs, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to access path '%s': %w", path, err)
+		}
+		rel, err := filepath.Rel(s.dstAbs, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path for '%s': %w", path, err)
+		}
+		if rel == "." {
+			return nil
+		}
+		// 被过滤或忽略规则排除的条目受保护，不删除也不进入
+		// 过滤函数和忽略规则与复制阶段一致，按源目录中对应的路径（可能不存在）判断
+		srcPath := filepath.Join(s.srcAbs, rel)
+		if err := s.c.filterEntry(srcPath, rel, entry); err != nil {
+			if errors.Is(err, errSkipEntry) {
+				return nil
+			}
+			return err
+		}
+
+		srcInfo, err := os.Lstat(srcPath)
+		switch {
+		case err == nil && srcInfo.IsDir() == entry.IsDir():
+			return nil
+		case err != nil && !os.IsNotExist(err):
+			return fmt.Errorf("failed to get source info '%s': %w", srcPath, err)
+		case err == nil && !srcInfo.IsDir():
+			// 目标是目录而源是文件：由复制时的备份机制整体替换
+			return filepath.SkipDir
+		}
+
+		s.deletes = append(s.deletes, path)
+		s.result.Deleted = append(s.result.Deleted, filepath.ToSlash(rel))
+		if entry.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// apply 执行扫描得到的操作：删除多余条目 → 创建目录 → 复制文件 → 恢复目录元数据
+func (s *syncer) apply() error {
+	c := s.c
+
+	for _, path := range s.deletes {
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to delete '%s': %w", path, err)
+		}
+	}
+
+	for _, dir := range s.dirs {
+		// 目标位置是文件而源是目录时先删除文件
+		if info, err := os.Lstat(dir.dst); err == nil && !info.IsDir() {
+			if err := os.Remove(dir.dst); err != nil {
+				return fmt.Errorf("failed to replace '%s' with directory: %w", dir.dst, err)
+			}
+		}
+		if err := os.MkdirAll(dir.dst, writableDirMode(dir.info.Mode())); err != nil {
+			return fmt.Errorf("failed to create directory '%s': %w", dir.dst, err)
+		}
+		// 已存在的只读目录同样需要临时补齐写权限
+		if writableDirMode(dir.info.Mode()) != dir.info.Mode() {
+			_ = os.Chmod(dir.dst, writableDirMode(dir.info.Mode()))
+		}
+	}
+
+	if c.opts.Progress != nil {
+		c.progress.FilesTotal = len(s.copies)
+		c.progress.BytesTotal = s.result.Bytes
+	}
+	if err := s.copyFiles(); err != nil {
+		return err
+	}
+
+	for _, f := range s.touches {
+		if err := setFileTimes(f.dst, f.info); err != nil {
+			return fmt.Errorf("failed to set times of '%s': %w", f.dst, err)
+		}
+	}
+
+	// 目录元数据在所有修改完成后从深到浅恢复
+	return c.finishDirs(s.dirs)
+}
+
+// copyFiles 复制新增和变化的文件，设置了 Workers 时并行复制
+func (s *syncer) copyFiles() error {
+	c := s.c
+	if c.opts.Workers <= 1 {
+		for _, f := range s.copies {
+			if err := c.copyFileRouter(f.src, f.dst, f.info); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	queue := c.newCopyQueue(c.opts.Workers)
+	var submitErr error
+	for _, f := range s.copies {
+		if err := c.ctx.Err(); err != nil {
+			submitErr = err
+			break
+		}
+		if err := queue.submit(f.src, f.dst, f.info); err != nil {
+			break
+		}
+	}
+	return errors.Join(submitErr, queue.wait())
+}
@@ -0,0 +1,196 @@
+package fs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeSyncFile 写入测试文件并自动创建父目录
+func writeSyncFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+}
+
+// checkSync 检查同步结果的各类文件列表
+func checkSync(t *testing.T, result *SyncResult, added, changed, deleted []string, unchanged int) {
+	t.Helper()
+
+	for _, list := range [][]string{result.Added, result.Changed, result.Deleted} {
+		slices.Sort(list)
+	}
+	if !slices.Equal(result.Added, added) {
+		t.Errorf("Added = %v, want %v", result.Added, added)
+	}
+	if !slices.Equal(result.Changed, changed) {
+		t.Errorf("Changed = %v, want %v", result.Changed, changed)
+	}
+	if !slices.Equal(result.Deleted, deleted) {
+		t.Errorf("Deleted = %v, want %v", result.Deleted, deleted)
+	}
+	if result.Unchanged != unchanged {
+		t.Errorf("Unchanged = %d, want %d", result.Unchanged, unchanged)
+	}
+}
+
+func TestSync(t *testing.T) {
+	for _, workers := range []int{0, 4} {
+		t.Run(map[int]string{0: "Sequential", 4: "Parallel"}[workers], func(t *testing.T) {
+			src := filepath.Join(t.TempDir(), "src")
+			dst := filepath.Join(t.TempDir(), "dst")
+			writeSyncFile(t, filepath.Join(src, "a.txt"), "alpha")
+			writeSyncFile(t, filepath.Join(src, "sub", "b.txt"), "beta")
+			writeSyncFile(t, filepath.Join(src, "sub", "c.txt"), "gamma")
+
+			opts := &SyncOptions{Delete: true, CopyOptions: CopyOptions{Workers: workers, Exclude: []string{"*.keep"}}}
+			result, err := Sync(src, dst, opts)
+			if err != nil {
+				t.Fatalf("Sync failed: %v", err)
+			}
+			checkSync(t, result, []string{"a.txt", "sub/b.txt", "sub/c.txt"}, nil, nil, 0)
+			if result.Bytes != 14 {
+				t.Errorf("Bytes = %d, want 14", result.Bytes)
+			}
+
+			// 再次同步没有变化
+			result, err = Sync(src, dst, opts)
+			if err != nil {
+				t.Fatalf("Sync failed: %v", err)
+			}
+			checkSync(t, result, nil, nil, nil, 3)
+
+			// 修改、新增、删除源文件，目标中被排除的文件受保护
+			writeSyncFile(t, filepath.Join(src, "sub", "b.txt"), "beta v2")
+			writeSyncFile(t, filepath.Join(src, "new", "d.txt"), "delta")
+			if err := os.Remove(filepath.Join(src, "sub", "c.txt")); err != nil {
+				t.Fatalf("Remove failed: %v", err)
+			}
+			writeSyncFile(t, filepath.Join(dst, "local.keep"), "keep me")
+			writeSyncFile(t, filepath.Join(dst, "stale", "x.txt"), "stale")
+
+			result, err = Sync(src, dst, opts)
+			if err != nil {
+				t.Fatalf("Sync failed: %v", err)
+			}
+			checkSync(t, result, []string{"new/d.txt"}, []string{"sub/b.txt"}, []string{"stale", "sub/c.txt"}, 1)
+
+			want := []string{"a.txt", "local.keep", "new/d.txt", "sub/b.txt"}
+			if got := listFiles(t, dst); !slices.Equal(got, want) {
+				t.Errorf("Destination files = %v, want %v", got, want)
+			}
+			validateFileCopy(t, filepath.Join(src, "sub", "b.txt"), filepath.Join(dst, "sub", "b.txt"))
+		})
+	}
+}
+
+func TestSyncDryRun(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	dst := filepath.Join(t.TempDir(), "dst")
+	writeSyncFile(t, filepath.Join(src, "a.txt"), "alpha")
+	writeSyncFile(t, filepath.Join(dst, "old.txt"), "old")
+
+	result, err := Sync(src, dst, &SyncOptions{Delete: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	checkSync(t, result, []string{"a.txt"}, nil, []string{"old.txt"}, 0)
+	if got := listFiles(t, dst); !slices.Equal(got, []string{"old.txt"}) {
+		t.Errorf("Dry run modified destination: %v", got)
+	}
+}
+
+func TestSyncDeleteFilter(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	dst := filepath.Join(t.TempDir(), "dst")
+	writeSyncFile(t, filepath.Join(src, "a.txt"), "alpha")
+	writeSyncFile(t, filepath.Join(dst, "old.txt"), "old")
+	writeSyncFile(t, filepath.Join(dst, "local", "x.txt"), "local")
+
+	// 删除阶段的过滤函数同样收到源目录中的路径
+	var outside []string
+	opts := &SyncOptions{Delete: true, CopyOptions: CopyOptions{
+		Filter: func(path string, d fs.DirEntry) bool {
+			if !strings.HasPrefix(path, src+string(filepath.Separator)) {
+				outside = append(outside, path)
+			}
+			return path != filepath.Join(src, "local")
+		},
+	}}
+	result, err := Sync(src, dst, opts)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	checkSync(t, result, []string{"a.txt"}, nil, []string{"old.txt"}, 0)
+	if len(outside) != 0 {
+		t.Errorf("Filter received paths outside source: %v", outside)
+	}
+	if !Exists(filepath.Join(dst, "local", "x.txt")) {
+		t.Error("filtered destination directory was deleted")
+	}
+}
+
+func TestSyncChecksum(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	dst := filepath.Join(t.TempDir(), "dst")
+	writeSyncFile(t, filepath.Join(src, "same.txt"), "same")
+	writeSyncFile(t, filepath.Join(src, "edit.txt"), "aaaa")
+	if _, err := Sync(src, dst, nil); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// same.txt 只改变修改时间；edit.txt 改变内容但保持大小和修改时间
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(src, "same.txt"), later, later); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+	info, _ := os.Stat(filepath.Join(src, "edit.txt"))
+	writeSyncFile(t, filepath.Join(src, "edit.txt"), "bbbb")
+	if err := os.Chtimes(filepath.Join(src, "edit.txt"), info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	result, err := Sync(src, dst, &SyncOptions{Checksum: true, Algorithm: "md5"})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	checkSync(t, result, nil, []string{"edit.txt"}, nil, 1)
+	validateFileCopy(t, filepath.Join(src, "edit.txt"), filepath.Join(dst, "edit.txt"))
+
+	// 内容相同的文件只更新修改时间
+	dstInfo, _ := os.Stat(filepath.Join(dst, "same.txt"))
+	if !dstInfo.ModTime().Equal(later) {
+		t.Errorf("ModTime = %v, want %v", dstInfo.ModTime(), later)
+	}
+
+	if _, err := Sync(src, dst, &SyncOptions{Checksum: true, Algorithm: "crc"}); err == nil {
+		t.Error("Expected unsupported algorithm error")
+	}
+}
+
+func TestSyncTypeChange(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	dst := filepath.Join(t.TempDir(), "dst")
+	writeSyncFile(t, filepath.Join(src, "item"), "now a file")
+	writeSyncFile(t, filepath.Join(src, "folder", "f.txt"), "inside")
+	writeSyncFile(t, filepath.Join(dst, "item", "old.txt"), "was a directory")
+	writeSyncFile(t, filepath.Join(dst, "folder"), "was a file")
+
+	result, err := Sync(src, dst, &SyncOptions{Delete: true})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	checkSync(t, result, []string{"folder/f.txt"}, []string{"item"}, []string{"folder"}, 0)
+	if got, want := listFiles(t, dst), []string{"folder/f.txt", "item"}; !slices.Equal(got, want) {
+		t.Errorf("Destination files = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,382 @@
+package fs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrTrashUnsupported 表示当前平台不支持回收站
+var ErrTrashUnsupported = errors.New("trash not supported")
+
+const (
+	trashInfoHeader = "[Trash Info]"        // .trashinfo 文件的组名
+	trashInfoExt    = ".trashinfo"          // 元数据文件扩展名
+	trashDateLayout = "2006-01-02T15:04:05" // DeletionDate 的格式（本地时间）
+)
+
+// TrashItem 回收站中的条目
+type TrashItem struct {
+	Name         string    // 在回收站中的名称（files 目录中的文件名），同一回收站内唯一
+	Path         string    // 在回收站中的当前路径
+	OriginalPath string    // 删除前的绝对路径
+	DeletionDate time.Time // 删除时间
+
+	infoPath string // .trashinfo 元数据文件路径
+}
+
+// trashDir 回收站目录
+type trashDir struct {
+	path   string // 回收站目录，包含 files 和 info 子目录
+	topdir string // 卷回收站所在卷的挂载点，原路径相对该目录记录；主目录回收站为空，记录绝对路径
+}
+
+// Trash 将文件或目录移入回收站（遵循 freedesktop.org Trash 规范，仅 Linux）
+// 与用户主目录在同一文件系统时使用 $XDG_DATA_HOME/Trash（默认 ~/.local/share/Trash），
+// 其他文件系统优先使用该卷的 $topdir/.Trash/$uid 或 $topdir/.Trash-$uid，
+// 无法使用卷回收站时回退到主目录回收站（通过 MoveEx 跨文件系统复制后删除）
+// 符号链接本身被移入回收站，不跟随
+//
+// 参数:
+//   - path: 文件或目录路径
+//
+// 返回:
+//   - *TrashItem: 回收站中的条目，可用于恢复
+//   - error: 路径不存在、平台不支持（ErrTrashUnsupported）或移动失败时返回错误
+//
+// 示例:
+//
+//	item, err := fs.Trash("report.docx")
+//	if err != nil {
+//	    return err
+//	}
+//	// 撤销删除
+//	err = item.Restore()
+func Trash(path string) (*TrashItem, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for '%s': %w", path, err)
+	}
+	info, err := os.Lstat(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get path info '%s': %w", absPath, err)
+	}
+	if filepath.Dir(absPath) == absPath {
+		return nil, fmt.Errorf("cannot move root directory '%s' to trash", absPath)
+	}
+
+	td, err := findTrash(absPath, info)
+	if err != nil {
+		return nil, err
+	}
+	return td.trash(absPath, time.Now())
+}
+
+// ListTrash 列出当前用户所有回收站中的条目（主目录回收站和各挂载卷的回收站）
+// 无效或缺少对应文件的 .trashinfo 元数据被跳过
+//
+// 返回:
+//   - []TrashItem: 回收站条目，按删除时间从新到旧排序
+//   - error: 平台不支持（ErrTrashUnsupported）或读取回收站失败时返回错误
+func ListTrash() ([]TrashItem, error) {
+	dirs, err := listTrashDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []TrashItem
+	for _, td := range dirs {
+		dirItems, err := td.list()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, dirItems...)
+	}
+	sortTrashItems(items)
+	return items, nil
+}
+
+// EmptyTrash 永久删除当前用户所有回收站中的内容
+//
+// 返回:
+//   - error: 平台不支持（ErrTrashUnsupported）或删除失败时返回错误，多个错误通过 errors.Join 合并
+func EmptyTrash() error {
+	dirs, err := listTrashDirs()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, td := range dirs {
+		errs = append(errs, td.empty())
+	}
+	return errors.Join(errs...)
+}
+
+// Restore 将条目移回原路径，原路径的父目录不存在时自动创建
+// 原路径已存在时不覆盖并返回错误；跨文件系统时通过 MoveEx 复制后删除
+//
+// 返回:
+//   - error: 原路径已存在或移动失败时返回错误
+func (item TrashItem) Restore() error {
+	if _, err := os.Lstat(item.OriginalPath); err == nil {
+		return fmt.Errorf("cannot restore '%s': destination already exists", item.OriginalPath)
+	}
+	if err := MoveEx(item.Path, item.OriginalPath, false); err != nil {
+		return fmt.Errorf("failed to restore '%s': %w", item.OriginalPath, err)
+	}
+	if err := os.Remove(item.infoPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove trash info '%s': %w", item.infoPath, err)
+	}
+	return nil
+}
+
+// Delete 从回收站中永久删除条目
+//
+// 返回:
+//   - error: 删除失败时返回错误
+func (item TrashItem) Delete() error {
+	// 先删除文件再删除元数据，删除中断时条目仍可被列出
+	if err := os.RemoveAll(item.Path); err != nil {
+		return fmt.Errorf("failed to delete '%s': %w", item.Path, err)
+	}
+	if err := os.Remove(item.infoPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove trash info '%s': %w", item.infoPath, err)
+	}
+	return nil
+}
+
+// homeTrashDir 返回主目录回收站 $XDG_DATA_HOME/Trash，XDG_DATA_HOME 未设置或不是绝对路径时使用 ~/.local/share
+func homeTrashDir() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" || !filepath.IsAbs(dataHome) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "Trash"), nil
+}
+
+// ensure 创建回收站的 files 和 info 子目录（仅所有者可访问）
+func (td trashDir) ensure() error {
+	for _, sub := range []string{"files", "info"} {
+		dir := filepath.Join(td.path, sub)
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("failed to create trash directory '%s': %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// trash 将路径移入回收站：先以独占方式创建 .trashinfo 预留名称，再移动文件，移动失败时删除元数据
+//
+// 参数:
+//   - absPath: 绝对路径
+//   - now: 删除时间
+//
+// 返回:
+//   - *TrashItem: 回收站条目
+//   - error: 创建元数据或移动失败时返回错误
+func (td trashDir) trash(absPath string, now time.Time) (*TrashItem, error) {
+	if err := td.ensure(); err != nil {
+		return nil, err
+	}
+
+	original := absPath
+	if td.topdir != "" {
+		rel, err := filepath.Rel(td.topdir, absPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get relative path for '%s': %w", absPath, err)
+		}
+		original = rel
+	}
+	// 秒以下的精度不会被记录，返回的条目与之后列出的保持一致
+	now = now.Truncate(time.Second)
+	content := fmt.Sprintf("%s\nPath=%s\nDeletionDate=%s\n",
+		trashInfoHeader, (&url.URL{Path: filepath.ToSlash(original)}).EscapedPath(), now.Format(trashDateLayout))
+
+	base := filepath.Base(absPath)
+	for n := 1; ; n++ {
+		name := base
+		if n > 1 {
+			name = base + "." + strconv.Itoa(n)
+		}
+		item := &TrashItem{
+			Name:         name,
+			Path:         filepath.Join(td.path, "files", name),
+			OriginalPath: absPath,
+			DeletionDate: now,
+			infoPath:     filepath.Join(td.path, "info", name+trashInfoExt),
+		}
+		if _, err := os.Lstat(item.Path); err == nil {
+			continue
+		}
+
+		f, err := os.OpenFile(item.infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create trash info '%s': %w", item.infoPath, err)
+		}
+		_, err = f.WriteString(content)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(item.infoPath)
+			return nil, fmt.Errorf("failed to write trash info '%s': %w", item.infoPath, err)
+		}
+
+		if err := MoveEx(absPath, item.Path, false); err != nil {
+			_ = os.Remove(item.infoPath)
+			return nil, fmt.Errorf("failed to move '%s' to trash: %w", absPath, err)
+		}
+		return item, nil
+	}
+}
+
+// list 读取回收站中的条目，跳过无效或缺少对应文件的元数据
+func (td trashDir) list() ([]TrashItem, error) {
+	infoDir := filepath.Join(td.path, "info")
+	entries, err := os.ReadDir(infoDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash directory '%s': %w", infoDir, err)
+	}
+
+	var items []TrashItem
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), trashInfoExt)
+		if !ok || name == "" || entry.IsDir() {
+			continue
+		}
+		infoPath := filepath.Join(infoDir, entry.Name())
+		data, err := os.ReadFile(infoPath)
+		if err != nil {
+			continue
+		}
+		original, date, err := parseTrashInfo(data)
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(original) {
+			if td.topdir == "" {
+				continue
+			}
+			original = filepath.Join(td.topdir, original)
+		}
+
+		item := TrashItem{
+			Name:         name,
+			Path:         filepath.Join(td.path, "files", name),
+			OriginalPath: original,
+			DeletionDate: date,
+			infoPath:     infoPath,
+		}
+		if _, err := os.Lstat(item.Path); err != nil {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// empty 删除回收站中的所有文件和元数据（先删除文件再删除元数据）
+func (td trashDir) empty() error {
+	var errs []error
+	for _, sub := range []string{"files", "info"} {
+		dir := filepath.Join(td.path, sub)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				errs = append(errs, fmt.Errorf("failed to read trash directory '%s': %w", dir, err))
+			}
+			continue
+		}
+		for _, entry := range entries {
+			p := filepath.Join(dir, entry.Name())
+			if err := os.RemoveAll(p); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete '%s': %w", p, err))
+			}
+		}
+	}
+	// 目录大小缓存随内容一起失效
+	if err := os.Remove(filepath.Join(td.path, "directorysizes")); err != nil && !os.IsNotExist(err) {
+		errs = append(errs, fmt.Errorf("failed to remove directory size cache: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// parseTrashInfo 解析 .trashinfo 元数据
+//
+// 参数:
+//   - data: 文件内容
+//
+// 返回:
+//   - string: 原路径（已解码，使用系统路径分隔符；卷回收站中为相对挂载点的路径）
+//   - time.Time: 删除时间，缺失或无效时为零值
+//   - error: 缺少 [Trash Info] 组或 Path 键时返回错误
+func parseTrashInfo(data []byte) (string, time.Time, error) {
+	var (
+		original string
+		date     time.Time
+		inGroup  bool
+		seen     bool
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			inGroup = line == trashInfoHeader
+			seen = seen || inGroup
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !inGroup || !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Path":
+			p, err := url.PathUnescape(strings.TrimSpace(value))
+			if err != nil {
+				return "", time.Time{}, fmt.Errorf("invalid trash info path %q: %w", value, err)
+			}
+			original = filepath.FromSlash(p)
+		case "DeletionDate":
+			date, _ = time.ParseInLocation(trashDateLayout, strings.TrimSpace(value), time.Local)
+		}
+	}
+	if !seen || original == "" {
+		return "", time.Time{}, fmt.Errorf("invalid trash info: missing %s group or Path key", trashInfoHeader)
+	}
+	return original, date, nil
+}
+
+// sortTrashItems 按删除时间从新到旧排序，时间相同时按原路径排序
+func sortTrashItems(items []TrashItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].DeletionDate.Equal(items[j].DeletionDate) {
+			return items[i].DeletionDate.After(items[j].DeletionDate)
+		}
+		return items[i].OriginalPath < items[j].OriginalPath
+	})
+}
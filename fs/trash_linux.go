@@ -0,0 +1,190 @@
+//go:build linux
+
+package fs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// findTrash 选择路径所在文件系统对应的回收站
+// 与主目录回收站在同一文件系统时使用主目录回收站，否则优先使用卷回收站，无法使用时回退到主目录回收站
+//
+// 参数:
+//   - absPath: 绝对路径
+//   - info: 路径的 Lstat 结果
+//
+// 返回:
+//   - trashDir: 回收站目录
+//   - error: 无法创建主目录回收站时返回错误
+func findTrash(absPath string, info os.FileInfo) (trashDir, error) {
+	homePath, err := homeTrashDir()
+	if err != nil {
+		return trashDir{}, err
+	}
+	home := trashDir{path: homePath}
+	if err := home.ensure(); err != nil {
+		return trashDir{}, err
+	}
+
+	homeInfo, err := os.Stat(homePath)
+	if err != nil {
+		return trashDir{}, fmt.Errorf("failed to get trash directory info '%s': %w", homePath, err)
+	}
+	dev, ok := fileDevice(info)
+	if homeDev, homeOK := fileDevice(homeInfo); !ok || !homeOK || dev == homeDev {
+		return home, nil
+	}
+
+	topdir := mountPoint(absPath, dev)
+	if td, ok := volumeTrash(topdir, true); ok {
+		return td, nil
+	}
+	return home, nil
+}
+
+// listTrashDirs 返回当前用户所有已存在的回收站：主目录回收站和各挂载卷的回收站
+func listTrashDirs() ([]trashDir, error) {
+	homePath, err := homeTrashDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := []trashDir{{path: homePath}}
+	seen := map[string]bool{homePath: true}
+	for _, topdir := range mountPoints() {
+		td, ok := volumeTrash(topdir, false)
+		if !ok || seen[td.path] {
+			continue
+		}
+		seen[td.path] = true
+		dirs = append(dirs, td)
+	}
+	return dirs, nil
+}
+
+// volumeTrash 返回挂载卷上当前用户的回收站
+// 优先使用管理员创建的 $topdir/.Trash/$uid（.Trash 必须是设置了粘滞位的真实目录），其次使用 $topdir/.Trash-$uid
+// 回收站目录必须是当前用户所有的真实目录，否则视为不可用
+//
+// 参数:
+//   - topdir: 挂载点
+//   - create: 回收站不存在时是否创建
+//
+// 返回:
+//   - trashDir: 回收站目录
+//   - bool: 是否可用
+func volumeTrash(topdir string, create bool) (trashDir, bool) {
+	uid := strconv.Itoa(os.Getuid())
+
+	candidates := []string{filepath.Join(topdir, ".Trash-"+uid)}
+	shared := filepath.Join(topdir, ".Trash")
+	if info, err := os.Lstat(shared); err == nil && info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		candidates = append([]string{filepath.Join(shared, uid)}, candidates...)
+	}
+
+	for _, dir := range candidates {
+		td := trashDir{path: dir, topdir: topdir}
+		if create {
+			// 只创建回收站目录本身，不创建 .Trash
+			if err := os.Mkdir(dir, 0o700); err != nil && !os.IsExist(err) {
+				continue
+			}
+		}
+		if !ownedDir(dir) {
+			continue
+		}
+		if create && td.ensure() != nil {
+			continue
+		}
+		return td, true
+	}
+	return trashDir{}, false
+}
+
+// ownedDir 判断路径是否为当前用户所有的真实目录（不是符号链接）
+func ownedDir(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(stat.Uid) == os.Getuid()
+}
+
+// fileDevice 返回文件所在的设备号
+func fileDevice(info os.FileInfo) (uint64, bool) {
+	key, _, ok := fileInode(info)
+	return key.dev, ok
+}
+
+// mountPoint 向上查找路径所在文件系统的挂载点（设备号发生变化前的最上层目录）
+//
+// 参数:
+//   - absPath: 绝对路径
+//   - dev: 路径所在的设备号
+//
+// 返回:
+//   - string: 挂载点
+func mountPoint(absPath string, dev uint64) string {
+	dir := absPath
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		info, err := os.Lstat(parent)
+		if err != nil {
+			return dir
+		}
+		if d, ok := fileDevice(info); !ok || d != dev {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// mountPoints 读取 /proc/self/mounts 返回所有挂载点，读取失败时返回 nil
+func mountPoints() []string {
+	f, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = f.Close() }()
+
+	var points []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		points = append(points, unescapeMountPath(fields[1]))
+	}
+	return points
+}
+
+// unescapeMountPath 还原挂载点中以八进制转义的空白字符和反斜杠（如 \040 表示空格）
+func unescapeMountPath(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				sb.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
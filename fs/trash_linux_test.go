@@ -0,0 +1,186 @@
+//go:build linux
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listTrashUnder 列出原路径位于 dir 下的回收站条目
+func listTrashUnder(t *testing.T, dir string) []TrashItem {
+	t.Helper()
+
+	items, err := ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+	var result []TrashItem
+	for _, item := range items {
+		if strings.HasPrefix(item.OriginalPath, dir+string(filepath.Separator)) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func TestTrash(t *testing.T) {
+	dataHome := filepath.Join(t.TempDir(), "data")
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	work := t.TempDir()
+
+	file := filepath.Join(work, "my file%.txt")
+	writeSyncFile(t, file, "first")
+	writeSyncFile(t, filepath.Join(work, "sub", "b.txt"), "b")
+
+	item, err := Trash(file)
+	if err != nil {
+		t.Fatalf("Trash failed: %v", err)
+	}
+	if Exists(file) {
+		t.Error("original file still exists")
+	}
+	if want := filepath.Join(dataHome, "Trash", "files", "my file%.txt"); item.Path != want {
+		t.Errorf("Path = %q, want %q", item.Path, want)
+	}
+	info, err := os.ReadFile(filepath.Join(dataHome, "Trash", "info", "my file%.txt.trashinfo"))
+	if err != nil {
+		t.Fatalf("trash info not written: %v", err)
+	}
+	escaped := strings.ReplaceAll(strings.ReplaceAll(file, "%", "%25"), " ", "%20")
+	if !strings.HasPrefix(string(info), "[Trash Info]\nPath="+escaped+"\nDeletionDate=") {
+		t.Errorf("trash info = %q", info)
+	}
+
+	// 同名文件使用不同的名称
+	writeSyncFile(t, file, "second")
+	second, err := Trash(file)
+	if err != nil {
+		t.Fatalf("Trash failed: %v", err)
+	}
+	if second.Name != "my file%.txt.2" {
+		t.Errorf("Name = %q, want %q", second.Name, "my file%.txt.2")
+	}
+	if _, err := Trash(filepath.Join(work, "sub")); err != nil {
+		t.Fatalf("Trash directory failed: %v", err)
+	}
+
+	items := listTrashUnder(t, work)
+	if len(items) != 3 {
+		t.Fatalf("ListTrash returned %d items, want 3: %+v", len(items), items)
+	}
+	for _, it := range items {
+		if time.Since(it.DeletionDate) > time.Minute {
+			t.Errorf("DeletionDate = %v, want recent", it.DeletionDate)
+		}
+	}
+
+	// 恢复：原路径已存在时不覆盖
+	if err := second.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "second" {
+		t.Errorf("restored content = %q, want %q", data, "second")
+	}
+	if err := item.Restore(); err == nil {
+		t.Error("Restore over existing file: expected error")
+	}
+
+	// 恢复目录时重新创建父目录
+	for _, it := range listTrashUnder(t, work) {
+		if it.Name == "sub" {
+			if err := it.Restore(); err != nil {
+				t.Fatalf("Restore directory failed: %v", err)
+			}
+		}
+	}
+	if !Exists(filepath.Join(work, "sub", "b.txt")) {
+		t.Error("directory not restored")
+	}
+
+	if err := item.Delete(); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if items := listTrashUnder(t, work); len(items) != 0 {
+		t.Errorf("trash not empty after restore and delete: %+v", items)
+	}
+
+	if _, err := Trash(filepath.Join(work, "missing")); err == nil {
+		t.Error("Trash of missing path: expected error")
+	}
+}
+
+func TestVolumeTrash(t *testing.T) {
+	top := t.TempDir()
+	file := filepath.Join(top, "dir", "x.txt")
+	writeSyncFile(t, file, "x")
+
+	td := trashDir{path: filepath.Join(top, ".Trash-"+strconv.Itoa(os.Getuid())), topdir: top}
+	deleted := time.Date(2024, 5, 6, 7, 8, 9, 500, time.Local)
+	item, err := td.trash(file, deleted)
+	if err != nil {
+		t.Fatalf("trash failed: %v", err)
+	}
+
+	// 卷回收站记录相对挂载点的路径
+	info, err := os.ReadFile(item.infoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[Trash Info]\nPath=dir/x.txt\nDeletionDate=2024-05-06T07:08:09\n"; string(info) != want {
+		t.Errorf("trash info = %q, want %q", info, want)
+	}
+
+	items, err := td.list()
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(items) != 1 || items[0].OriginalPath != file || !items[0].DeletionDate.Equal(item.DeletionDate) {
+		t.Fatalf("list = %+v, want original %q at %v", items, file, item.DeletionDate)
+	}
+
+	if err := td.empty(); err != nil {
+		t.Fatalf("empty failed: %v", err)
+	}
+	if items, _ := td.list(); len(items) != 0 {
+		t.Errorf("list after empty = %+v", items)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(td.path, "files")); len(entries) != 0 {
+		t.Errorf("files left after empty: %v", entries)
+	}
+}
+
+func TestParseTrashInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{"valid", "[Trash Info]\nPath=/a/b%20c\nDeletionDate=2024-01-02T03:04:05\n", "/a/b c", false},
+		{"crlf and comments", "# note\r\n[Trash Info]\r\nPath=rel/x\r\n", "rel/x", false},
+		{"missing group", "Path=/a\n", "", true},
+		{"missing path", "[Trash Info]\nDeletionDate=2024-01-02T03:04:05\n", "", true},
+		{"other group", "[Other]\nPath=/a\n", "", true},
+		{"bad escape", "[Trash Info]\nPath=/a%zz\n", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := parseTrashInfo([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("path = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := unescapeMountPath(`/mnt/my\040disk\134x`); got != `/mnt/my disk\x` {
+		t.Errorf("unescapeMountPath = %q", got)
+	}
+}
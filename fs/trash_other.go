@@ -0,0 +1,15 @@
+//go:build !linux
+
+package fs
+
+import "os"
+
+// findTrash 当前平台不支持 freedesktop.org 回收站
+func findTrash(absPath string, info os.FileInfo) (trashDir, error) {
+	return trashDir{}, ErrTrashUnsupported
+}
+
+// listTrashDirs 当前平台不支持 freedesktop.org 回收站
+func listTrashDirs() ([]trashDir, error) {
+	return nil, ErrTrashUnsupported
+}
@@ -0,0 +1,297 @@
+package fs
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+)
+
+// walkBatchSize 不排序遍历时每批读取的目录项数量
+const walkBatchSize = 256
+
+// WalkType 遍历时返回的条目类型，可以组合使用
+type WalkType uint
+
+const (
+	WalkFiles    WalkType = 1 << iota // 普通文件
+	WalkDirs                          // 目录
+	WalkSymlinks                      // 符号链接（跟随符号链接时只包含无法解析的链接）
+	WalkOther                         // 设备文件、命名管道、套接字等特殊文件
+
+	WalkAll = WalkFiles | WalkDirs | WalkSymlinks | WalkOther // 所有类型
+)
+
+// WalkOptions 目录遍历选项
+type WalkOptions struct {
+	// Types 返回的条目类型，0 表示返回所有类型；未返回的目录仍会被遍历
+	Types WalkType
+
+	// MaxDepth 最大遍历深度，根目录的直接子条目深度为 1，0 表示不限制
+	MaxDepth int
+
+	// FollowSymlinks 跟随指向目录的符号链接，检测到指向祖先目录的循环时返回错误且不进入该链接
+	FollowSymlinks bool
+
+	// SkipHidden 跳过隐藏文件和目录（按 IsHidden 判断），隐藏目录的内容也不会遍历
+	SkipHidden bool
+
+	// Ignore gitignore 语义的忽略规则，被忽略的条目跳过，被忽略目录的内容也不会遍历
+	Ignore *IgnoreMatcher
+
+	// Sorted 按名称排序遍历（深度优先、与 filepath.WalkDir 顺序相同）
+	// 默认按目录中的存储顺序分批读取，先返回当前目录的条目再进入子目录，大目录占用的内存更少
+	Sorted bool
+
+	// ContinueOnError 遇到无法读取的目录或条目时返回错误并继续遍历，默认返回错误后结束
+	ContinueOnError bool
+}
+
+// Entry 遍历得到的条目，嵌入的 DirEntry 提供 Name、IsDir、Type 和 Info 方法
+type Entry struct {
+	fs.DirEntry
+
+	Path  string // 完整路径（根路径与相对路径拼接）
+	Rel   string // 相对遍历根目录的路径（使用系统路径分隔符，根路径本身为 "."）
+	Depth int    // 深度，根路径为 0
+}
+
+// Walk 以迭代器的形式流式遍历目录，不会在内存中收集所有路径
+// 根路径本身不返回（根路径为文件时只返回该文件），根路径为符号链接时总是跟随
+// 出错时返回的 Entry 包含出错的路径；在 range 循环中 break 即可提前结束遍历
+//
+// 参数:
+//   - root: 遍历的根路径
+//   - opts: 遍历选项，为 nil 时返回所有条目
+//
+// 返回:
+//   - iter.Seq2[Entry, error]: 条目迭代器
+//
+// 示例:
+//
+//	for entry, err := range fs.Walk("src", &fs.WalkOptions{Types: fs.WalkFiles, SkipHidden: true}) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(entry.Rel)
+//	}
+func Walk(root string, opts *WalkOptions) iter.Seq2[Entry, error] {
+	if opts == nil {
+		opts = &WalkOptions{}
+	}
+	types := opts.Types
+	if types == 0 {
+		types = WalkAll
+	}
+
+	return func(yield func(Entry, error) bool) {
+		if root == "" {
+			yield(Entry{}, fmt.Errorf("walk root cannot be empty"))
+			return
+		}
+		info, err := os.Stat(root)
+		if err != nil {
+			yield(Entry{Path: root, Rel: "."}, fmt.Errorf("failed to get path info '%s': %w", root, err))
+			return
+		}
+
+		rootEntry := Entry{DirEntry: fs.FileInfoToDirEntry(info), Path: root, Rel: ".", Depth: 0}
+		if !info.IsDir() {
+			if types&entryWalkType(rootEntry) != 0 {
+				yield(rootEntry, nil)
+			}
+			return
+		}
+
+		w := &walker{opts: opts, types: types, yield: yield, ancestors: make(map[string]bool)}
+		if opts.Ignore != nil {
+			if w.absRoot, err = filepath.Abs(root); err != nil {
+				yield(rootEntry, fmt.Errorf("failed to get absolute path for '%s': %w", root, err))
+				return
+			}
+		}
+		w.walkDir(rootEntry)
+	}
+}
+
+// walker 单次遍历的内部状态
+type walker struct {
+	opts      *WalkOptions
+	types     WalkType
+	yield     func(Entry, error) bool
+	ancestors map[string]bool // 当前路径上的目录标识，用于检测符号链接循环
+	absRoot   string          // 根路径的绝对路径，用于匹配忽略规则
+}
+
+// walkDir 遍历目录中的条目
+//
+// 参数:
+//   - dir: 目录条目
+//
+// 返回:
+//   - bool: false 表示遍历已结束（调用方停止迭代或出错）
+func (w *walker) walkDir(dir Entry) bool {
+	if w.opts.MaxDepth > 0 && dir.Depth >= w.opts.MaxDepth {
+		return true
+	}
+
+	if w.opts.FollowSymlinks {
+		key, err := dirKey(dir)
+		if err != nil {
+			return w.fail(dir, err)
+		}
+		if w.ancestors[key] {
+			return w.fail(dir, fmt.Errorf("symlink loop detected at '%s'", dir.Path))
+		}
+		w.ancestors[key] = true
+		defer delete(w.ancestors, key)
+	}
+
+	if w.opts.Sorted {
+		entries, err := os.ReadDir(dir.Path)
+		// ReadDir 出错时仍返回已读取的条目
+		for _, d := range entries {
+			entry, descend, ok := w.visit(dir, d)
+			if !ok {
+				return false
+			}
+			if descend && !w.walkDir(entry) {
+				return false
+			}
+		}
+		if err != nil {
+			return w.fail(dir, fmt.Errorf("failed to read directory '%s': %w", dir.Path, err))
+		}
+		return true
+	}
+
+	// 分批读取，子目录在关闭当前目录后再进入，避免深层目录占用过多文件描述符
+	f, err := os.Open(dir.Path)
+	if err != nil {
+		return w.fail(dir, fmt.Errorf("failed to open directory '%s': %w", dir.Path, err))
+	}
+	var subdirs []Entry
+	for {
+		batch, err := f.ReadDir(walkBatchSize)
+		for _, d := range batch {
+			entry, descend, ok := w.visit(dir, d)
+			if !ok {
+				_ = f.Close()
+				return false
+			}
+			if descend {
+				subdirs = append(subdirs, entry)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = f.Close()
+			if !w.fail(dir, fmt.Errorf("failed to read directory '%s': %w", dir.Path, err)) {
+				return false
+			}
+			break
+		}
+	}
+	_ = f.Close()
+
+	for _, sub := range subdirs {
+		if !w.walkDir(sub) {
+			return false
+		}
+	}
+	return true
+}
+
+// visit 处理目录中的单个条目：应用隐藏文件过滤、按需解析符号链接、应用忽略规则，类型匹配时返回给调用方
+//
+// 参数:
+//   - parent: 父目录条目
+//   - d: 目录项
+//
+// 返回:
+//   - Entry: 条目
+//   - bool: 条目是否为需要进入的目录
+//   - bool: 是否继续遍历
+func (w *walker) visit(parent Entry, d fs.DirEntry) (Entry, bool, bool) {
+	entry := Entry{
+		DirEntry: d,
+		Path:     filepath.Join(parent.Path, d.Name()),
+		Rel:      d.Name(),
+		Depth:    parent.Depth + 1,
+	}
+	if parent.Rel != "." {
+		entry.Rel = filepath.Join(parent.Rel, d.Name())
+	}
+
+	if w.opts.SkipHidden && IsHidden(entry.Path) {
+		return entry, false, true
+	}
+
+	if w.opts.FollowSymlinks && d.Type()&fs.ModeSymlink != 0 {
+		info, err := os.Stat(entry.Path)
+		switch {
+		case err == nil:
+			entry.DirEntry = fs.FileInfoToDirEntry(info)
+		case !os.IsNotExist(err):
+			// 无法解析的链接按符号链接本身返回，其他错误（如权限不足）上报
+			return entry, false, w.fail(entry, fmt.Errorf("failed to resolve symlink '%s': %w", entry.Path, err))
+		}
+	}
+
+	if w.opts.Ignore != nil {
+		ignored, err := w.opts.Ignore.match(filepath.Join(w.absRoot, entry.Rel), entry.IsDir())
+		if err != nil {
+			return entry, false, w.fail(entry, err)
+		}
+		if ignored {
+			return entry, false, true
+		}
+	}
+
+	if w.types&entryWalkType(entry) != 0 && !w.yield(entry, nil) {
+		return entry, false, false
+	}
+	return entry, entry.IsDir(), true
+}
+
+// fail 返回错误，根据 ContinueOnError 决定是否继续遍历
+func (w *walker) fail(entry Entry, err error) bool {
+	if !w.yield(entry, err) {
+		return false
+	}
+	return w.opts.ContinueOnError
+}
+
+// entryWalkType 返回条目对应的类型
+func entryWalkType(entry Entry) WalkType {
+	switch t := entry.Type(); {
+	case t.IsDir():
+		return WalkDirs
+	case t.IsRegular():
+		return WalkFiles
+	case t&fs.ModeSymlink != 0:
+		return WalkSymlinks
+	default:
+		return WalkOther
+	}
+}
+
+// dirKey 返回目录在文件系统中的唯一标识，不支持 inode 的平台使用解析后的真实路径
+func dirKey(dir Entry) (string, error) {
+	info, err := os.Stat(dir.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get directory info '%s': %w", dir.Path, err)
+	}
+	if key, _, ok := fileInode(info); ok {
+		return fmt.Sprintf("%d:%d", key.dev, key.ino), nil
+	}
+	resolved, err := filepath.EvalSymlinks(dir.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve directory '%s': %w", dir.Path, err)
+	}
+	return resolved, nil
+}
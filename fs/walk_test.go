@@ -0,0 +1,214 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// setupWalkTree 创建遍历测试目录
+func setupWalkTree(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	writeSyncFile(t, filepath.Join(root, "a.txt"), "a")
+	writeSyncFile(t, filepath.Join(root, ".hidden"), "h")
+	writeSyncFile(t, filepath.Join(root, ".git", "config"), "c")
+	writeSyncFile(t, filepath.Join(root, "sub", "b.txt"), "b")
+	writeSyncFile(t, filepath.Join(root, "sub", "deep", "c.txt"), "c")
+	return root
+}
+
+// collectWalk 收集遍历结果的相对路径（使用 / 分隔），遇到错误时终止测试
+func collectWalk(t *testing.T, root string, opts *WalkOptions) []string {
+	t.Helper()
+
+	var rels []string
+	for entry, err := range Walk(root, opts) {
+		if err != nil {
+			t.Fatalf("Walk failed: %v", err)
+		}
+		rels = append(rels, filepath.ToSlash(entry.Rel))
+	}
+	return rels
+}
+
+func TestWalk(t *testing.T) {
+	root := setupWalkTree(t)
+
+	tests := []struct {
+		name string
+		opts *WalkOptions
+		want []string
+	}{
+		{
+			name: "all entries sorted",
+			opts: &WalkOptions{Sorted: true},
+			want: []string{".git", ".git/config", ".hidden", "a.txt", "sub", "sub/b.txt", "sub/deep", "sub/deep/c.txt"},
+		},
+		{
+			name: "files only",
+			opts: &WalkOptions{Types: WalkFiles, Sorted: true},
+			want: []string{".git/config", ".hidden", "a.txt", "sub/b.txt", "sub/deep/c.txt"},
+		},
+		{
+			name: "dirs only",
+			opts: &WalkOptions{Types: WalkDirs, Sorted: true},
+			want: []string{".git", "sub", "sub/deep"},
+		},
+		{
+			name: "skip hidden",
+			opts: &WalkOptions{SkipHidden: true, Sorted: true},
+			want: []string{"a.txt", "sub", "sub/b.txt", "sub/deep", "sub/deep/c.txt"},
+		},
+		{
+			name: "max depth",
+			opts: &WalkOptions{MaxDepth: 2, SkipHidden: true, Sorted: true},
+			want: []string{"a.txt", "sub", "sub/b.txt", "sub/deep"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collectWalk(t, root, tt.opts); !slices.Equal(got, tt.want) {
+				t.Errorf("Walk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkUnsorted(t *testing.T) {
+	root := setupWalkTree(t)
+
+	got := collectWalk(t, root, nil)
+	want := collectWalk(t, root, &WalkOptions{Sorted: true})
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("Unsorted walk = %v, want %v", got, want)
+	}
+
+	// 不排序时先返回当前目录的条目再进入子目录
+	got = collectWalk(t, root, &WalkOptions{SkipHidden: true})
+	if len(got) != 5 || got[len(got)-1] != "sub/deep/c.txt" {
+		t.Errorf("Unexpected unsorted order: %v", got)
+	}
+
+	// 根路径为文件时只返回该文件
+	entries := collectWalk(t, filepath.Join(root, "a.txt"), nil)
+	if !slices.Equal(entries, []string{"."}) {
+		t.Errorf("Walk(file) = %v, want [.]", entries)
+	}
+}
+
+func TestWalkEntry(t *testing.T) {
+	root := setupWalkTree(t)
+
+	for entry, err := range Walk(root, &WalkOptions{Types: WalkFiles, SkipHidden: true}) {
+		if err != nil {
+			t.Fatalf("Walk failed: %v", err)
+		}
+		if entry.Path != filepath.Join(root, entry.Rel) {
+			t.Errorf("Path = %s, want %s", entry.Path, filepath.Join(root, entry.Rel))
+		}
+		if want := strings.Count(entry.Rel, string(filepath.Separator)) + 1; entry.Depth != want {
+			t.Errorf("%s: Depth = %d, want %d", entry.Rel, entry.Depth, want)
+		}
+		info, err := entry.Info()
+		if err != nil || info.Size() != 1 {
+			t.Errorf("%s: Info() = %v, %v", entry.Rel, info, err)
+		}
+	}
+
+	// break 后不再继续遍历
+	count := 0
+	for range Walk(root, nil) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 entry before break, got %d", count)
+	}
+}
+
+func TestWalkErrors(t *testing.T) {
+	for _, err := range Walk("", nil) {
+		if err == nil {
+			t.Error("Expected error for empty root")
+		}
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	var errs int
+	for _, err := range Walk(missing, nil) {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("Expected 1 error for missing root, got %d", errs)
+	}
+}
+
+func TestWalkFollowSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Symlinks require privileges on Windows")
+	}
+	root := setupWalkTree(t)
+	target := t.TempDir()
+	writeSyncFile(t, filepath.Join(target, "linked.txt"), "l")
+
+	if err := os.Symlink(target, filepath.Join(root, "link")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+	if err := os.Symlink("missing", filepath.Join(root, "broken")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+
+	// 默认不跟随符号链接
+	got := collectWalk(t, root, &WalkOptions{Types: WalkFiles | WalkSymlinks, SkipHidden: true, Sorted: true})
+	want := []string{"a.txt", "broken", "link", "sub/b.txt", "sub/deep/c.txt"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Walk() = %v, want %v", got, want)
+	}
+
+	// 跟随符号链接时进入链接目录，无法解析的链接按符号链接返回
+	got = collectWalk(t, root, &WalkOptions{Types: WalkFiles | WalkSymlinks, SkipHidden: true, Sorted: true, FollowSymlinks: true})
+	want = []string{"a.txt", "broken", "link/linked.txt", "sub/b.txt", "sub/deep/c.txt"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Walk(FollowSymlinks) = %v, want %v", got, want)
+	}
+
+	// 指向祖先目录的链接形成循环
+	if err := os.Symlink("..", filepath.Join(root, "sub", "a-loop")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+	var paths []string
+	var errs int
+	for entry, err := range Walk(root, &WalkOptions{Types: WalkFiles, SkipHidden: true, Sorted: true, FollowSymlinks: true}) {
+		if err != nil {
+			errs++
+			continue
+		}
+		paths = append(paths, filepath.ToSlash(entry.Rel))
+	}
+	if errs != 1 || !slices.Equal(paths, []string{"a.txt", "link/linked.txt"}) {
+		t.Errorf("Expected walk to stop at loop, got %v with %d errors", paths, errs)
+	}
+
+	// ContinueOnError 时跳过循环继续遍历
+	paths, errs = nil, 0
+	for entry, err := range Walk(root, &WalkOptions{Types: WalkFiles, SkipHidden: true, Sorted: true, FollowSymlinks: true, ContinueOnError: true}) {
+		if err != nil {
+			errs++
+			continue
+		}
+		paths = append(paths, filepath.ToSlash(entry.Rel))
+	}
+	want = []string{"a.txt", "link/linked.txt", "sub/b.txt", "sub/deep/c.txt"}
+	if errs != 1 || !slices.Equal(paths, want) {
+		t.Errorf("Walk(ContinueOnError) = %v with %d errors, want %v with 1 error", paths, errs, want)
+	}
+}
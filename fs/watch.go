@@ -0,0 +1,392 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrWatchOverflow 表示内核事件队列溢出，部分事件已丢失，调用方应重新扫描被监视的目录
+var ErrWatchOverflow = errors.New("watch event queue overflow")
+
+// errNativeWatchUnsupported 表示当前平台没有原生的文件监视机制，需要回退到轮询（仅内部使用）
+var errNativeWatchUnsupported = errors.New("native watch not supported")
+
+const (
+	defaultPollInterval = time.Second // 默认轮询间隔
+	watchMaxDelay       = 10          // 持续有事件时最长延迟 Debounce 的倍数
+	watchEventBuffer    = 64          // 事件通道的缓冲大小
+)
+
+// WatchOp 文件变化类型，可以组合使用（合并后的事件可能包含多个类型）
+type WatchOp uint32
+
+const (
+	WatchCreate WatchOp = 1 << iota // 创建（包括移入被监视的目录）
+	WatchWrite                      // 内容修改
+	WatchRemove                     // 删除
+	WatchRename                     // 重命名或移出被监视的目录（报告原路径）
+	WatchChmod                      // 权限、所有者、时间戳等属性变化
+
+	WatchAll = WatchCreate | WatchWrite | WatchRemove | WatchRename | WatchChmod // 所有类型
+)
+
+// watchOpNames 变化类型名称，按位顺序排列
+var watchOpNames = []string{"create", "write", "remove", "rename", "chmod"}
+
+// String 返回以 | 连接的变化类型名称
+func (op WatchOp) String() string {
+	if op == 0 {
+		return "none"
+	}
+	var names []string
+	for i, name := range watchOpNames {
+		if op&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// Has 是否包含指定的变化类型
+func (op WatchOp) Has(o WatchOp) bool {
+	return op&o != 0
+}
+
+// WatchEvent 文件变化事件
+type WatchEvent struct {
+	Path string  // 发生变化的路径（以监视的根路径为前缀）
+	Op   WatchOp // 变化类型
+}
+
+// String 返回事件的文本表示
+func (e WatchEvent) String() string {
+	return fmt.Sprintf("%s %s", e.Op, e.Path)
+}
+
+// WatchOptions 文件监视选项
+type WatchOptions struct {
+	// Recursive 递归监视子目录，之后新建或移入的子目录自动加入监视
+	Recursive bool
+
+	// Include/Exclude 过滤规则与 CopyOptions 相同（语法见 Match），相对路径以监视的根目录为基准
+	// Include 非空时只报告匹配的路径；匹配 Exclude 的路径不报告，匹配的目录不会被监视
+	Include []string
+	Exclude []string
+
+	// Ops 报告的变化类型，0 表示报告所有类型
+	Ops WatchOp
+
+	// Debounce 防抖时间，大于 0 时同一路径的多个事件合并为一个，在 Debounce 时间内没有新事件后发送
+	// 持续有事件时最长延迟 10 倍 Debounce；创建后在同一窗口内又被删除的路径不会报告
+	Debounce time.Duration
+
+	// Poll 强制使用轮询（如网络文件系统），不支持 inotify 的平台总是使用轮询
+	Poll bool
+
+	// PollInterval 轮询间隔，0 表示 1 秒
+	PollInterval time.Duration
+}
+
+// Watcher 文件监视器，必须持续读取 Events 和 Errors，直到调用 Close
+type Watcher struct {
+	Events <-chan WatchEvent // 变化事件，Close 后关闭
+	Errors <-chan error      // 监视过程中的错误（如 ErrWatchOverflow），Close 后关闭
+
+	events chan WatchEvent
+	errors chan error
+	raw    chan WatchEvent // 后端产生的原始事件，由 run 过滤、合并后发送
+
+	opts WatchOptions
+	root string // 监视的根目录绝对路径
+	only string // 监视单个文件时的文件名，此时只监视根目录本身
+
+	done         chan struct{}
+	closeOnce    sync.Once
+	closeErr     error
+	closeBackend func() error
+	wg           sync.WaitGroup
+}
+
+// Watch 监视文件或目录的变化，Linux 上使用 inotify，其他平台或 Poll 为 true 时定期轮询
+// 监视单个文件时实际监视其所在目录，文件被替换（如编辑器保存时重命名临时文件）后仍能继续收到事件
+//
+// 参数:
+//   - root: 需要监视的文件或目录
+//   - opts: 监视选项，为 nil 时使用默认选项（不递归、不防抖）
+//
+// 返回:
+//   - *Watcher: 监视器，使用完毕后必须调用 Close
+//   - error: 路径不存在、过滤模式无效或初始化监视失败时返回错误
+//
+// 示例:
+//
+//	w, err := fs.Watch("conf", &fs.WatchOptions{Recursive: true, Include: []string{"*.yaml"}, Debounce: 200 * time.Millisecond})
+//	if err != nil {
+//	    return err
+//	}
+//	defer w.Close()
+//	for {
+//	    select {
+//	    case ev, ok := <-w.Events:
+//	        if !ok {
+//	            return nil
+//	        }
+//	        fmt.Println(ev.Op, ev.Path)
+//	    case err, ok := <-w.Errors:
+//	        if !ok {
+//	            return nil
+//	        }
+//	        log.Println(err)
+//	    }
+//	}
+func Watch(root string, opts *WatchOptions) (*Watcher, error) {
+	if root == "" {
+		return nil, fmt.Errorf("watch root cannot be empty")
+	}
+	if opts == nil {
+		opts = &WatchOptions{}
+	}
+	filters := &CopyOptions{Include: opts.Include, Exclude: opts.Exclude}
+	if err := filters.validate(); err != nil {
+		return nil, err
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for '%s': %w", root, err)
+	}
+	info, err := os.Stat(absRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get path info '%s': %w", absRoot, err)
+	}
+
+	events := make(chan WatchEvent, watchEventBuffer)
+	errs := make(chan error, 1)
+	w := &Watcher{
+		Events: events,
+		Errors: errs,
+		events: events,
+		errors: errs,
+		raw:    make(chan WatchEvent, watchEventBuffer),
+		opts:   *opts,
+		root:   absRoot,
+		done:   make(chan struct{}),
+	}
+	if w.opts.PollInterval <= 0 {
+		w.opts.PollInterval = defaultPollInterval
+	}
+	if !info.IsDir() {
+		w.root, w.only = filepath.Dir(absRoot), filepath.Base(absRoot)
+		w.opts.Recursive = false
+	}
+
+	err = errNativeWatchUnsupported
+	if !w.opts.Poll {
+		err = startNativeWatch(w)
+	}
+	if errors.Is(err, errNativeWatchUnsupported) {
+		err = startPollWatch(w)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+// Close 停止监视并关闭 Events 和 Errors 通道，尚未发送的防抖事件被丢弃；重复调用返回相同的结果
+//
+// 返回:
+//   - error: 释放监视资源失败时返回错误
+func (w *Watcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.closeErr = w.closeBackend()
+		w.wg.Wait()
+		close(w.events)
+		close(w.errors)
+	})
+	return w.closeErr
+}
+
+// send 过滤后端产生的事件并交给 run 处理
+//
+// 参数:
+//   - path: 发生变化的路径
+//   - op: 变化类型
+//
+// 返回:
+//   - bool: false 表示监视器已关闭，后端应停止
+func (w *Watcher) send(path string, op WatchOp) bool {
+	if !w.accept(path, op) {
+		return true
+	}
+	select {
+	case w.raw <- WatchEvent{Path: path, Op: op}:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+// fail 报告错误
+//
+// 返回:
+//   - bool: false 表示监视器已关闭，后端应停止
+func (w *Watcher) fail(err error) bool {
+	select {
+	case w.errors <- err:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+// accept 判断事件是否需要报告
+func (w *Watcher) accept(path string, op WatchOp) bool {
+	if w.opts.Ops != 0 && op&w.opts.Ops == 0 {
+		return false
+	}
+	rel, ok := w.rel(path)
+	if !ok {
+		return false
+	}
+	if w.only != "" {
+		return rel == w.only
+	}
+	if rel == "." {
+		return true
+	}
+	if matchAny(w.opts.Exclude, rel) {
+		return false
+	}
+	return len(w.opts.Include) == 0 || matchAny(w.opts.Include, rel)
+}
+
+// rel 返回相对根目录的路径
+func (w *Watcher) rel(path string) (string, bool) {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// watchDir 判断目录是否需要监视（递归模式下被 Exclude 匹配的子目录不监视）
+func (w *Watcher) watchDir(path string) bool {
+	rel, ok := w.rel(path)
+	if !ok {
+		return false
+	}
+	return rel == "." || !matchAny(w.opts.Exclude, rel)
+}
+
+// run 接收后端事件，按 Debounce 合并后发送到 Events
+func (w *Watcher) run() {
+	defer w.wg.Done()
+
+	if w.opts.Debounce <= 0 {
+		for {
+			select {
+			case ev := <-w.raw:
+				if !w.deliver(ev) {
+					return
+				}
+			case <-w.done:
+				return
+			}
+		}
+	}
+
+	var (
+		pending []WatchEvent
+		index   = make(map[string]int)
+		first   time.Time // 当前窗口中第一个事件的时间
+		timer   = time.NewTimer(0)
+		timerC  <-chan time.Time
+	)
+	<-timer.C
+	defer timer.Stop()
+
+	for {
+		select {
+		case ev := <-w.raw:
+			now := time.Now()
+			if len(pending) == 0 {
+				first = now
+			}
+			pending = coalesceEvent(pending, index, ev)
+
+			wait := w.opts.Debounce
+			if deadline := first.Add(watchMaxDelay * w.opts.Debounce); now.Add(wait).After(deadline) {
+				wait = deadline.Sub(now)
+			}
+			timer.Stop()
+			select {
+			case <-timer.C:
+			default:
+			}
+			timer.Reset(wait)
+			timerC = timer.C
+
+		case <-timerC:
+			timerC = nil
+			batch := pending
+			pending = nil
+			clear(index)
+			for _, ev := range batch {
+				if ev.Op != 0 && !w.deliver(ev) {
+					return
+				}
+			}
+
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// coalesceEvent 将事件合并到待发送列表：同一路径的变化类型按位合并，创建后又被删除的路径从列表中移除
+//
+// 参数:
+//   - pending: 待发送的事件（按首次出现的顺序，Op 为 0 表示已移除）
+//   - index: 路径到 pending 下标的映射
+//   - ev: 新事件
+//
+// 返回:
+//   - []WatchEvent: 合并后的待发送事件
+func coalesceEvent(pending []WatchEvent, index map[string]int, ev WatchEvent) []WatchEvent {
+	i, ok := index[ev.Path]
+	if !ok {
+		index[ev.Path] = len(pending)
+		return append(pending, ev)
+	}
+	if pending[i].Op.Has(WatchCreate) && ev.Op.Has(WatchRemove) {
+		// 临时文件：窗口内创建又删除，不报告
+		pending[i].Op = 0
+		delete(index, ev.Path)
+		return pending
+	}
+	pending[i].Op |= ev.Op
+	return pending
+}
+
+// deliver 发送事件到 Events
+//
+// 返回:
+//   - bool: false 表示监视器已关闭
+func (w *Watcher) deliver(ev WatchEvent) bool {
+	select {
+	case w.events <- ev:
+		return true
+	case <-w.done:
+		return false
+	}
+}
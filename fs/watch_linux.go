@@ -0,0 +1,213 @@
+//go:build linux
+
+package fs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotifyMask 监视目录时关注的事件
+const inotifyMask = unix.IN_CREATE | unix.IN_MODIFY | unix.IN_ATTRIB | unix.IN_DELETE | unix.IN_DELETE_SELF |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_MOVE_SELF | unix.IN_EXCL_UNLINK
+
+// inotifyWatch inotify 后端的状态，初始化完成后只由读取协程访问
+type inotifyWatch struct {
+	w     *Watcher
+	f     *os.File // 用于读取事件和关闭，不能调用 Fd（会将描述符切换为阻塞模式）
+	fd    int
+	wds   map[int]string // 监视描述符到目录路径的映射
+	paths map[string]int // 目录路径到监视描述符的映射
+}
+
+// startNativeWatch 启动 inotify 后端
+// inotify 描述符以非阻塞模式打开并交给 Go 运行时的网络轮询器，Close 时读取协程立即返回
+//
+// 参数:
+//   - w: 监视器
+//
+// 返回:
+//   - error: 创建 inotify 实例或添加初始监视失败时返回错误（如超出 fs.inotify.max_user_watches）
+func startNativeWatch(w *Watcher) error {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return fmt.Errorf("failed to initialize inotify: %w", err)
+	}
+	iw := &inotifyWatch{
+		w:     w,
+		f:     os.NewFile(uintptr(fd), "inotify"),
+		fd:    fd,
+		wds:   make(map[int]string),
+		paths: make(map[string]int),
+	}
+	if err := iw.addDir(w.root, false); err != nil {
+		_ = iw.f.Close()
+		return err
+	}
+
+	w.closeBackend = iw.f.Close
+	w.wg.Add(1)
+	go iw.read()
+	return nil
+}
+
+// addDir 监视目录，递归模式下同时监视其中的子目录
+//
+// 参数:
+//   - dir: 目录路径
+//   - emit: 是否为目录中已有的条目发送创建事件（新建的目录在加入监视前可能已有内容）
+//
+// 返回:
+//   - error: 添加监视或读取目录失败时返回错误，目录已不存在时返回 nil
+func (iw *inotifyWatch) addDir(dir string, emit bool) error {
+	mask := uint32(inotifyMask)
+	if dir != iw.w.root {
+		// 子目录不跟随符号链接，根目录为符号链接时监视其指向的目录
+		mask |= unix.IN_DONT_FOLLOW | unix.IN_ONLYDIR
+	}
+	// 关闭后描述符可能已被复用
+	select {
+	case <-iw.w.done:
+		return nil
+	default:
+	}
+	wd, err := unix.InotifyAddWatch(iw.fd, dir, mask)
+	if err != nil {
+		if dir != iw.w.root && (errors.Is(err, unix.ENOENT) || errors.Is(err, unix.ENOTDIR)) {
+			return nil
+		}
+		return fmt.Errorf("failed to watch '%s': %w", dir, err)
+	}
+	iw.wds[wd] = dir
+	iw.paths[dir] = wd
+
+	if !iw.w.opts.Recursive && !emit {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read directory '%s': %w", dir, err)
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if emit && !iw.w.send(path, WatchCreate) {
+			return nil
+		}
+		if iw.w.opts.Recursive && entry.IsDir() && iw.w.watchDir(path) {
+			if err := iw.addDir(path, emit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// removeDir 移除目录及其子目录的监视（目录被移走后原路径失效）
+func (iw *inotifyWatch) removeDir(dir string) {
+	prefix := dir + string(filepath.Separator)
+	for path, wd := range iw.paths {
+		if path == dir || strings.HasPrefix(path, prefix) {
+			_, _ = unix.InotifyRmWatch(iw.fd, uint32(wd))
+			delete(iw.paths, path)
+			delete(iw.wds, wd)
+		}
+	}
+}
+
+// read 读取并处理 inotify 事件，直到描述符被关闭
+func (iw *inotifyWatch) read() {
+	defer iw.w.wg.Done()
+
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := iw.f.Read(buf)
+		if err != nil {
+			if errors.Is(err, os.ErrClosed) {
+				return
+			}
+			iw.w.fail(fmt.Errorf("failed to read inotify events: %w", err))
+			return
+		}
+
+		for off := 0; off+unix.SizeofInotifyEvent <= n; {
+			wd := int(int32(binary.NativeEndian.Uint32(buf[off:])))
+			mask := binary.NativeEndian.Uint32(buf[off+4:])
+			nameLen := int(binary.NativeEndian.Uint32(buf[off+12:]))
+			off += unix.SizeofInotifyEvent
+			name := string(bytes.TrimRight(buf[off:off+nameLen], "\x00"))
+			off += nameLen
+
+			if !iw.handle(wd, mask, name) {
+				return
+			}
+		}
+	}
+}
+
+// handle 将 inotify 事件转换为监视事件，新建或移入的目录在递归模式下加入监视
+//
+// 返回:
+//   - bool: false 表示监视器已关闭
+func (iw *inotifyWatch) handle(wd int, mask uint32, name string) bool {
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		return iw.w.fail(ErrWatchOverflow)
+	}
+	dir, ok := iw.wds[wd]
+	if !ok {
+		return true
+	}
+	if mask&unix.IN_IGNORED != 0 {
+		// 目录已被删除或监视已被移除
+		delete(iw.wds, wd)
+		if iw.paths[dir] == wd {
+			delete(iw.paths, dir)
+		}
+		return true
+	}
+
+	path := dir
+	if name != "" {
+		path = filepath.Join(dir, name)
+	}
+	isDir := mask&unix.IN_ISDIR != 0
+
+	switch {
+	case mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+		if !iw.w.send(path, WatchCreate) {
+			return false
+		}
+		if isDir && iw.w.opts.Recursive && iw.w.watchDir(path) {
+			if err := iw.addDir(path, true); err != nil {
+				return iw.w.fail(err)
+			}
+		}
+		return true
+	case mask&unix.IN_MODIFY != 0:
+		return iw.w.send(path, WatchWrite)
+	case mask&unix.IN_ATTRIB != 0:
+		return iw.w.send(path, WatchChmod)
+	case mask&unix.IN_DELETE != 0:
+		return iw.w.send(path, WatchRemove)
+	case mask&unix.IN_MOVED_FROM != 0:
+		if isDir {
+			iw.removeDir(path)
+		}
+		return iw.w.send(path, WatchRename)
+	case mask&unix.IN_DELETE_SELF != 0 && dir == iw.w.root:
+		// 子目录的删除已由父目录的 IN_DELETE 报告
+		return iw.w.send(path, WatchRemove)
+	case mask&unix.IN_MOVE_SELF != 0 && dir == iw.w.root:
+		return iw.w.send(path, WatchRename)
+	}
+	return true
+}
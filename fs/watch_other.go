@@ -0,0 +1,8 @@
+//go:build !linux
+
+package fs
+
+// startNativeWatch 当前平台不支持 inotify，回退到轮询
+func startNativeWatch(w *Watcher) error {
+	return errNativeWatchUnsupported
+}
@@ -0,0 +1,156 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// pollEntry 轮询快照中单个路径的状态
+type pollEntry struct {
+	modTime time.Time
+	size    int64
+	mode    os.FileMode
+}
+
+// startPollWatch 启动轮询后端：定期扫描被监视的路径，与上一次的快照比较产生事件
+//
+// 参数:
+//   - w: 监视器
+//
+// 返回:
+//   - error: 首次扫描失败时返回错误
+func startPollWatch(w *Watcher) error {
+	prev, err := w.pollScan()
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	w.closeBackend = func() error {
+		close(stop)
+		return nil
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.opts.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			cur, err := w.pollScan()
+			if err != nil {
+				if !w.fail(err) {
+					return
+				}
+				continue
+			}
+			if !w.pollDiff(prev, cur) {
+				return
+			}
+			prev = cur
+		}
+	}()
+	return nil
+}
+
+// pollScan 扫描被监视的路径，根目录不存在时返回空快照
+//
+// 返回:
+//   - map[string]pollEntry: 路径到状态的映射（不包含根目录本身）
+//   - error: 读取目录失败时返回错误
+func (w *Watcher) pollScan() (map[string]pollEntry, error) {
+	snapshot := make(map[string]pollEntry)
+	if w.only != "" {
+		path := filepath.Join(w.root, w.only)
+		info, err := os.Lstat(path)
+		if err == nil {
+			snapshot[path] = pollEntry{modTime: info.ModTime(), size: info.Size(), mode: info.Mode()}
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to get file info '%s': %w", path, err)
+		}
+		return snapshot, nil
+	}
+
+	var scan func(dir string) error
+	scan = func(dir string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			// 扫描期间被删除的目录按不存在处理
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to read directory '%s': %w", dir, err)
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			info, err := entry.Info()
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return fmt.Errorf("failed to get file info '%s': %w", path, err)
+			}
+			snapshot[path] = pollEntry{modTime: info.ModTime(), size: info.Size(), mode: info.Mode()}
+			if w.opts.Recursive && entry.IsDir() && w.watchDir(path) {
+				if err := scan(path); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return snapshot, scan(w.root)
+}
+
+// pollDiff 比较两次快照并发送事件（按路径排序）
+//
+// 返回:
+//   - bool: false 表示监视器已关闭
+func (w *Watcher) pollDiff(prev, cur map[string]pollEntry) bool {
+	var events []WatchEvent
+	for path, c := range cur {
+		p, ok := prev[path]
+		switch {
+		case !ok:
+			events = append(events, WatchEvent{Path: path, Op: WatchCreate})
+		case p.mode.Type() != c.mode.Type():
+			// 类型变化（如文件被替换为目录）视为删除后重新创建
+			events = append(events, WatchEvent{Path: path, Op: WatchRemove | WatchCreate})
+		default:
+			var op WatchOp
+			// 目录的修改时间随其中的条目变化，条目本身的事件已单独报告
+			if !c.mode.IsDir() && (!p.modTime.Equal(c.modTime) || p.size != c.size) {
+				op |= WatchWrite
+			}
+			if p.mode != c.mode {
+				op |= WatchChmod
+			}
+			if op != 0 {
+				events = append(events, WatchEvent{Path: path, Op: op})
+			}
+		}
+	}
+	for path := range prev {
+		if _, ok := cur[path]; !ok {
+			events = append(events, WatchEvent{Path: path, Op: WatchRemove})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	for _, ev := range events {
+		if !w.send(ev.Path, ev.Op) {
+			return false
+		}
+	}
+	return true
+}
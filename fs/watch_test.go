@@ -0,0 +1,225 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// watchBackends 测试使用的监视后端，不支持 inotify 的平台两者都使用轮询
+var watchBackends = []struct {
+	name string
+	poll bool
+}{
+	{"Native", false},
+	{"Poll", true},
+}
+
+// startWatch 启动监视器，测试结束时关闭
+func startWatch(t *testing.T, root string, opts *WatchOptions) *Watcher {
+	t.Helper()
+
+	if opts.Poll {
+		opts.PollInterval = 20 * time.Millisecond
+	}
+	w, err := Watch(root, opts)
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Close() })
+	return w
+}
+
+// waitEvent 等待指定路径上包含 op 的事件，返回此前收到的所有事件
+func waitEvent(t *testing.T, w *Watcher, path string, op WatchOp) []WatchEvent {
+	t.Helper()
+
+	var seen []WatchEvent
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-w.Events:
+			seen = append(seen, ev)
+			if ev.Path == path && ev.Op.Has(op) {
+				return seen
+			}
+		case err := <-w.Errors:
+			t.Fatalf("watch error: %v", err)
+		case <-timeout:
+			t.Fatalf("timed out waiting for %v on %s, got %v", op, path, seen)
+		}
+	}
+}
+
+// tick 等待足够长的时间，使轮询后端能区分先后两次修改
+func tick(poll bool) {
+	if poll {
+		time.Sleep(60 * time.Millisecond)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	for _, backend := range watchBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			root := t.TempDir()
+			w := startWatch(t, root, &WatchOptions{Recursive: true, Poll: backend.poll})
+
+			file := filepath.Join(root, "a.txt")
+			writeSyncFile(t, file, "a")
+			waitEvent(t, w, file, WatchCreate)
+
+			tick(backend.poll)
+			writeSyncFile(t, file, "changed")
+			waitEvent(t, w, file, WatchWrite)
+
+			// 新建的子目录自动加入监视
+			sub := filepath.Join(root, "sub", "deep")
+			if err := os.MkdirAll(sub, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			waitEvent(t, w, sub, WatchCreate)
+			nested := filepath.Join(sub, "b.txt")
+			writeSyncFile(t, nested, "b")
+			waitEvent(t, w, nested, WatchCreate)
+
+			if err := os.Remove(file); err != nil {
+				t.Fatal(err)
+			}
+			waitEvent(t, w, file, WatchRemove)
+		})
+	}
+}
+
+func TestWatchFilters(t *testing.T) {
+	for _, backend := range watchBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.Mkdir(filepath.Join(root, "vendor"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			w := startWatch(t, root, &WatchOptions{
+				Recursive: true,
+				Include:   []string{"**/*.go"},
+				Exclude:   []string{"vendor"},
+				Ops:       WatchCreate,
+				Poll:      backend.poll,
+			})
+
+			writeSyncFile(t, filepath.Join(root, "a.txt"), "a")
+			writeSyncFile(t, filepath.Join(root, "vendor", "x.go"), "x")
+			tick(backend.poll)
+			last := filepath.Join(root, "pkg", "b.go")
+			writeSyncFile(t, last, "b")
+
+			for _, ev := range waitEvent(t, w, last, WatchCreate) {
+				if ev.Path != last || ev.Op != WatchCreate {
+					t.Errorf("unexpected event %v", ev)
+				}
+			}
+		})
+	}
+
+	if _, err := Watch(t.TempDir(), &WatchOptions{Include: []string{"[z-"}}); err == nil {
+		t.Error("Watch with bad pattern: expected error")
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	for _, backend := range watchBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			root := t.TempDir()
+			config := filepath.Join(root, "app.conf")
+			writeSyncFile(t, config, "v1")
+			w := startWatch(t, config, &WatchOptions{Poll: backend.poll})
+
+			// 同目录中的其他文件不报告
+			writeSyncFile(t, filepath.Join(root, "other.txt"), "x")
+			tick(backend.poll)
+
+			// 编辑器式保存：写入临时文件后重命名覆盖
+			if err := WriteFileAtomic(config, []byte("version 2"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			for _, ev := range waitEvent(t, w, config, WatchCreate|WatchWrite) {
+				if ev.Path != config {
+					t.Errorf("unexpected event %v", ev)
+				}
+			}
+
+			// 替换后继续收到事件
+			tick(backend.poll)
+			writeSyncFile(t, config, "version three")
+			waitEvent(t, w, config, WatchWrite)
+		})
+	}
+}
+
+func TestWatchDebounce(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("debounce timing test requires inotify")
+	}
+	root := t.TempDir()
+	w := startWatch(t, root, &WatchOptions{Debounce: 100 * time.Millisecond})
+
+	file := filepath.Join(root, "a.txt")
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := f.WriteString("x"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = f.Close()
+	// 窗口内创建又删除的临时文件不报告
+	tmp := filepath.Join(root, "a.txt.tmp")
+	writeSyncFile(t, tmp, "t")
+	if err := os.Remove(tmp); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := waitEvent(t, w, file, WatchCreate)
+	if len(seen) != 1 || seen[0].Op != WatchCreate|WatchWrite {
+		t.Errorf("events = %v, want single create|write", seen)
+	}
+	select {
+	case ev := <-w.Events:
+		t.Errorf("unexpected event after debounce: %v", ev)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestWatchClose(t *testing.T) {
+	w, err := Watch(t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if _, ok := <-w.Events; ok {
+		t.Error("Events not closed")
+	}
+	if _, ok := <-w.Errors; ok {
+		t.Error("Errors not closed")
+	}
+
+	if _, err := Watch(filepath.Join(t.TempDir(), "missing"), nil); err == nil {
+		t.Error("Watch of missing path: expected error")
+	}
+}
+
+func TestWatchOpString(t *testing.T) {
+	if got := (WatchCreate | WatchWrite).String(); got != "create|write" {
+		t.Errorf("String = %q, want %q", got, "create|write")
+	}
+	if got := WatchOp(0).String(); got != "none" {
+		t.Errorf("String = %q, want %q", got, "none")
+	}
+}